@@ -5,7 +5,7 @@ go 1.25.4
 require (
 	github.com/BurntSushi/toml v1.5.0
 	github.com/go-ozzo/ozzo-validation v3.6.0+incompatible
-	github.com/gorilla/securecookie v1.1.2 // indirect
+	github.com/gorilla/securecookie v1.1.2
 )
 
 require (
@@ -15,20 +15,18 @@ require (
 	golang.org/x/sys v0.40.0 // indirect
 	golang.org/x/text v0.33.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20260120221211-b8f7ae30c516 // indirect
-	google.golang.org/grpc v1.80.0 // indirect
+	google.golang.org/grpc v1.80.0
 	google.golang.org/protobuf v1.36.11 // indirect
 )
 
-require (
-	github.com/grpc-ecosystem/go-grpc-middleware/v2 v2.3.3 // indirect
-	github.com/stretchr/testify v1.11.1 // indirect
-)
+require github.com/stretchr/testify v1.11.1 // indirect
 
 require (
 	github.com/google/uuid v1.6.0
 	github.com/gorilla/handlers v1.5.2
 	github.com/gorilla/mux v1.8.1
 	github.com/gorilla/sessions v1.4.0
+	github.com/grpc-ecosystem/go-grpc-middleware/v2 v2.3.3
 	github.com/lib/pq v1.10.9
 	github.com/qeery8/protos v0.0.1
 	github.com/sirupsen/logrus v1.9.3
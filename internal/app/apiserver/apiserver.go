@@ -1,13 +1,17 @@
 package apiserver
 
 import (
+	"context"
 	"database/sql"
 	"net/http"
 
 	"github.com/gorilla/sessions"
 	_ "github.com/lib/pq"
+	"github.com/qeery8/rest/internal/app/blob"
+	"github.com/qeery8/rest/internal/app/notify"
 	"github.com/qeery8/rest/internal/config"
 	"github.com/qeery8/rest/internal/store/sqlstore"
+	"github.com/sirupsen/logrus"
 )
 
 func Start(config *config.Config) error {
@@ -19,7 +23,24 @@ func Start(config *config.Config) error {
 	defer db.Close()
 	store := sqlstore.New(db)
 	sessionStore := sessions.NewCookieStore([]byte(config.SessionKey))
-	srv := newServer(store, sessionStore)
+	logger := logrus.New()
+
+	notifier, err := notify.New(config.Notifier, logger)
+	if err != nil {
+		return err
+	}
+
+	blobs, err := blob.New(config.Attachments)
+	if err != nil {
+		return err
+	}
+
+	srv := newServer(store, sessionStore, logger, notifier, blobs, config.Attachments)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv.configureJobs(config, notifier).Start(ctx)
 
 	return http.ListenAndServe(config.BindAddr, srv)
 }
@@ -0,0 +1,46 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qeery8/rest/internal/app/notify"
+	"github.com/qeery8/rest/internal/app/reminder"
+	"github.com/qeery8/rest/internal/app/scheduler"
+	"github.com/qeery8/rest/internal/config"
+)
+
+func (s *server) configureJobs(config *config.Config, notifier notify.Notifier) *scheduler.Scheduler {
+	jobs := scheduler.New(s.logger)
+
+	if config.Reminders.Enabled {
+		leads := make([]time.Duration, 0, len(config.Reminders.LeadTimes))
+		for _, l := range config.Reminders.LeadTimes {
+			leads = append(leads, l.Duration)
+		}
+
+		reminders := reminder.New(s.store, notifier, leads, s.logger)
+		jobs.Add("due_reminders", config.Reminders.Interval.Duration, reminders.Run)
+	}
+
+	if config.Archive.Enabled {
+		staleAfter := config.Archive.StaleAfter.Duration
+		reason := fmt.Sprintf("automatically abandoned: in progress for more than %v", staleAfter)
+
+		jobs.Add("abandon_stale_tasks", config.Archive.Interval.Duration, func(ctx context.Context) error {
+			n, err := s.store.Archive().AbandonStale(time.Now().Add(-staleAfter), reason)
+			if n > 0 {
+				s.logger.Infof("abandoned %d stale tasks", n)
+			}
+			return err
+		})
+	}
+
+	jobs.Add("expire_invitations", time.Hour, func(ctx context.Context) error {
+		_, err := s.store.Invitation().ExpireStale()
+		return err
+	})
+
+	return jobs
+}
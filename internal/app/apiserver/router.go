@@ -15,29 +15,154 @@ func (s *server) configureRouter() {
 	s.router.HandleFunc("/users", s.handlers.User.HandleUsersCreate()).Methods("POST")
 	//авторизация
 	s.router.HandleFunc("/sessions", s.handleSessionsCreate()).Methods("POST")
-	//создание команды
-	s.router.HandleFunc("/teams", s.handlers.Team.HandleTeamsCreate()).Methods("POST")
-	//создание задачи
-	s.router.HandleFunc("/task", s.handlers.Task.HandleTaskCreate()).Methods("POST")
 
 	//это типо приватные запросы, хуй знает как объяснить
 	private := s.router.PathPrefix("/private").Subrouter()
 	private.Use(s.authenticateUser)
 
+	//создание команды (владелец - текущий юзер)
+	private.HandleFunc("/teams", s.handlers.Team.HandleTeamsCreate()).Methods("POST")
+	//создание задачи (только в команде, где ты состоишь)
+	private.HandleFunc("/task", s.handlers.Task.HandleTaskCreate()).Methods("POST")
+
 	//для проверки авторизованного пользователя, те по запросу выдает инфу из бд о челе
 	private.HandleFunc("/whoami", s.handlers.User.HandlerWhoami()).Methods("GET")
 	//выдает инфу о команде по введенному id
 	private.HandleFunc("/team/{id}", s.handlers.Team.HandleTeamID()).Methods("GET")
 	//выдает инфу о командах в которых состоит юзер
 	private.HandleFunc("/team_user_id/{id}", s.handlers.Team.HandleTeamByUserID()).Methods("GET")
-	//показывает все задачи которые тебе присвоены
+	//мои открытые задачи (исполнитель или наблюдатель) по всем командам: просроченные, на сегодня, предстоящие, без срока
+	//tz задает часовой пояс для "сегодня", по умолчанию UTC
+	private.HandleFunc("/me/tasks", s.handlers.Task.HandleMyTasks()).Methods("GET")
+	//массовые действия над задачами: task_ids, action (set_status, set_priority, assign, add_label, delete, abandon)
+	//и его параметр, mode=all_or_nothing (по умолчанию) или best_effort; в ответе результат по каждой задаче
+	private.HandleFunc("/tasks/bulk", s.handlers.Bulk.HandleTaskBulk()).Methods("POST")
+	//показывает задачи из команд, в которых ты состоишь
+	//фильтры: status, priority, assignee_id, watcher_id, involved_id (или me), team_id, due_from, due_to, q, labels (label_match=all), sprint_id (или none),
+	//свои поля: field.<key>=a,b и диапазоны field.<key>.from / field.<key>.to
+	//сортировка: sort=created_at|due_date|priority (с минусом по убыванию), пагинация: limit, cursor
 	private.HandleFunc("/task/list", s.handlers.Task.HandleTaskList()).Methods("GET")
+	//выдает задачи команды
+	private.HandleFunc("/teams/{team_id}/tasks", s.handlers.Task.HandleTeamTaskList()).Methods("GET")
 	//показывает задачу по id
 	private.HandleFunc("/task/{task_id}", s.handlers.Task.HandleTaskGetID()).Methods("GET")
 
-	//добавляет юзера в команду
+	//выдает участников команды с их ролями
+	private.HandleFunc("/teams/{team_id}/members", s.handlers.Team.HandleTeamMembers()).Methods("GET")
+	//добавляет юзера в команду (роль в теле, по умолчанию member)
 	private.HandleFunc("/teams/{team_id}/members", s.handlers.Team.HandleTeamAddMembers()).Methods("POST")
-	//присваивает задачу юзеру
+	//создает задачу внутри команды
+	private.HandleFunc("/teams/{team_id}/tasks", s.handlers.Task.HandleTeamTaskCreate()).Methods("POST")
+	//приглашает в команду по почте (юзер может быть еще не зарегистрирован)
+	private.HandleFunc("/teams/{team_id}/invitations", s.handlers.Invitation.HandleInvitationCreate()).Methods("POST")
+	//выдает активные приглашения команды
+	private.HandleFunc("/teams/{team_id}/invitations", s.handlers.Invitation.HandleTeamInvitations()).Methods("GET")
+	//выдает приглашения текущего юзера
+	private.HandleFunc("/invitations", s.handlers.Invitation.HandleMyInvitations()).Methods("GET")
+	//принимает приглашение
+	private.HandleFunc("/invitations/{invitation_id}/accept", s.handlers.Invitation.HandleInvitationAccept()).Methods("POST")
+	//отклоняет приглашение
+	private.HandleFunc("/invitations/{invitation_id}/decline", s.handlers.Invitation.HandleInvitationDecline()).Methods("POST")
+	//отправляет задачу в архив заброшенных (причина в теле)
+	private.HandleFunc("/task/{task_id}/abandon", s.handlers.Archive.HandleTaskAbandon()).Methods("POST")
+	//выдает заброшенные задачи команды
+	private.HandleFunc("/teams/{team_id}/abandoned", s.handlers.Archive.HandleTeamAbandonedList()).Methods("GET")
+	//возвращает задачу из архива
+	private.HandleFunc("/teams/{team_id}/abandoned/{task_id}/restore", s.handlers.Archive.HandleTeamAbandonedRestore()).Methods("POST")
+	//комментарии к задаче: список деревом, создание (parent_id для ответа), правка, удаление, история правок
+	//упоминания пишутся как @почта и должны указывать на участника команды
+	private.HandleFunc("/task/{task_id}/comments", s.handlers.Comment.HandleCommentList()).Methods("GET")
+	private.HandleFunc("/task/{task_id}/comments", s.handlers.Comment.HandleCommentCreate()).Methods("POST")
+	private.HandleFunc("/task/{task_id}/comments/{comment_id}", s.handlers.Comment.HandleCommentUpdate()).Methods("PUT")
+	private.HandleFunc("/task/{task_id}/comments/{comment_id}", s.handlers.Comment.HandleCommentDelete()).Methods("DELETE")
+	private.HandleFunc("/task/{task_id}/comments/{comment_id}/history", s.handlers.Comment.HandleCommentHistory()).Methods("GET")
+	//история изменений задачи (limit, cursor)
+	private.HandleFunc("/task/{task_id}/history", s.handlers.Activity.HandleTaskHistory()).Methods("GET")
+	//лента активности команды (limit, cursor)
+	private.HandleFunc("/teams/{team_id}/activity", s.handlers.Activity.HandleTeamActivity()).Methods("GET")
+	//подзадачи: список, создание, перенос под другую задачу (parent_id: null - на верхний уровень)
+	private.HandleFunc("/task/{task_id}/subtasks", s.handlers.Subtask.HandleSubtaskList()).Methods("GET")
+	private.HandleFunc("/task/{task_id}/subtasks", s.handlers.Subtask.HandleSubtaskCreate()).Methods("POST")
+	private.HandleFunc("/task/{task_id}/parent", s.handlers.Subtask.HandleTaskSetParent()).Methods("PUT")
+	//чеклист задачи
+	private.HandleFunc("/task/{task_id}/checklist", s.handlers.Checklist.HandleChecklistList()).Methods("GET")
+	private.HandleFunc("/task/{task_id}/checklist", s.handlers.Checklist.HandleChecklistCreate()).Methods("POST")
+	private.HandleFunc("/task/{task_id}/checklist/{item_id}", s.handlers.Checklist.HandleChecklistUpdate()).Methods("PUT")
+	private.HandleFunc("/task/{task_id}/checklist/{item_id}", s.handlers.Checklist.HandleChecklistDelete()).Methods("DELETE")
+	//зависимости: кто блокирует задачу и кого блокирует она, добавление блокера (blocker_id), удаление связи
+	private.HandleFunc("/task/{task_id}/dependencies", s.handlers.Dependency.HandleDependencyList()).Methods("GET")
+	private.HandleFunc("/task/{task_id}/dependencies", s.handlers.Dependency.HandleDependencyAdd()).Methods("POST")
+	private.HandleFunc("/task/{task_id}/dependencies/{blocker_id}", s.handlers.Dependency.HandleDependencyRemove()).Methods("DELETE")
+	//процесс команды: статусы по порядку (первый - начальный) и разрешенные переходы между ними
+	private.HandleFunc("/teams/{team_id}/workflow", s.handlers.Workflow.HandleWorkflowGet()).Methods("GET")
+	private.HandleFunc("/teams/{team_id}/workflow", s.handlers.Workflow.HandleWorkflowUpdate()).Methods("PUT")
+	//доска команды: колонки по статусам процесса, карточки по rank
+	private.HandleFunc("/teams/{team_id}/board", s.handlers.Board.HandleBoard()).Methods("GET")
+	//переносит карточку: status (по умолчанию текущий) и место - before_id или after_id, без них в конец колонки
+	private.HandleFunc("/task/{task_id}/move", s.handlers.Board.HandleTaskMove()).Methods("POST")
+	//спринты команды: список, создание (даты YYYY-MM-DD), просмотр, правка
+	private.HandleFunc("/teams/{team_id}/sprints", s.handlers.Sprint.HandleSprintList()).Methods("GET")
+	private.HandleFunc("/teams/{team_id}/sprints", s.handlers.Sprint.HandleSprintCreate()).Methods("POST")
+	private.HandleFunc("/teams/{team_id}/sprints/{sprint_id}", s.handlers.Sprint.HandleSprintGet()).Methods("GET")
+	private.HandleFunc("/teams/{team_id}/sprints/{sprint_id}", s.handlers.Sprint.HandleSprintUpdate()).Methods("PUT")
+	//старт фиксирует задачи спринта как обязательства, закрытие переносит незавершенные в carry_over_to или в бэклог
+	private.HandleFunc("/teams/{team_id}/sprints/{sprint_id}/start", s.handlers.Sprint.HandleSprintStart()).Methods("POST")
+	private.HandleFunc("/teams/{team_id}/sprints/{sprint_id}/close", s.handlers.Sprint.HandleSprintClose()).Methods("POST")
+	private.HandleFunc("/teams/{team_id}/sprints/{sprint_id}/summary", s.handlers.Sprint.HandleSprintSummary()).Methods("GET")
+	//планирование: добавить задачи (task_ids) в спринт, вернуть задачу в бэклог
+	private.HandleFunc("/teams/{team_id}/sprints/{sprint_id}/tasks", s.handlers.Sprint.HandleSprintAddTasks()).Methods("POST")
+	private.HandleFunc("/teams/{team_id}/sprints/{sprint_id}/tasks/{task_id}", s.handlers.Sprint.HandleSprintRemoveTask()).Methods("DELETE")
+	//отчеты команды (from, to; format=csv для выгрузки): burndown по спринту (sprint_id) или периоду,
+	//время выполнения по приоритетам, закрытые задачи по неделям, открытые задачи по исполнителям
+	private.HandleFunc("/teams/{team_id}/reports/burndown", s.handlers.Report.HandleReportBurndown()).Methods("GET")
+	private.HandleFunc("/teams/{team_id}/reports/cycle-time", s.handlers.Report.HandleReportCycleTime()).Methods("GET")
+	private.HandleFunc("/teams/{team_id}/reports/throughput", s.handlers.Report.HandleReportThroughput()).Methods("GET")
+	private.HandleFunc("/teams/{team_id}/reports/workload", s.handlers.Report.HandleReportWorkload()).Methods("GET")
+	//учет времени по задаче: записи (minutes, note, work_date YYYY-MM-DD), править может только автор
+	private.HandleFunc("/task/{task_id}/worklogs", s.handlers.Worklog.HandleWorklogList()).Methods("GET")
+	private.HandleFunc("/task/{task_id}/worklogs", s.handlers.Worklog.HandleWorklogCreate()).Methods("POST")
+	private.HandleFunc("/task/{task_id}/worklogs/{worklog_id}", s.handlers.Worklog.HandleWorklogUpdate()).Methods("PUT")
+	private.HandleFunc("/task/{task_id}/worklogs/{worklog_id}", s.handlers.Worklog.HandleWorklogDelete()).Methods("DELETE")
+	//табель участника команды за период (user_id, по умолчанию me; from, to; format=csv)
+	private.HandleFunc("/teams/{team_id}/reports/timesheet", s.handlers.Worklog.HandleReportTimesheet()).Methods("GET")
+	//шаблоны задач команды: name_pattern с {date}, {week}, {month} и своими переменными, метки, чеклист, подзадачи
+	private.HandleFunc("/teams/{team_id}/templates", s.handlers.Template.HandleTemplateList()).Methods("GET")
+	private.HandleFunc("/teams/{team_id}/templates", s.handlers.Template.HandleTemplateCreate()).Methods("POST")
+	private.HandleFunc("/teams/{team_id}/templates/{template_id}", s.handlers.Template.HandleTemplateGet()).Methods("GET")
+	private.HandleFunc("/teams/{team_id}/templates/{template_id}", s.handlers.Template.HandleTemplateUpdate()).Methods("PUT")
+	private.HandleFunc("/teams/{team_id}/templates/{template_id}", s.handlers.Template.HandleTemplateDelete()).Methods("DELETE")
+	//создает задачу по шаблону (vars, due_date, assignee_id, with_subtasks) одной транзакцией
+	private.HandleFunc("/teams/{team_id}/templates/{template_id}/tasks", s.handlers.Template.HandleTemplateInstantiate()).Methods("POST")
+	//свои поля задач команды (text, number, date, select с options), тип после создания не меняется
+	private.HandleFunc("/teams/{team_id}/fields", s.handlers.Field.HandleFieldList()).Methods("GET")
+	private.HandleFunc("/teams/{team_id}/fields", s.handlers.Field.HandleFieldCreate()).Methods("POST")
+	private.HandleFunc("/teams/{team_id}/fields/{field_id}", s.handlers.Field.HandleFieldUpdate()).Methods("PUT")
+	private.HandleFunc("/teams/{team_id}/fields/{field_id}", s.handlers.Field.HandleFieldDelete()).Methods("DELETE")
+	//значения полей задачи: объект key -> значение, null очищает поле
+	private.HandleFunc("/task/{task_id}/fields", s.handlers.Field.HandleTaskFieldsSet()).Methods("PUT")
+	//полнотекстовый поиск по задачам, комментариям и командам юзера (q, type, team_id, limit, offset)
+	private.HandleFunc("/search", s.handlers.Search.HandleSearch()).Methods("GET")
+	//метки команды: список, создание, правка, удаление
+	private.HandleFunc("/teams/{team_id}/labels", s.handlers.Label.HandleLabelList()).Methods("GET")
+	private.HandleFunc("/teams/{team_id}/labels", s.handlers.Label.HandleLabelCreate()).Methods("POST")
+	private.HandleFunc("/teams/{team_id}/labels/{label_id}", s.handlers.Label.HandleLabelUpdate()).Methods("PUT")
+	private.HandleFunc("/teams/{team_id}/labels/{label_id}", s.handlers.Label.HandleLabelDelete()).Methods("DELETE")
+	//вешает метку на задачу (label_id) и снимает ее
+	private.HandleFunc("/task/{task_id}/labels", s.handlers.Label.HandleTaskLabelAttach()).Methods("POST")
+	private.HandleFunc("/task/{task_id}/labels/{label_id}", s.handlers.Label.HandleTaskLabelDetach()).Methods("DELETE")
+	//исполнители задачи (в теле user_id), assignee_id в ответе остается основным исполнителем
+	private.HandleFunc("/task/{task_id}/assignees", s.handlers.Assignee.HandleTaskAssigneeAdd()).Methods("POST")
+	private.HandleFunc("/task/{task_id}/assignees/{user_id}", s.handlers.Assignee.HandleTaskAssigneeRemove()).Methods("DELETE")
+	//наблюдатели: без тела подписывает себя, user_id подписывает другого участника
+	private.HandleFunc("/task/{task_id}/watchers", s.handlers.Assignee.HandleTaskWatch()).Methods("POST")
+	private.HandleFunc("/task/{task_id}/watchers/{user_id}", s.handlers.Assignee.HandleTaskUnwatch()).Methods("DELETE")
+	//вложения задачи: загрузка multipart/form-data (поля file), размер и типы из [attachments] конфига,
+	//скачивание отдает файл потоком с Content-Disposition
+	private.HandleFunc("/task/{task_id}/attachments", s.handlers.Attachment.HandleAttachmentList()).Methods("GET")
+	private.HandleFunc("/task/{task_id}/attachments", s.handlers.Attachment.HandleAttachmentUpload()).Methods("POST")
+	private.HandleFunc("/task/{task_id}/attachments/{attachment_id}", s.handlers.Attachment.HandleAttachmentDownload()).Methods("GET")
+	private.HandleFunc("/task/{task_id}/attachments/{attachment_id}", s.handlers.Attachment.HandleAttachmentDelete()).Methods("DELETE")
+	//присваивает задачу юзеру (в теле task_id)
 	private.HandleFunc("/task/{user_id}/member", s.handlers.Task.HandleTaskAssigneeID()).Methods("POST")
 	//заканчивает активную сессию
 	private.HandleFunc("/logout", s.handlers.User.HandlerUsersDelete()).Methods("POST")
@@ -46,7 +171,12 @@ func (s *server) configureRouter() {
 	private.HandleFunc("/profile", s.handlers.User.HandleUpdateProfile()).Methods("PUT")
 	//обновляет название, описание и тд команды
 	private.HandleFunc("/team/{team_id}", s.handlers.Team.HandleTeamUpdate()).Methods("PUT")
+	//меняет роль участника команды
+	private.HandleFunc("/teams/{team_id}/members/{user_id}", s.handlers.Team.HandleTeamMemberRole()).Methods("PUT")
+	//передает владение командой другому участнику
+	private.HandleFunc("/teams/{team_id}/owner", s.handlers.Team.HandleTeamTransferOwnership()).Methods("PUT")
 	//обновляет название, контент задачи и тд (короче если что потом просто уточнишь)
+	//меняются только переданные поля, null очищает due_date, assignee_id (снимает всех исполнителей) и т.п.
 	private.HandleFunc("/task/{task_id}", s.handlers.Task.HandleTaskUpdate()).Methods("PUT")
 
 	//удаляет пользователя из бд (свой акк)
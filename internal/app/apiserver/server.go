@@ -3,6 +3,10 @@ package apiserver
 import (
 	"github.com/gorilla/mux"
 	"github.com/gorilla/sessions"
+	"github.com/qeery8/rest/internal/app/blob"
+	"github.com/qeery8/rest/internal/app/notify"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/config"
 	"github.com/qeery8/rest/internal/store"
 	"github.com/qeery8/rest/internal/transport/handler"
 	"github.com/sirupsen/logrus"
@@ -16,25 +20,108 @@ type server struct {
 	handlers     handler.Handlers
 }
 
-func newServer(store store.Store, sessionStore sessions.Store) *server {
+func newServer(store store.Store, sessionStore sessions.Store, logger *logrus.Logger, notifier notify.Notifier, blobs blob.Storage, attachments config.Attachments) *server {
 	s := &server{
 		router:       mux.NewRouter(),
-		logger:       logrus.New(),
+		logger:       logger,
 		store:        store,
 		sessionStore: sessionStore,
 	}
 
+	policy := policy.New(store)
+
 	s.handlers = handler.Handlers{
 		User: handler.UserHandlers{
 			Store:        store,
 			SessionStore: sessionStore,
 		},
 		Team: handler.TeamHandlers{
-			Store: store,
+			Store:  store,
+			Policy: policy,
 		},
 		Task: handler.TaskHandlers{
+			Store:  store,
+			Policy: policy,
+		},
+		Invitation: handler.InvitationHandlers{
+			Store:  store,
+			Policy: policy,
+		},
+		Comment: handler.CommentHandlers{
+			Store:    store,
+			Policy:   policy,
+			Notifier: notify.NewAsync(notifier, logger),
+		},
+		Attachment: handler.AttachmentHandlers{
+			Store:        store,
+			Policy:       policy,
+			Storage:      blobs,
+			MaxSize:      attachments.MaxSizeMB << 20,
+			AllowedTypes: attachments.AllowedTypes,
+		},
+		Activity: handler.ActivityHandlers{
+			Store:  store,
+			Policy: policy,
+		},
+		Archive: handler.ArchiveHandlers{
+			Store:  store,
+			Policy: policy,
+		},
+		Assignee: handler.AssigneeHandlers{
+			Store:  store,
+			Policy: policy,
+		},
+		Subtask: handler.SubtaskHandlers{
+			Store:  store,
+			Policy: policy,
+		},
+		Checklist: handler.ChecklistHandlers{
+			Store:  store,
+			Policy: policy,
+		},
+		Dependency: handler.DependencyHandlers{
+			Store:  store,
+			Policy: policy,
+		},
+		Workflow: handler.WorkflowHandlers{
+			Store:  store,
+			Policy: policy,
+		},
+		Board: handler.BoardHandlers{
+			Store:  store,
+			Policy: policy,
+		},
+		Sprint: handler.SprintHandlers{
+			Store:  store,
+			Policy: policy,
+		},
+		Report: handler.ReportHandlers{
+			Store:  store,
+			Policy: policy,
+		},
+		Worklog: handler.WorklogHandlers{
+			Store:  store,
+			Policy: policy,
+		},
+		Label: handler.LabelHandlers{
+			Store:  store,
+			Policy: policy,
+		},
+		Template: handler.TemplateHandlers{
+			Store:  store,
+			Policy: policy,
+		},
+		Field: handler.FieldHandlers{
+			Store:  store,
+			Policy: policy,
+		},
+		Search: handler.SearchHandlers{
 			Store: store,
 		},
+		Bulk: handler.BulkHandlers{
+			Store:  store,
+			Policy: policy,
+		},
 	}
 
 	s.configureRouter()
@@ -0,0 +1,978 @@
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+	"github.com/qeery8/rest/internal/app/blob"
+	"github.com/qeery8/rest/internal/app/notify"
+	"github.com/qeery8/rest/internal/app/session"
+	"github.com/qeery8/rest/internal/config"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+	"github.com/qeery8/rest/internal/store/teststore"
+	"github.com/sirupsen/logrus"
+)
+
+var secretKey = []byte("secret")
+
+// missingID is never handed out by the teststore within one test.
+const missingID = 999999
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+type nopNotifier struct{}
+
+func (nopNotifier) Notify(ctx context.Context, msg *notify.Notification) error {
+	return nil
+}
+
+// fixture is a team with one resource of every kind. The owner created
+// everything; the member is assigned to and watches the task, which sits in
+// the sprint and is blocked by another task.
+type fixture struct {
+	srv   *server
+	store *teststore.Store
+
+	owner    *model.User
+	member   *model.User
+	outsider *model.User
+
+	teamID       int
+	taskID       int
+	otherID      int
+	blockerID    int
+	archivedID   int
+	labelID      int
+	sprintID     int
+	templateID   int
+	fieldID      int
+	itemID       int
+	worklogID    int
+	commentID    int
+	attachmentID int
+
+	blobs blob.Storage
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	st := teststore.New()
+	blobs := blob.NewLocalStorage(t.TempDir())
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	f := &fixture{
+		srv: newServer(st, sessions.NewCookieStore(secretKey), logger, nopNotifier{}, blobs, config.Attachments{
+			MaxSizeMB:    1,
+			AllowedTypes: []string{"text/plain"},
+		}),
+		store: st,
+		blobs: blobs,
+	}
+
+	user := func(email string) *model.User {
+		u := &model.User{Email: email, EncryptedPassword: "-"}
+		must(t, st.User().Create(u))
+		return u
+	}
+	f.owner = user("owner@example.org")
+	f.member = user("member@example.org")
+	f.outsider = user("outsider@example.org")
+	by := &f.owner.ID
+
+	team := &model.Team{Name: "team", OwnerID: f.owner.ID}
+	must(t, st.Team().Create(team))
+	must(t, st.Team().AddMembers(team.ID, f.member.ID, model.RoleMember))
+	f.teamID = team.ID
+
+	task := func(name string) int {
+		task := &model.Task{Name: name, TeamID: team.ID}
+		must(t, st.Task().Create(task, by))
+		return task.ID
+	}
+	f.taskID = task("task")
+	f.otherID = task("other")
+	f.blockerID = task("blocker")
+	f.archivedID = task("archived")
+
+	must(t, st.Task().AddAssignee(f.taskID, f.member.ID, by))
+	must(t, st.Task().Watch(f.taskID, f.member.ID))
+	must(t, st.Dependency().Add(f.blockerID, f.taskID, by))
+	must(t, st.Archive().Abandon(f.archivedID, "stale", by))
+
+	label := &model.Label{TeamID: team.ID, Name: "bug", Color: "#aa0000"}
+	must(t, st.Label().Create(label))
+	must(t, st.Label().Attach(f.taskID, label.ID, by))
+	f.labelID = label.ID
+
+	sprint := &model.Sprint{
+		TeamID:    team.ID,
+		Name:      "sprint",
+		StartDate: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC),
+	}
+	must(t, st.Sprint().Create(sprint))
+	must(t, st.Sprint().AddTasks(sprint.ID, []int{f.taskID}, by))
+	f.sprintID = sprint.ID
+
+	tmpl := &model.TaskTemplate{TeamID: team.ID, Name: "weekly", NamePattern: "weekly {date}", CreatedBy: by}
+	must(t, st.Template().Create(tmpl))
+	f.templateID = tmpl.ID
+
+	field := &model.CustomField{TeamID: team.ID, Key: "env", Name: "Env", Type: model.FieldText}
+	must(t, st.CustomField().Create(field))
+	f.fieldID = field.ID
+
+	item := &model.ChecklistItem{TaskID: f.taskID, Title: "step"}
+	must(t, st.Checklist().Create(item))
+	f.itemID = item.ID
+
+	wl := &model.Worklog{TaskID: f.taskID, UserID: f.owner.ID, Minutes: 30, WorkDate: time.Now()}
+	must(t, st.Worklog().Create(wl))
+	f.worklogID = wl.ID
+
+	c := &model.Comment{TaskID: f.taskID, AuthorID: by, Body: "hello"}
+	must(t, st.Comment().Create(c))
+	f.commentID = c.ID
+
+	a := &model.Attachment{
+		TaskID:      f.taskID,
+		StorageKey:  "fixture",
+		FileName:    "notes.txt",
+		ContentType: "text/plain",
+		Size:        5,
+		UploadedBy:  by,
+	}
+	must(t, blobs.Put(context.Background(), a.StorageKey, strings.NewReader("notes"), a.Size, a.ContentType))
+	must(t, st.Attachment().Create(a))
+	f.attachmentID = a.ID
+
+	return f
+}
+
+// forget points every id of the fixture at nothing, so the team and task
+// scoped lookups of a route fail.
+func (f *fixture) forget() {
+	f.teamID = missingID
+	f.taskID = missingID
+	f.archivedID = missingID
+}
+
+func (f *fixture) do(t *testing.T, u *model.User, method string, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var (
+		reader      io.Reader
+		contentType = "application/json"
+	)
+	switch b := body.(type) {
+	case nil:
+	case *upload:
+		reader, contentType = b.encode(t)
+	default:
+		buf := &bytes.Buffer{}
+		must(t, json.NewEncoder(buf).Encode(b))
+		reader = buf
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", contentType)
+
+	sc := securecookie.New(secretKey, nil)
+	cookie, err := sc.Encode(session.SessionsName, map[interface{}]interface{}{
+		"user_id": u.ID,
+	})
+	must(t, err)
+	req.Header.Set("Cookie", fmt.Sprintf("%s=%s", session.SessionsName, cookie))
+
+	rec := httptest.NewRecorder()
+	f.srv.ServeHTTP(rec, req)
+	return rec
+}
+
+type upload struct {
+	name    string
+	content string
+}
+
+func (u *upload) encode(t *testing.T) (io.Reader, string) {
+	buf := &bytes.Buffer{}
+	mw := multipart.NewWriter(buf)
+	part, err := mw.CreateFormFile("file", u.name)
+	must(t, err)
+	_, err = part.Write([]byte(u.content))
+	must(t, err)
+	must(t, mw.Close())
+	return buf, mw.FormDataContentType()
+}
+
+// route is one team or task scoped endpoint. The owner always succeeds with
+// ok; member is what a plain member gets; outsiders always get 403 and a
+// missing team or task always gives 404.
+type route struct {
+	method string
+	path   routePath
+	body   func(f *fixture) interface{}
+	setup  func(t *testing.T, f *fixture)
+	ok     int
+	member int
+}
+
+type routePath struct {
+	format string
+	ids    []func(f *fixture) int
+}
+
+func path(format string, ids ...func(f *fixture) int) routePath {
+	return routePath{format: "/private" + format, ids: ids}
+}
+
+func (p routePath) String() string {
+	return strings.ReplaceAll(p.format, "%d", "{id}")
+}
+
+func (p routePath) For(f *fixture) string {
+	args := make([]interface{}, len(p.ids))
+	for i, id := range p.ids {
+		args[i] = id(f)
+	}
+	return fmt.Sprintf(p.format, args...)
+}
+
+func body(v interface{}) func(f *fixture) interface{} {
+	return func(*fixture) interface{} { return v }
+}
+
+var (
+	team       = func(f *fixture) int { return f.teamID }
+	task       = func(f *fixture) int { return f.taskID }
+	blocker    = func(f *fixture) int { return f.blockerID }
+	archived   = func(f *fixture) int { return f.archivedID }
+	label      = func(f *fixture) int { return f.labelID }
+	sprint     = func(f *fixture) int { return f.sprintID }
+	template   = func(f *fixture) int { return f.templateID }
+	field      = func(f *fixture) int { return f.fieldID }
+	item       = func(f *fixture) int { return f.itemID }
+	worklog    = func(f *fixture) int { return f.worklogID }
+	comment    = func(f *fixture) int { return f.commentID }
+	attachment = func(f *fixture) int { return f.attachmentID }
+	member     = func(f *fixture) int { return f.member.ID }
+)
+
+var routes = []route{
+	// команда и участники
+	{method: "GET", path: path("/team/%d", team), ok: 200, member: 200},
+	{method: "PUT", path: path("/team/%d", team), body: body(map[string]string{"name": "renamed"}), ok: 200, member: 403},
+	{method: "GET", path: path("/teams/%d/members", team), ok: 200, member: 200},
+	{method: "POST", path: path("/teams/%d/members", team), body: func(f *fixture) interface{} {
+		return map[string]interface{}{"user_id": f.outsider.ID, "role": "member"}
+	}, ok: 200, member: 403},
+	{method: "PUT", path: path("/teams/%d/members/%d", team, member), body: body(map[string]string{"role": "viewer"}), ok: 200, member: 403},
+	{method: "DELETE", path: path("/team/%d/members/%d", team, member), ok: 200, member: 200},
+	{method: "PUT", path: path("/teams/%d/owner", team), body: func(f *fixture) interface{} {
+		return map[string]int{"user_id": f.member.ID}
+	}, ok: 200, member: 403},
+	{method: "POST", path: path("/teams/%d/invitations", team), body: body(map[string]string{"email": "new@example.org", "role": "member"}), ok: 201, member: 403},
+	{method: "GET", path: path("/teams/%d/invitations", team), ok: 200, member: 403},
+
+	// задачи
+	{method: "POST", path: path("/task"), body: func(f *fixture) interface{} {
+		return map[string]interface{}{"name": "new task", "team_id": f.teamID}
+	}, ok: 201, member: 201},
+	{method: "GET", path: path("/teams/%d/tasks", team), ok: 200, member: 200},
+	{method: "POST", path: path("/teams/%d/tasks", team), body: body(map[string]string{"name": "new task"}), ok: 201, member: 201},
+	{method: "GET", path: path("/task/%d", task), ok: 200, member: 200},
+	{method: "PUT", path: path("/task/%d", task), body: body(map[string]string{"name": "renamed"}), ok: 200, member: 200},
+	{method: "DELETE", path: path("/task/%d", task), ok: 200, member: 403},
+	{method: "POST", path: path("/task/%d/member", member), body: func(f *fixture) interface{} {
+		return map[string]int{"task_id": f.taskID}
+	}, ok: 200, member: 200},
+	{method: "POST", path: path("/task/%d/assignees", task), body: func(f *fixture) interface{} {
+		return map[string]int{"user_id": f.owner.ID}
+	}, ok: 200, member: 200},
+	{method: "DELETE", path: path("/task/%d/assignees/%d", task, member), ok: 200, member: 200},
+	{method: "POST", path: path("/task/%d/watchers", task), ok: 200, member: 200},
+	{method: "DELETE", path: path("/task/%d/watchers/%d", task, member), ok: 200, member: 200},
+	{method: "PUT", path: path("/task/%d/fields", task), body: body(map[string]string{"env": "prod"}), ok: 200, member: 200},
+
+	// архив
+	{method: "POST", path: path("/task/%d/abandon", task), body: body(map[string]string{"reason": "dropped"}), ok: 200, member: 200},
+	{method: "GET", path: path("/teams/%d/abandoned", team), ok: 200, member: 200},
+	{method: "POST", path: path("/teams/%d/abandoned/%d/restore", team, archived), ok: 200, member: 200},
+
+	// комментарии
+	{method: "GET", path: path("/task/%d/comments", task), ok: 200, member: 200},
+	{method: "POST", path: path("/task/%d/comments", task), body: body(map[string]string{"body": "hi"}), ok: 201, member: 201},
+	{method: "PUT", path: path("/task/%d/comments/%d", task, comment), body: body(map[string]string{"body": "edited"}), ok: 200, member: 403},
+	{method: "DELETE", path: path("/task/%d/comments/%d", task, comment), ok: 200, member: 403},
+	{method: "GET", path: path("/task/%d/comments/%d/history", task, comment), ok: 200, member: 200},
+
+	// история
+	{method: "GET", path: path("/task/%d/history", task), ok: 200, member: 200},
+	{method: "GET", path: path("/teams/%d/activity", team), ok: 200, member: 200},
+
+	// подзадачи и чеклист
+	{method: "GET", path: path("/task/%d/subtasks", task), ok: 200, member: 200},
+	{method: "POST", path: path("/task/%d/subtasks", task), body: body(map[string]string{"name": "subtask"}), ok: 201, member: 201},
+	{method: "PUT", path: path("/task/%d/parent", task), body: func(f *fixture) interface{} {
+		return map[string]int{"parent_id": f.otherID}
+	}, ok: 200, member: 200},
+	{method: "GET", path: path("/task/%d/checklist", task), ok: 200, member: 200},
+	{method: "POST", path: path("/task/%d/checklist", task), body: body(map[string]string{"title": "next"}), ok: 201, member: 201},
+	{method: "PUT", path: path("/task/%d/checklist/%d", task, item), body: body(map[string]bool{"done": true}), ok: 200, member: 200},
+	{method: "DELETE", path: path("/task/%d/checklist/%d", task, item), ok: 200, member: 200},
+
+	// зависимости
+	{method: "GET", path: path("/task/%d/dependencies", task), ok: 200, member: 200},
+	{method: "POST", path: path("/task/%d/dependencies", task), body: func(f *fixture) interface{} {
+		return map[string]int{"blocker_id": f.otherID}
+	}, ok: 201, member: 201},
+	{method: "DELETE", path: path("/task/%d/dependencies/%d", task, blocker), ok: 200, member: 200},
+
+	// процесс и доска
+	{method: "GET", path: path("/teams/%d/workflow", team), ok: 200, member: 200},
+	{method: "PUT", path: path("/teams/%d/workflow", team), body: func(f *fixture) interface{} {
+		return model.DefaultWorkflow(f.teamID)
+	}, ok: 200, member: 403},
+	{method: "GET", path: path("/teams/%d/board", team), ok: 200, member: 200},
+	{method: "POST", path: path("/task/%d/move", task), body: body(map[string]string{"status": string(model.StatusInProgress)}), ok: 200, member: 200},
+
+	// спринты
+	{method: "GET", path: path("/teams/%d/sprints", team), ok: 200, member: 200},
+	{method: "POST", path: path("/teams/%d/sprints", team), body: body(map[string]string{
+		"name": "next", "start_date": "2026-01-15", "end_date": "2026-01-28",
+	}), ok: 201, member: 403},
+	{method: "GET", path: path("/teams/%d/sprints/%d", team, sprint), ok: 200, member: 200},
+	{method: "PUT", path: path("/teams/%d/sprints/%d", team, sprint), body: body(map[string]string{"goal": "ship"}), ok: 200, member: 403},
+	{method: "POST", path: path("/teams/%d/sprints/%d/start", team, sprint), ok: 200, member: 403},
+	{method: "POST", path: path("/teams/%d/sprints/%d/close", team, sprint), body: body(map[string]interface{}{}), setup: func(t *testing.T, f *fixture) {
+		must(t, f.store.Sprint().Start(f.sprintID))
+	}, ok: 200, member: 403},
+	{method: "GET", path: path("/teams/%d/sprints/%d/summary", team, sprint), ok: 200, member: 200},
+	{method: "POST", path: path("/teams/%d/sprints/%d/tasks", team, sprint), body: func(f *fixture) interface{} {
+		return map[string][]int{"task_ids": {f.otherID}}
+	}, ok: 200, member: 200},
+	{method: "DELETE", path: path("/teams/%d/sprints/%d/tasks/%d", team, sprint, task), ok: 200, member: 200},
+
+	// отчеты
+	{method: "GET", path: path("/teams/%d/reports/burndown", team), ok: 200, member: 200},
+	{method: "GET", path: path("/teams/%d/reports/cycle-time", team), ok: 200, member: 200},
+	{method: "GET", path: path("/teams/%d/reports/throughput", team), ok: 200, member: 200},
+	{method: "GET", path: path("/teams/%d/reports/workload", team), ok: 200, member: 200},
+	{method: "GET", path: path("/teams/%d/reports/timesheet", team), ok: 200, member: 200},
+
+	// учет времени
+	{method: "GET", path: path("/task/%d/worklogs", task), ok: 200, member: 200},
+	{method: "POST", path: path("/task/%d/worklogs", task), body: body(map[string]interface{}{
+		"minutes": 15, "work_date": "2026-01-05",
+	}), ok: 201, member: 201},
+	{method: "PUT", path: path("/task/%d/worklogs/%d", task, worklog), body: body(map[string]int{"minutes": 45}), ok: 200, member: 403},
+	{method: "DELETE", path: path("/task/%d/worklogs/%d", task, worklog), ok: 200, member: 403},
+
+	// шаблоны
+	{method: "GET", path: path("/teams/%d/templates", team), ok: 200, member: 200},
+	{method: "POST", path: path("/teams/%d/templates", team), body: body(map[string]string{
+		"name": "daily", "name_pattern": "daily {date}",
+	}), ok: 201, member: 201},
+	{method: "GET", path: path("/teams/%d/templates/%d", team, template), ok: 200, member: 200},
+	{method: "PUT", path: path("/teams/%d/templates/%d", team, template), body: body(map[string]string{"content": "steps"}), ok: 200, member: 200},
+	{method: "DELETE", path: path("/teams/%d/templates/%d", team, template), ok: 200, member: 200},
+	{method: "POST", path: path("/teams/%d/templates/%d/tasks", team, template), body: body(map[string]interface{}{}), ok: 201, member: 201},
+
+	// свои поля
+	{method: "GET", path: path("/teams/%d/fields", team), ok: 200, member: 200},
+	{method: "POST", path: path("/teams/%d/fields", team), body: body(map[string]string{
+		"key": "region", "name": "Region", "type": string(model.FieldText),
+	}), ok: 201, member: 403},
+	{method: "PUT", path: path("/teams/%d/fields/%d", team, field), body: body(map[string]string{"name": "Environment"}), ok: 200, member: 403},
+	{method: "DELETE", path: path("/teams/%d/fields/%d", team, field), ok: 200, member: 403},
+
+	// метки
+	{method: "GET", path: path("/teams/%d/labels", team), ok: 200, member: 200},
+	{method: "POST", path: path("/teams/%d/labels", team), body: body(map[string]string{"name": "feature", "color": "#00aa00"}), ok: 201, member: 201},
+	{method: "PUT", path: path("/teams/%d/labels/%d", team, label), body: body(map[string]string{"name": "defect"}), ok: 200, member: 200},
+	{method: "DELETE", path: path("/teams/%d/labels/%d", team, label), ok: 200, member: 200},
+	{method: "POST", path: path("/task/%d/labels", task), body: func(f *fixture) interface{} {
+		return map[string]int{"label_id": f.labelID}
+	}, ok: 200, member: 200},
+	{method: "DELETE", path: path("/task/%d/labels/%d", task, label), ok: 200, member: 200},
+
+	// вложения
+	{method: "GET", path: path("/task/%d/attachments", task), ok: 200, member: 200},
+	{method: "POST", path: path("/task/%d/attachments", task), body: body(&upload{name: "todo.txt", content: "buy milk"}), ok: 201, member: 201},
+	{method: "GET", path: path("/task/%d/attachments/%d", task, attachment), ok: 200, member: 200},
+	{method: "DELETE", path: path("/task/%d/attachments/%d", task, attachment), ok: 200, member: 200},
+}
+
+// check looks at what a successful response says, past its status code.
+type check func(t *testing.T, f *fixture, rec *httptest.ResponseRecorder)
+
+// returns decodes the response body into T for fn.
+func returns[T any](fn func(t *testing.T, f *fixture, v T)) check {
+	return func(t *testing.T, f *fixture, rec *httptest.ResponseRecorder) {
+		t.Helper()
+		var v T
+		decode(t, rec, &v)
+		fn(t, f, v)
+	}
+}
+
+func hasTask(tasks []*model.Task, id int) bool {
+	for _, task := range tasks {
+		if task.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func hasLabel(labels []model.LabelRef, id int) bool {
+	for _, l := range labels {
+		if l.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// checks are keyed by route name, as in the subtests below.
+var checks = map[string]check{
+	"GET /private/team/{id}": returns(func(t *testing.T, f *fixture, team *model.Team) {
+		if team.ID != f.teamID || team.Name != "team" {
+			t.Errorf("got team %+v", team)
+		}
+	}),
+	"PUT /private/team/{id}": func(t *testing.T, f *fixture, rec *httptest.ResponseRecorder) {
+		team, err := f.store.Team().Find(f.teamID)
+		must(t, err)
+		if team.Name != "renamed" {
+			t.Errorf("team name: got %q, want %q", team.Name, "renamed")
+		}
+	},
+	"GET /private/teams/{id}/members": returns(func(t *testing.T, f *fixture, members []*model.TeamMember) {
+		roles := map[int]model.TeamRole{}
+		for _, m := range members {
+			roles[m.UserID] = m.Role
+		}
+		if len(roles) != 2 || roles[f.owner.ID] != model.RoleOwner || roles[f.member.ID] != model.RoleMember {
+			t.Errorf("got members %v", roles)
+		}
+	}),
+
+	"POST /private/task": returns(func(t *testing.T, f *fixture, task *model.Task) {
+		if task.ID == 0 || task.Name != "new task" || task.TeamID != f.teamID || task.Status != model.StatusToDo {
+			t.Errorf("got task %+v", task)
+		}
+	}),
+	"GET /private/teams/{id}/tasks": returns(func(t *testing.T, f *fixture, page *store.TaskPage) {
+		if len(page.Tasks) != 3 || !hasTask(page.Tasks, f.taskID) || !hasTask(page.Tasks, f.otherID) || !hasTask(page.Tasks, f.blockerID) {
+			t.Errorf("got %d tasks, want the three open ones", len(page.Tasks))
+		}
+		if hasTask(page.Tasks, f.archivedID) {
+			t.Error("archived task listed")
+		}
+	}),
+	"POST /private/teams/{id}/tasks": returns(func(t *testing.T, f *fixture, task *model.Task) {
+		if task.Name != "new task" || task.TeamID != f.teamID {
+			t.Errorf("got task %+v", task)
+		}
+	}),
+	"GET /private/task/{id}": returns(func(t *testing.T, f *fixture, task *model.Task) {
+		if task.ID != f.taskID || task.Name != "task" || !task.Blocked || !hasLabel(task.Labels, f.labelID) ||
+			len(task.AssigneeIDs) != 1 || task.AssigneeIDs[0] != f.member.ID || task.SprintID == nil || *task.SprintID != f.sprintID {
+			t.Errorf("got task %+v", task)
+		}
+	}),
+	"PUT /private/task/{id}": returns(func(t *testing.T, f *fixture, task *model.Task) {
+		if task.ID != f.taskID || task.Name != "renamed" {
+			t.Errorf("got task %+v", task)
+		}
+	}),
+	"DELETE /private/task/{id}": func(t *testing.T, f *fixture, rec *httptest.ResponseRecorder) {
+		if _, err := f.store.Task().GetByID(f.taskID); err == nil {
+			t.Error("task is still there")
+		}
+	},
+	"POST /private/task/{id}/assignees": returns(func(t *testing.T, f *fixture, task *model.Task) {
+		if len(task.AssigneeIDs) != 2 || task.AssigneeID == nil || *task.AssigneeID != f.member.ID {
+			t.Errorf("got assignees %v, main %v", task.AssigneeIDs, task.AssigneeID)
+		}
+	}),
+	"PUT /private/task/{id}/fields": returns(func(t *testing.T, f *fixture, task *model.Task) {
+		if task.Fields["env"] != "prod" {
+			t.Errorf("got fields %v", task.Fields)
+		}
+	}),
+
+	"POST /private/task/{id}/abandon": func(t *testing.T, f *fixture, rec *httptest.ResponseRecorder) {
+		if rec := f.do(t, f.owner, http.MethodGet, fmt.Sprintf("/private/task/%d", f.taskID), nil); rec.Code != http.StatusNotFound {
+			t.Errorf("abandoned task: got %d, want %d", rec.Code, http.StatusNotFound)
+		}
+	},
+	"GET /private/teams/{id}/abandoned": returns(func(t *testing.T, f *fixture, tasks []*model.Task) {
+		if len(tasks) != 1 || tasks[0].ID != f.archivedID {
+			t.Errorf("got %d tasks, want the archived one", len(tasks))
+		}
+	}),
+	"POST /private/teams/{id}/abandoned/{id}/restore": returns(func(t *testing.T, f *fixture, task *model.Task) {
+		if task.ID != f.archivedID || task.Status != model.StatusToDo {
+			t.Errorf("got task %+v", task)
+		}
+	}),
+
+	"GET /private/task/{id}/comments": returns(func(t *testing.T, f *fixture, comments []*model.Comment) {
+		if len(comments) != 1 || comments[0].ID != f.commentID || comments[0].Body != "hello" {
+			t.Errorf("got comments %+v", comments)
+		}
+	}),
+	"POST /private/task/{id}/comments": returns(func(t *testing.T, f *fixture, c *model.Comment) {
+		if c.TaskID != f.taskID || c.Body != "hi" || c.AuthorID == nil || c.Edited {
+			t.Errorf("got comment %+v", c)
+		}
+	}),
+	"PUT /private/task/{id}/comments/{id}": returns(func(t *testing.T, f *fixture, c *model.Comment) {
+		if c.Body != "edited" || !c.Edited {
+			t.Errorf("got comment %+v", c)
+		}
+	}),
+	"GET /private/task/{id}/history": returns(func(t *testing.T, f *fixture, page *store.ActivityPage) {
+		if len(page.Items) == 0 {
+			t.Fatal("no history")
+		}
+		for _, a := range page.Items {
+			if a.TaskID != f.taskID {
+				t.Errorf("entry of task %d", a.TaskID)
+			}
+		}
+	}),
+	"GET /private/teams/{id}/activity": returns(func(t *testing.T, f *fixture, page *store.ActivityPage) {
+		if len(page.Items) == 0 {
+			t.Fatal("no activity")
+		}
+		for _, a := range page.Items {
+			if a.TeamID != f.teamID {
+				t.Errorf("entry of team %d", a.TeamID)
+			}
+		}
+	}),
+
+	"POST /private/task/{id}/subtasks": returns(func(t *testing.T, f *fixture, task *model.Task) {
+		if task.Name != "subtask" || task.ParentID == nil || *task.ParentID != f.taskID || task.TeamID != f.teamID {
+			t.Errorf("got subtask %+v", task)
+		}
+	}),
+	"PUT /private/task/{id}/parent": returns(func(t *testing.T, f *fixture, task *model.Task) {
+		if task.ParentID == nil || *task.ParentID != f.otherID {
+			t.Errorf("got parent %v, want %d", task.ParentID, f.otherID)
+		}
+	}),
+	"GET /private/task/{id}/checklist": returns(func(t *testing.T, f *fixture, items []*model.ChecklistItem) {
+		if len(items) != 1 || items[0].Title != "step" || items[0].Done {
+			t.Errorf("got items %+v", items)
+		}
+	}),
+	"POST /private/task/{id}/checklist": returns(func(t *testing.T, f *fixture, item *model.ChecklistItem) {
+		if item.TaskID != f.taskID || item.Title != "next" || item.Done {
+			t.Errorf("got item %+v", item)
+		}
+	}),
+	"PUT /private/task/{id}/checklist/{id}": returns(func(t *testing.T, f *fixture, item *model.ChecklistItem) {
+		if item.ID != f.itemID || !item.Done {
+			t.Errorf("got item %+v", item)
+		}
+	}),
+
+	"GET /private/task/{id}/dependencies": returns(func(t *testing.T, f *fixture, deps struct {
+		BlockedBy []*model.Task `json:"blocked_by"`
+		Blocks    []*model.Task `json:"blocks"`
+	}) {
+		if len(deps.BlockedBy) != 1 || deps.BlockedBy[0].ID != f.blockerID || len(deps.Blocks) != 0 {
+			t.Errorf("got blocked by %d tasks and blocking %d", len(deps.BlockedBy), len(deps.Blocks))
+		}
+	}),
+	"DELETE /private/task/{id}/dependencies/{id}": func(t *testing.T, f *fixture, rec *httptest.ResponseRecorder) {
+		task, err := f.store.Task().GetByID(f.taskID)
+		must(t, err)
+		if task.Blocked {
+			t.Error("task is still blocked")
+		}
+	},
+
+	"GET /private/teams/{id}/workflow": returns(func(t *testing.T, f *fixture, wf *model.Workflow) {
+		if wf.TeamID != f.teamID || len(wf.Statuses) != len(model.DefaultWorkflow(f.teamID).Statuses) {
+			t.Errorf("got workflow %+v", wf)
+		}
+	}),
+	"GET /private/teams/{id}/board": returns(func(t *testing.T, f *fixture, board *model.Board) {
+		if board.TeamID != f.teamID || len(board.Columns) == 0 {
+			t.Fatalf("got board %+v", board)
+		}
+		todo := board.Columns[0]
+		if todo.Status != model.StatusToDo || len(todo.Tasks) != 3 || !hasTask(todo.Tasks, f.taskID) {
+			t.Errorf("first column %q has %d tasks", todo.Status, len(todo.Tasks))
+		}
+	}),
+	"POST /private/task/{id}/move": returns(func(t *testing.T, f *fixture, task *model.Task) {
+		if task.ID != f.taskID || task.Status != model.StatusInProgress || task.Category != model.CategoryInProgress {
+			t.Errorf("got task %+v", task)
+		}
+	}),
+
+	"POST /private/teams/{id}/sprints": returns(func(t *testing.T, f *fixture, sp *model.Sprint) {
+		if sp.TeamID != f.teamID || sp.Name != "next" || sp.State != model.SprintPlanned {
+			t.Errorf("got sprint %+v", sp)
+		}
+	}),
+	"POST /private/teams/{id}/sprints/{id}/start": returns(func(t *testing.T, f *fixture, sp *model.Sprint) {
+		if sp.ID != f.sprintID || sp.State != model.SprintActive {
+			t.Errorf("got sprint %+v", sp)
+		}
+	}),
+	"GET /private/teams/{id}/sprints/{id}/summary": returns(func(t *testing.T, f *fixture, sum *model.SprintSummary) {
+		if sum.SprintID != f.sprintID || sum.Total != 1 || sum.Remaining != 1 || sum.Completed != 0 {
+			t.Errorf("got summary %+v", sum)
+		}
+	}),
+	"POST /private/teams/{id}/sprints/{id}/tasks": func(t *testing.T, f *fixture, rec *httptest.ResponseRecorder) {
+		task, err := f.store.Task().GetByID(f.otherID)
+		must(t, err)
+		if task.SprintID == nil || *task.SprintID != f.sprintID {
+			t.Errorf("got sprint %v, want %d", task.SprintID, f.sprintID)
+		}
+	},
+
+	"GET /private/task/{id}/worklogs": returns(func(t *testing.T, f *fixture, worklogs []*model.Worklog) {
+		if len(worklogs) != 1 || worklogs[0].ID != f.worklogID || worklogs[0].Minutes != 30 {
+			t.Errorf("got worklogs %+v", worklogs)
+		}
+	}),
+	"POST /private/task/{id}/worklogs": returns(func(t *testing.T, f *fixture, wl *model.Worklog) {
+		if wl.TaskID != f.taskID || wl.Minutes != 15 || wl.WorkDate.Format("2006-01-02") != "2026-01-05" {
+			t.Errorf("got worklog %+v", wl)
+		}
+	}),
+	"PUT /private/task/{id}/worklogs/{id}": returns(func(t *testing.T, f *fixture, wl *model.Worklog) {
+		if wl.ID != f.worklogID || wl.Minutes != 45 {
+			t.Errorf("got worklog %+v", wl)
+		}
+	}),
+
+	"POST /private/teams/{id}/templates/{id}/tasks": returns(func(t *testing.T, f *fixture, task *model.Task) {
+		if !strings.HasPrefix(task.Name, "weekly ") || strings.Contains(task.Name, "{date}") || task.TeamID != f.teamID {
+			t.Errorf("got task %+v", task)
+		}
+	}),
+
+	"POST /private/teams/{id}/fields": returns(func(t *testing.T, f *fixture, field *model.CustomField) {
+		if field.TeamID != f.teamID || field.Key != "region" || field.Type != model.FieldText {
+			t.Errorf("got field %+v", field)
+		}
+	}),
+	"PUT /private/teams/{id}/fields/{id}": returns(func(t *testing.T, f *fixture, field *model.CustomField) {
+		if field.ID != f.fieldID || field.Name != "Environment" || field.Key != "env" {
+			t.Errorf("got field %+v", field)
+		}
+	}),
+
+	"POST /private/teams/{id}/labels": returns(func(t *testing.T, f *fixture, l *model.Label) {
+		if l.TeamID != f.teamID || l.Name != "feature" || l.Color != "#00aa00" {
+			t.Errorf("got label %+v", l)
+		}
+	}),
+	"PUT /private/teams/{id}/labels/{id}": returns(func(t *testing.T, f *fixture, l *model.Label) {
+		if l.ID != f.labelID || l.Name != "defect" || l.Color != "#aa0000" {
+			t.Errorf("got label %+v", l)
+		}
+	}),
+	"DELETE /private/task/{id}/labels/{id}": func(t *testing.T, f *fixture, rec *httptest.ResponseRecorder) {
+		task, err := f.store.Task().GetByID(f.taskID)
+		must(t, err)
+		if hasLabel(task.Labels, f.labelID) {
+			t.Error("label is still on the task")
+		}
+	},
+
+	"GET /private/task/{id}/attachments": returns(func(t *testing.T, f *fixture, list []*model.Attachment) {
+		if len(list) != 1 || list[0].ID != f.attachmentID || list[0].FileName != "notes.txt" {
+			t.Errorf("got attachments %+v", list)
+		}
+	}),
+	"POST /private/task/{id}/attachments": returns(func(t *testing.T, f *fixture, created []*model.Attachment) {
+		if len(created) != 1 || created[0].FileName != "todo.txt" || created[0].Size != int64(len("buy milk")) {
+			t.Fatalf("got attachments %+v", created)
+		}
+		a, err := f.store.Attachment().Find(created[0].ID)
+		must(t, err)
+		body, err := f.blobs.Get(context.Background(), a.StorageKey)
+		must(t, err)
+		defer body.Close()
+		if b, _ := io.ReadAll(body); string(b) != "buy milk" {
+			t.Errorf("stored %q", b)
+		}
+	}),
+	"GET /private/task/{id}/attachments/{id}": func(t *testing.T, f *fixture, rec *httptest.ResponseRecorder) {
+		if rec.Body.String() != "notes" || rec.Header().Get("Content-Type") != "text/plain" ||
+			rec.Header().Get("Content-Disposition") != `attachment; filename=notes.txt` {
+			t.Errorf("got %q with headers %v", rec.Body.String(), rec.Header())
+		}
+	},
+}
+
+func TestServer_TeamScopedRoutes(t *testing.T) {
+	for _, rt := range routes {
+		t.Run(rt.method+" "+rt.path.String(), func(t *testing.T) {
+			cases := []struct {
+				name   string
+				user   func(f *fixture) *model.User
+				forget bool
+				code   int
+			}{
+				{name: "owner", user: func(f *fixture) *model.User { return f.owner }, code: rt.ok},
+				{name: "member", user: func(f *fixture) *model.User { return f.member }, code: rt.member},
+				{name: "outsider", user: func(f *fixture) *model.User { return f.outsider }, code: http.StatusForbidden},
+				{name: "missing", user: func(f *fixture) *model.User { return f.owner }, forget: true, code: http.StatusNotFound},
+			}
+
+			for _, tc := range cases {
+				t.Run(tc.name, func(t *testing.T) {
+					f := newFixture(t)
+					if rt.setup != nil {
+						rt.setup(t, f)
+					}
+					if tc.forget {
+						f.forget()
+					}
+
+					var b interface{}
+					if rt.body != nil {
+						b = rt.body(f)
+					}
+
+					rec := f.do(t, tc.user(f), rt.method, rt.path.For(f), b)
+					if rec.Code != tc.code {
+						t.Fatalf("got %d, want %d: %s", rec.Code, tc.code, rec.Body.String())
+					}
+					if check := checks[rt.method+" "+rt.path.String()]; check != nil && rec.Code < 300 {
+						check(t, f, rec)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestServer_ChecksNameRoutes(t *testing.T) {
+	names := map[string]bool{}
+	for _, rt := range routes {
+		names[rt.method+" "+rt.path.String()] = true
+	}
+	for name := range checks {
+		if !names[name] {
+			t.Errorf("check %q has no route", name)
+		}
+	}
+}
+
+func TestServer_Unauthenticated(t *testing.T) {
+	f := newFixture(t)
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/private/teams/%d/tasks", f.teamID), nil)
+	rec := httptest.NewRecorder()
+	f.srv.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("got %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestServer_TaskBulk(t *testing.T) {
+	testCases := []struct {
+		name string
+		user func(f *fixture) *model.User
+		ids  func(f *fixture) []int
+		op   model.BulkOperation
+		code int
+	}{
+		{
+			name: "owner deletes",
+			user: func(f *fixture) *model.User { return f.owner },
+			ids:  func(f *fixture) []int { return []int{f.taskID, f.otherID} },
+			op:   model.BulkOperation{Action: model.BulkDelete},
+			code: http.StatusOK,
+		},
+		{
+			name: "member sets priority",
+			user: func(f *fixture) *model.User { return f.member },
+			ids:  func(f *fixture) []int { return []int{f.taskID, f.otherID} },
+			op:   model.BulkOperation{Action: model.BulkSetPriority, Priority: model.HightPriority},
+			code: http.StatusOK,
+		},
+		{
+			name: "member deletes",
+			user: func(f *fixture) *model.User { return f.member },
+			ids:  func(f *fixture) []int { return []int{f.taskID} },
+			op:   model.BulkOperation{Action: model.BulkDelete},
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "outsider",
+			user: func(f *fixture) *model.User { return f.outsider },
+			ids:  func(f *fixture) []int { return []int{f.taskID} },
+			op:   model.BulkOperation{Action: model.BulkSetPriority, Priority: model.HightPriority},
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "missing task rolls back the batch",
+			user: func(f *fixture) *model.User { return f.owner },
+			ids:  func(f *fixture) []int { return []int{f.taskID, missingID} },
+			op:   model.BulkOperation{Action: model.BulkDelete},
+			code: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(t)
+
+			rec := f.do(t, tc.user(f), http.MethodPost, "/private/tasks/bulk", map[string]interface{}{
+				"task_ids": tc.ids(f),
+				"action":   tc.op.Action,
+				"priority": tc.op.Priority,
+			})
+			if rec.Code != tc.code {
+				t.Errorf("got %d, want %d: %s", rec.Code, tc.code, rec.Body.String())
+			}
+
+			if tc.code != http.StatusOK {
+				if _, err := f.store.Task().GetByID(f.taskID); err != nil {
+					t.Errorf("task is gone after a failed batch: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+		t.Fatalf("decode %q: %v", rec.Body.String(), err)
+	}
+}
+
+func TestServer_TaskUpdate(t *testing.T) {
+	f := newFixture(t)
+	due := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	points := 3
+	status := model.StatusInProgress
+	must(t, f.store.Task().Update(f.taskID, &model.TaskPatch{
+		DueDate:     model.Optional[time.Time]{Set: true, Value: &due},
+		StoryPoints: model.Optional[int]{Set: true, Value: &points},
+		Status:      &status,
+	}, &f.owner.ID))
+
+	path := fmt.Sprintf("/private/task/%d", f.taskID)
+
+	// поля, которых нет в теле, не меняются
+	rec := f.do(t, f.member, http.MethodPut, path, map[string]string{"name": "renamed"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+	}
+	task := &model.Task{}
+	decode(t, rec, task)
+	if task.Name != "renamed" || task.Status != model.StatusInProgress || task.DueDate == nil || !task.DueDate.Equal(due) ||
+		task.StoryPoints == nil || *task.StoryPoints != 3 || task.AssigneeID == nil || *task.AssigneeID != f.member.ID {
+		t.Errorf("partial update touched other fields: %+v", task)
+	}
+
+	// null очищает поле
+	rec = f.do(t, f.member, http.MethodPut, path, map[string]interface{}{"due_date": nil, "assignee_id": nil})
+	task = &model.Task{}
+	decode(t, rec, task)
+	if task.DueDate != nil || task.AssigneeID != nil || len(task.AssigneeIDs) != 0 || task.StoryPoints == nil {
+		t.Errorf("null did not clear due_date and assignee_id only: %+v", task)
+	}
+
+	testCases := []struct {
+		name string
+		body interface{}
+		code int
+	}{
+		{name: "short name", body: map[string]string{"name": "ab"}, code: http.StatusUnprocessableEntity},
+		{name: "unknown status", body: map[string]string{"status": "review"}, code: http.StatusUnprocessableEntity},
+		{name: "unknown priority", body: map[string]string{"priority": "urgent"}, code: http.StatusUnprocessableEntity},
+		{name: "assignee outside the team", body: map[string]int{"assignee_id": f.outsider.ID}, code: http.StatusUnprocessableEntity},
+		{name: "bad recurrence", body: map[string]string{"recurrence": "FREQ=HOURLY"}, code: http.StatusUnprocessableEntity},
+		{name: "negative estimate", body: map[string]int{"estimate_minutes": -5}, code: http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := f.do(t, f.owner, http.MethodPut, path, tc.body)
+			if rec.Code != tc.code {
+				t.Errorf("got %d, want %d: %s", rec.Code, tc.code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestServer_RemoveMember(t *testing.T) {
+	f := newFixture(t)
+	path := fmt.Sprintf("/private/team/%d/members/%d", f.teamID, f.member.ID)
+
+	if rec := f.do(t, f.owner, http.MethodDelete, path, nil); rec.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	task, err := f.store.Task().GetByID(f.taskID)
+	must(t, err)
+	if task.AssigneeID != nil || len(task.AssigneeIDs) != 0 || len(task.WatcherIDs) != 0 {
+		t.Errorf("removed member still on the task: %+v", task)
+	}
+
+	if rec := f.do(t, f.owner, http.MethodDelete, path, nil); rec.Code != http.StatusNotFound {
+		t.Errorf("second removal: got %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestServer_AttachmentDelete(t *testing.T) {
+	f := newFixture(t)
+	a, err := f.store.Attachment().Find(f.attachmentID)
+	must(t, err)
+	path := fmt.Sprintf("/private/task/%d/attachments/%d", f.taskID, f.attachmentID)
+
+	if rec := f.do(t, f.member, http.MethodDelete, path, nil); rec.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec := f.do(t, f.member, http.MethodGet, fmt.Sprintf("/private/task/%d/attachments", f.taskID), nil)
+	var list []*model.Attachment
+	decode(t, rec, &list)
+	if len(list) != 0 {
+		t.Errorf("attachment still listed: %+v", list)
+	}
+	if _, err := f.blobs.Get(context.Background(), a.StorageKey); err != blob.ErrNotFound {
+		t.Errorf("blob: got %v, want %v", err, blob.ErrNotFound)
+	}
+
+	if rec := f.do(t, f.member, http.MethodDelete, path, nil); rec.Code != http.StatusNotFound {
+		t.Errorf("second delete: got %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
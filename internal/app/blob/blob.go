@@ -0,0 +1,41 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/qeery8/rest/internal/config"
+)
+
+var (
+	ErrUnknownStorage = errors.New("unknown attachment storage")
+	ErrNotFound       = errors.New("blob not found")
+	ErrInvalidKey     = errors.New("invalid blob key")
+)
+
+// Storage keeps file contents under keys like "tasks/12/3f9a...". Put
+// needs the size up front, so uploads are spooled before they are stored.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+func New(cfg config.Attachments) (Storage, error) {
+	switch cfg.Storage {
+	case "", "local":
+		return NewLocalStorage(cfg.LocalDir), nil
+	case "s3":
+		return NewS3Storage(S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+			Timeout:   cfg.Timeout.Duration,
+		})
+	}
+	return nil, ErrUnknownStorage
+}
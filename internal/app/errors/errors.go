@@ -6,9 +6,25 @@ var (
 	ErrTeamNotFound  = errors.New("team not found")
 	ErrInvalidTeamId = errors.New("invalid team id")
 	ErrTaskNotFound  = errors.New("task not found")
+
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrCommentNotFound    = errors.New("comment not found")
+
+	ErrChecklistItemNotFound = errors.New("checklist item not found")
+	ErrLabelNotFound         = errors.New("label not found")
+	ErrSprintNotFound        = errors.New("sprint not found")
+	ErrWorklogNotFound       = errors.New("worklog entry not found")
+	ErrTemplateNotFound      = errors.New("template not found")
+	ErrFieldNotFound         = errors.New("custom field not found")
+	ErrAttachmentNotFound    = errors.New("attachment not found")
+
+	ErrOwnerCannotLeave = errors.New("team owner cannot be removed from the team")
+
+	ErrReportRange = errors.New("report range must be positive and at most a year long")
 )
 
 var (
 	ErrIncorrectEmailOrPassword = errors.New("incorrect email or password")
 	ErrNotAuthenticated         = errors.New("not authenticated")
+	ErrForbidden                = errors.New("forbidden")
 )
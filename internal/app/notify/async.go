@@ -0,0 +1,34 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AsyncNotifier hands notifications to the wrapped notifier in the
+// background so request handlers do not wait on webhooks or mail.
+// Failures are logged.
+type AsyncNotifier struct {
+	next   Notifier
+	logger *logrus.Logger
+}
+
+func NewAsync(next Notifier, logger *logrus.Logger) *AsyncNotifier {
+	return &AsyncNotifier{
+		next:   next,
+		logger: logger,
+	}
+}
+
+func (n *AsyncNotifier) Notify(ctx context.Context, msg *Notification) error {
+	go func() {
+		if err := n.next.Notify(context.Background(), msg); err != nil {
+			n.logger.WithFields(logrus.Fields{
+				"kind": msg.Kind,
+				"to":   msg.To,
+			}).Errorf("notification failed: %v", err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/qeery8/rest/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+var ErrUnknownNotifier = errors.New("unknown notifier kind")
+
+type Notification struct {
+	Kind    string    `json:"kind"`
+	To      string    `json:"to"`
+	Subject string    `json:"subject"`
+	Body    string    `json:"body"`
+	TaskID  int       `json:"task_id,omitempty"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, n *Notification) error
+}
+
+func New(cfg config.Notifier, logger *logrus.Logger) (Notifier, error) {
+	switch cfg.Kind {
+	case "", "log":
+		return &LogNotifier{logger: logger}, nil
+	case "webhook":
+		return NewWebhookNotifier(cfg.WebhookURL, cfg.Timeout.Duration), nil
+	case "smtp":
+		return NewSMTPNotifier(cfg.SMTPAddr, cfg.SMTPFrom, cfg.Timeout.Duration), nil
+	}
+	return nil, ErrUnknownNotifier
+}
+
+type LogNotifier struct {
+	logger *logrus.Logger
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, msg *Notification) error {
+	n.logger.WithFields(logrus.Fields{
+		"kind":    msg.Kind,
+		"to":      msg.To,
+		"task_id": msg.TaskID,
+	}).Info(msg.Subject)
+	return nil
+}
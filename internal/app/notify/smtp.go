@@ -0,0 +1,117 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+var ErrHeaderLineBreak = errors.New("mail header cannot contain line breaks")
+
+// SMTPNotifier sends plain text mail without authentication. It is meant
+// for a local relay or a mail catcher in development. Each message has to
+// go through within the timeout and before the context is done.
+type SMTPNotifier struct {
+	addr    string
+	from    string
+	timeout time.Duration
+}
+
+func NewSMTPNotifier(addr string, from string, timeout time.Duration) *SMTPNotifier {
+	return &SMTPNotifier{
+		addr:    addr,
+		from:    from,
+		timeout: timeout,
+	}
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, msg *Notification) error {
+	if msg.To == "" {
+		return nil
+	}
+
+	// адрес подставляется в заголовок как есть, тема кодируется целиком
+	if strings.ContainsAny(msg.To, "\r\n") {
+		return ErrHeaderLineBreak
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(msg.Body)
+	b.WriteString("\r\n")
+
+	return n.send(ctx, msg.To, b.String())
+}
+
+// send does what smtp.SendMail does, but over a connection with a deadline
+// that a cancelled context cuts short.
+func (n *SMTPNotifier) send(ctx context.Context, to string, body string) error {
+	if n.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.timeout)
+		defer cancel()
+	}
+
+	dialer := &net.Dialer{Timeout: n.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	host, _, err := net.SplitHostPort(n.addr)
+	if err != nil {
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(n.from); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(body)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
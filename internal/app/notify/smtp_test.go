@@ -0,0 +1,98 @@
+package notify_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qeery8/rest/internal/app/notify"
+)
+
+// hangingServer accepts connections and never says a word.
+func hangingServer(t *testing.T) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu    sync.Mutex
+		conns []net.Conn
+	)
+	t.Cleanup(func() {
+		l.Close()
+		mu.Lock()
+		defer mu.Unlock()
+		for _, conn := range conns {
+			conn.Close()
+		}
+	})
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+		}
+	}()
+
+	return l.Addr().String()
+}
+
+func TestSMTPNotifier_Timeout(t *testing.T) {
+	addr := hangingServer(t)
+	msg := &notify.Notification{To: "user@example.org", Subject: "hi", Body: "hi"}
+
+	testCases := []struct {
+		name    string
+		timeout time.Duration
+		ctx     func() (context.Context, context.CancelFunc)
+	}{
+		{
+			name:    "timeout",
+			timeout: 100 * time.Millisecond,
+			ctx:     func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+		},
+		{
+			name:    "context",
+			timeout: time.Minute,
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 100*time.Millisecond)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := tc.ctx()
+			defer cancel()
+
+			n := notify.NewSMTPNotifier(addr, "tracker@localhost", tc.timeout)
+
+			start := time.Now()
+			if err := n.Notify(ctx, msg); err == nil {
+				t.Fatal("want an error from a server that never answers")
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("gave up after %v", elapsed)
+			}
+		})
+	}
+}
+
+func TestSMTPNotifier_HeaderLineBreak(t *testing.T) {
+	n := notify.NewSMTPNotifier("127.0.0.1:1", "tracker@localhost", time.Second)
+
+	err := n.Notify(context.Background(), &notify.Notification{To: "a@example.org\r\nBcc: b@example.org"})
+	if err != notify.ErrHeaderLineBreak {
+		t.Errorf("got %v, want %v", err, notify.ErrHeaderLineBreak)
+	}
+}
@@ -0,0 +1,60 @@
+package policy
+
+import (
+	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type Policy struct {
+	store store.Store
+}
+
+func New(store store.Store) *Policy {
+	return &Policy{
+		store: store,
+	}
+}
+
+// Role returns the caller's role in the team. Outsiders get an empty role
+// and no error, so callers can decide between 403 and 404 themselves.
+func (p *Policy) Role(u *model.User, teamID int) (model.TeamRole, error) {
+	if _, err := p.store.Team().Find(teamID); err != nil {
+		if err == store.ErrRecordNotFound {
+			return "", errors.ErrTeamNotFound
+		}
+		return "", err
+	}
+
+	role, err := p.store.Team().MemberRole(teamID, u.ID)
+	if err == store.ErrUserNotInTeam {
+		return "", nil
+	}
+	return role, err
+}
+
+func (p *Policy) Authorize(u *model.User, teamID int, perm model.Permission) (model.TeamRole, error) {
+	role, err := p.Role(u, teamID)
+	if err != nil {
+		return "", err
+	}
+	if !role.Can(perm) {
+		return role, errors.ErrForbidden
+	}
+	return role, nil
+}
+
+func (p *Policy) AuthorizeTask(u *model.User, taskID int, perm model.Permission) (*model.Task, error) {
+	task, err := p.store.Task().GetByID(taskID)
+	if err != nil {
+		if err == store.ErrRecordNotFound {
+			return nil, errors.ErrTaskNotFound
+		}
+		return nil, err
+	}
+
+	if _, err := p.Authorize(u, task.TeamID, perm); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
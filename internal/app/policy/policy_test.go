@@ -0,0 +1,148 @@
+package policy_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store/teststore"
+)
+
+type team struct {
+	store *teststore.Store
+	id    int
+	task  int
+	users map[model.TeamRole]*model.User
+}
+
+// outsider stands for a user outside the team in the matrix below.
+const outsider model.TeamRole = ""
+
+func newTeam(t *testing.T) *team {
+	t.Helper()
+
+	st := teststore.New()
+	tm := &team{store: st, users: map[model.TeamRole]*model.User{}}
+
+	for i, role := range []model.TeamRole{model.RoleOwner, model.RoleAdmin, model.RoleMember, model.RoleViewer, outsider} {
+		u := &model.User{Email: fmt.Sprintf("user%d@example.org", i), EncryptedPassword: "-"}
+		if err := st.User().Create(u); err != nil {
+			t.Fatal(err)
+		}
+		tm.users[role] = u
+	}
+
+	owner := tm.users[model.RoleOwner]
+	team := &model.Team{Name: "team", OwnerID: owner.ID}
+	if err := st.Team().Create(team); err != nil {
+		t.Fatal(err)
+	}
+	tm.id = team.ID
+
+	for _, role := range []model.TeamRole{model.RoleAdmin, model.RoleMember, model.RoleViewer} {
+		if err := st.Team().AddMembers(team.ID, tm.users[role].ID, role); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	task := &model.Task{Name: "task", TeamID: team.ID}
+	if err := st.Task().Create(task, &owner.ID); err != nil {
+		t.Fatal(err)
+	}
+	tm.task = task.ID
+
+	return tm
+}
+
+func TestPolicy_Authorize(t *testing.T) {
+	allowed := map[model.TeamRole][]model.Permission{
+		model.RoleOwner: {
+			model.PermViewTeam, model.PermUpdateTeam, model.PermManageMembers, model.PermTransferOwnership,
+			model.PermEditTasks, model.PermDeleteTasks, model.PermComment,
+		},
+		model.RoleAdmin: {
+			model.PermViewTeam, model.PermUpdateTeam, model.PermManageMembers,
+			model.PermEditTasks, model.PermDeleteTasks, model.PermComment,
+		},
+		model.RoleMember: {
+			model.PermViewTeam, model.PermEditTasks, model.PermComment,
+		},
+		model.RoleViewer: {
+			model.PermViewTeam,
+		},
+		outsider: {},
+	}
+	perms := []model.Permission{
+		model.PermViewTeam, model.PermUpdateTeam, model.PermManageMembers, model.PermTransferOwnership,
+		model.PermEditTasks, model.PermDeleteTasks, model.PermComment,
+	}
+
+	tm := newTeam(t)
+	p := policy.New(tm.store)
+
+	for role, granted := range allowed {
+		for _, perm := range perms {
+			want := error(errors.ErrForbidden)
+			for _, g := range granted {
+				if g == perm {
+					want = nil
+				}
+			}
+
+			name := string(role)
+			if role == outsider {
+				name = "outsider"
+			}
+
+			t.Run(name+" "+string(perm), func(t *testing.T) {
+				got, err := p.Authorize(tm.users[role], tm.id, perm)
+				if err != want {
+					t.Errorf("Authorize: got %v, want %v", err, want)
+				}
+				if got != role {
+					t.Errorf("Authorize role: got %q, want %q", got, role)
+				}
+
+				if _, err := p.AuthorizeTask(tm.users[role], tm.task, perm); err != want {
+					t.Errorf("AuthorizeTask: got %v, want %v", err, want)
+				}
+			})
+		}
+	}
+}
+
+func TestPolicy_NotFound(t *testing.T) {
+	tm := newTeam(t)
+	p := policy.New(tm.store)
+	owner := tm.users[model.RoleOwner]
+
+	if _, err := p.Role(owner, tm.id+1000); err != errors.ErrTeamNotFound {
+		t.Errorf("Role: got %v, want %v", err, errors.ErrTeamNotFound)
+	}
+	if _, err := p.Authorize(owner, tm.id+1000, model.PermViewTeam); err != errors.ErrTeamNotFound {
+		t.Errorf("Authorize: got %v, want %v", err, errors.ErrTeamNotFound)
+	}
+	if _, err := p.AuthorizeTask(owner, tm.task+1000, model.PermViewTeam); err != errors.ErrTaskNotFound {
+		t.Errorf("AuthorizeTask: got %v, want %v", err, errors.ErrTaskNotFound)
+	}
+
+	// задача в архиве для политики не существует
+	if err := tm.store.Archive().Abandon(tm.task, "stale", &owner.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.AuthorizeTask(owner, tm.task, model.PermViewTeam); err != errors.ErrTaskNotFound {
+		t.Errorf("AuthorizeTask archived: got %v, want %v", err, errors.ErrTaskNotFound)
+	}
+}
+
+func TestPolicy_RoleOfOutsider(t *testing.T) {
+	tm := newTeam(t)
+	p := policy.New(tm.store)
+
+	role, err := p.Role(tm.users[outsider], tm.id)
+	if err != nil || role != "" {
+		t.Errorf("Role: got %q, %v; want empty role and no error", role, err)
+	}
+}
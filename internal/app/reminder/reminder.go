@@ -0,0 +1,107 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/qeery8/rest/internal/app/notify"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+	"github.com/sirupsen/logrus"
+)
+
+type Service struct {
+	store     store.Store
+	notifier  notify.Notifier
+	leadTimes []time.Duration
+	logger    *logrus.Logger
+	now       func() time.Time
+}
+
+func New(store store.Store, notifier notify.Notifier, leadTimes []time.Duration, logger *logrus.Logger) *Service {
+	leads := append([]time.Duration(nil), leadTimes...)
+	sort.Slice(leads, func(i, j int) bool { return leads[i] < leads[j] })
+
+	return &Service{
+		store:     store,
+		notifier:  notifier,
+		leadTimes: leads,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Run sends reminders for tasks that are due soon or overdue. It is safe to
+// run repeatedly: only reminders no earlier run has recorded are loaded,
+// and each one is recorded again right before delivery in case another
+// run got there first.
+func (s *Service) Run(ctx context.Context) error {
+	now := s.now()
+
+	var pending []*model.PendingReminder
+
+	if len(s.leadTimes) > 0 {
+		maxLead := s.leadTimes[len(s.leadTimes)-1]
+		soon, err := s.store.Reminder().Pending(model.ReminderDueSoon, now, now.Add(maxLead), s.leadTimes)
+		if err != nil {
+			return err
+		}
+		pending = append(pending, soon...)
+	}
+
+	overdue, err := s.store.Reminder().Pending(model.ReminderOverdue, time.Time{}, now, nil)
+	if err != nil {
+		return err
+	}
+	pending = append(pending, overdue...)
+
+	// ошибка по одному напоминанию не должна мешать остальным, возвращаем первую
+	var firstErr error
+	for _, p := range pending {
+		if err := s.deliver(ctx, p); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+func (s *Service) deliver(ctx context.Context, p *model.PendingReminder) error {
+	rem := &p.Reminder
+
+	created, err := s.store.Reminder().Record(rem)
+	if err != nil || !created {
+		return err
+	}
+
+	n := &notify.Notification{
+		Kind:    string(p.Kind),
+		To:      p.Email,
+		Subject: subject(p),
+		Body:    fmt.Sprintf("Task #%d %q is due %s.", p.TaskID, p.TaskName, p.DueDate.Format(time.RFC1123)),
+		TaskID:  p.TaskID,
+		SentAt:  s.now(),
+	}
+
+	if err := s.notifier.Notify(ctx, n); err != nil {
+		// снимаем отметку, чтобы повторить на следующем прогоне
+		if delErr := s.store.Reminder().Delete(rem.ID); delErr != nil {
+			s.logger.WithFields(logrus.Fields{
+				"reminder_id": rem.ID,
+				"task_id":     p.TaskID,
+			}).Errorf("reminder will not be retried: %v", delErr)
+		}
+		return err
+	}
+
+	return s.store.Reminder().MarkSent(rem.ID)
+}
+
+func subject(p *model.PendingReminder) string {
+	if p.Kind == model.ReminderOverdue {
+		return fmt.Sprintf("Overdue: %s", p.TaskName)
+	}
+	return fmt.Sprintf("Due soon: %s", p.TaskName)
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type JobFunc func(ctx context.Context) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	fn       JobFunc
+}
+
+// Scheduler runs registered jobs periodically until its context is done.
+// Every job runs once right after Start and then on its own ticker.
+type Scheduler struct {
+	logger *logrus.Logger
+	jobs   []job
+	wg     sync.WaitGroup
+}
+
+func New(logger *logrus.Logger) *Scheduler {
+	return &Scheduler{
+		logger: logger,
+	}
+}
+
+func (s *Scheduler) Add(name string, interval time.Duration, fn JobFunc) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	s.jobs = append(s.jobs, job{
+		name:     name,
+		interval: interval,
+		fn:       fn,
+	})
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.run(ctx, j)
+	}
+}
+
+// Wait blocks until every job goroutine has returned.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run(ctx context.Context, j job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		if err := j.fn(ctx); err != nil {
+			s.logger.WithField("job", j.name).Errorf("job failed: %v", err)
+		} else {
+			s.logger.WithField("job", j.name).Debugf("job done in %v", time.Since(start))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
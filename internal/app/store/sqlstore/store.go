@@ -3,7 +3,7 @@ package sqlstore
 import (
 	"database/sql"
 
-	"github.com/qeery8/rest/internal/store"
+	"github.com/qeery8/rest/internal/app/store"
 )
 
 type Store struct {
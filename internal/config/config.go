@@ -1,15 +1,110 @@
 package config
 
+import "time"
+
 type Config struct {
 	BindAddr    string `toml:"bind_addr"`
 	LogLevel    string `toml:"log_level"`
 	DatabaseURL string `toml:"database_url"`
 	SessionKey  string `toml:"session_key"`
+
+	Reminders Reminders `toml:"reminders"`
+	Notifier  Notifier  `toml:"notifier"`
+	Archive   Archive   `toml:"archive"`
+
+	Attachments Attachments `toml:"attachments"`
+}
+
+type Reminders struct {
+	Enabled   bool       `toml:"enabled"`
+	Interval  Duration   `toml:"interval"`
+	LeadTimes []Duration `toml:"lead_times"`
+}
+
+// Archive controls automatic abandoning of tasks that have sat in an
+// in-progress status, without moving on, for longer than StaleAfter.
+type Archive struct {
+	Enabled    bool     `toml:"enabled"`
+	Interval   Duration `toml:"interval"`
+	StaleAfter Duration `toml:"stale_after"`
+}
+
+type Notifier struct {
+	Kind       string   `toml:"kind"`
+	WebhookURL string   `toml:"webhook_url"`
+	Timeout    Duration `toml:"timeout"`
+	SMTPAddr   string   `toml:"smtp_addr"`
+	SMTPFrom   string   `toml:"smtp_from"`
+}
+
+// Attachments picks where uploaded files are kept and what is accepted.
+// AllowedTypes holds MIME types, "image/*" style wildcards included.
+type Attachments struct {
+	Storage      string   `toml:"storage"`
+	LocalDir     string   `toml:"local_dir"`
+	MaxSizeMB    int64    `toml:"max_size_mb"`
+	AllowedTypes []string `toml:"allowed_types"`
+
+	S3Endpoint  string   `toml:"s3_endpoint"`
+	S3Region    string   `toml:"s3_region"`
+	S3Bucket    string   `toml:"s3_bucket"`
+	S3AccessKey string   `toml:"s3_access_key"`
+	S3SecretKey string   `toml:"s3_secret_key"`
+	S3PathStyle bool     `toml:"s3_path_style"`
+	Timeout     Duration `toml:"timeout"`
+}
+
+// Duration lets durations be written as strings like "15m" in TOML.
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = v
+	return nil
 }
 
 func NewConfig() *Config {
 	return &Config{
 		BindAddr: ":8080",
 		LogLevel: "debug",
+		Reminders: Reminders{
+			Enabled:  true,
+			Interval: Duration{time.Minute},
+			LeadTimes: []Duration{
+				{24 * time.Hour},
+				{time.Hour},
+			},
+		},
+		Archive: Archive{
+			Enabled:    false,
+			Interval:   Duration{time.Hour},
+			StaleAfter: Duration{30 * 24 * time.Hour},
+		},
+		Notifier: Notifier{
+			Kind:     "log",
+			Timeout:  Duration{10 * time.Second},
+			SMTPAddr: "localhost:1025",
+			SMTPFrom: "tracker@localhost",
+		},
+		Attachments: Attachments{
+			Storage:   "local",
+			LocalDir:  "data/attachments",
+			MaxSizeMB: 25,
+			AllowedTypes: []string{
+				"image/*",
+				"text/plain",
+				"application/pdf",
+				"application/zip",
+				"application/x-gzip",
+			},
+			S3Region:    "us-east-1",
+			S3PathStyle: true,
+			Timeout:     Duration{time.Minute},
+		},
 	}
 }
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// AbandonedTask is an archived task with the reason it was abandoned.
+type AbandonedTask struct {
+	Task
+	Reason      string    `json:"reason"`
+	AbandonedBy *int      `json:"abandoned_by"`
+	AbandonedAt time.Time `json:"abandoned_at"`
+}
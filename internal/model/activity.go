@@ -0,0 +1,150 @@
+package model
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ActivityAction string
+
+const (
+	ActivityCreated   ActivityAction = "created"
+	ActivityUpdated   ActivityAction = "updated"
+	ActivityDeleted   ActivityAction = "deleted"
+	ActivityAbandoned ActivityAction = "abandoned"
+	ActivityRestored  ActivityAction = "restored"
+)
+
+// Activity is one entry of a task's history. Updates produce one entry per
+// changed field; other actions leave Field empty.
+type Activity struct {
+	ID        int            `json:"id"`
+	TaskID    int            `json:"task_id"`
+	TeamID    int            `json:"team_id"`
+	ActorID   *int           `json:"actor_id"`
+	Action    ActivityAction `json:"action"`
+	Field     *string        `json:"field,omitempty"`
+	OldValue  *string        `json:"old_value"`
+	NewValue  *string        `json:"new_value"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+func NewActivity(t *Task, actorID *int, action ActivityAction) *Activity {
+	return &Activity{
+		TaskID:  t.ID,
+		TeamID:  t.TeamID,
+		ActorID: actorID,
+		Action:  action,
+	}
+}
+
+// DiffTasks returns an update entry for every field that differs between
+// the old and the new version of a task. Custom fields are named
+// "fields.<key>".
+func DiffTasks(old *Task, new *Task, actorID *int) []*Activity {
+	var changes []*Activity
+
+	add := func(field string, before, after *string) {
+		if equalPtr(before, after) {
+			return
+		}
+		a := NewActivity(new, actorID, ActivityUpdated)
+		a.Field = &field
+		a.OldValue = before
+		a.NewValue = after
+		changes = append(changes, a)
+	}
+
+	add("name", strPtr(old.Name), strPtr(new.Name))
+	add("content", strPtr(old.Content), strPtr(new.Content))
+	add("status", strPtr(string(old.Status)), strPtr(string(new.Status)))
+	add("priority", strPtr(string(old.Priority)), strPtr(string(new.Priority)))
+	add("due_date", timeStr(old.DueDate), timeStr(new.DueDate))
+	add("assignee_id", intStr(old.AssigneeID), intStr(new.AssigneeID))
+	add("assignee_ids", idsStr(old.AssigneeIDs), idsStr(new.AssigneeIDs))
+	add("parent_id", intStr(old.ParentID), intStr(new.ParentID))
+	add("sprint_id", intStr(old.SprintID), intStr(new.SprintID))
+	add("labels", labelsStr(old.Labels), labelsStr(new.Labels))
+	add("story_points", intStr(old.StoryPoints), intStr(new.StoryPoints))
+	add("estimate_minutes", intStr(old.EstimateMinutes), intStr(new.EstimateMinutes))
+	add("recurrence", old.Recurrence, new.Recurrence)
+
+	keys := make([]string, 0, len(old.Fields)+len(new.Fields))
+	for k := range old.Fields {
+		keys = append(keys, k)
+	}
+	for k := range new.Fields {
+		if _, ok := old.Fields[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		add("fields."+k, fieldStr(old.Fields[k]), fieldStr(new.Fields[k]))
+	}
+
+	return changes
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func intStr(v *int) *string {
+	if v == nil {
+		return nil
+	}
+	return strPtr(strconv.Itoa(*v))
+}
+
+// idsStr joins ids with commas; an empty list has no value.
+func idsStr(ids []int) *string {
+	if len(ids) == 0 {
+		return nil
+	}
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strPtr(strings.Join(parts, ","))
+}
+
+// labelsStr lists label ids in the order the task shows them.
+func labelsStr(labels []LabelRef) *string {
+	ids := make([]int, len(labels))
+	for i, l := range labels {
+		ids[i] = l.ID
+	}
+	return idsStr(ids)
+}
+
+func fieldStr(v interface{}) *string {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case float64:
+		return strPtr(FormatFieldNumber(v))
+	case string:
+		return &v
+	default:
+		b, _ := json.Marshal(v)
+		return strPtr(string(b))
+	}
+}
+
+func timeStr(v *time.Time) *string {
+	if v == nil {
+		return nil
+	}
+	return strPtr(v.UTC().Format(time.RFC3339))
+}
+
+func equalPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,166 @@
+package model_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+)
+
+// change is an update entry reduced to comparable strings; nil reads "-".
+type change struct {
+	field, old, new string
+}
+
+func changes(t *testing.T, activity []*model.Activity) []change {
+	t.Helper()
+
+	str := func(s *string) string {
+		if s == nil {
+			return "-"
+		}
+		return *s
+	}
+
+	var got []change
+	for _, a := range activity {
+		if a.Action != model.ActivityUpdated || a.Field == nil {
+			t.Fatalf("unexpected entry %+v", a)
+		}
+		got = append(got, change{*a.Field, str(a.OldValue), str(a.NewValue)})
+	}
+	return got
+}
+
+func TestDiffTasks(t *testing.T) {
+	one, two := 1, 2
+	due := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	daily := "FREQ=DAILY"
+
+	base := func() *model.Task {
+		return &model.Task{
+			ID:          7,
+			TeamID:      3,
+			Name:        "write report",
+			Status:      model.StatusToDo,
+			Priority:    model.MediumPriority,
+			AssigneeID:  &one,
+			AssigneeIDs: []int{1},
+			Labels:      []model.LabelRef{{ID: 5, Name: "docs"}},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		change func(t *model.Task)
+		want   []change
+	}{
+		{
+			name:   "nothing changed",
+			change: func(t *model.Task) {},
+		},
+		{
+			name: "computed fields are not history",
+			change: func(t *model.Task) {
+				t.Rank = 100
+				t.SpentMinutes = 30
+				t.Blocked = true
+				t.UpdatedAt = time.Now()
+			},
+		},
+		{
+			name:   "name",
+			change: func(t *model.Task) { t.Name = "write the report" },
+			want:   []change{{"name", "write report", "write the report"}},
+		},
+		{
+			name: "status and priority",
+			change: func(t *model.Task) {
+				t.Status = model.StatusInProgress
+				t.Priority = model.HightPriority
+			},
+			want: []change{
+				{"status", "todo", "in_progress"},
+				{"priority", "medium", "high"},
+			},
+		},
+		{
+			name:   "due date is set in UTC",
+			change: func(t *model.Task) { t.DueDate = &due },
+			want:   []change{{"due_date", "-", "2026-03-01T09:00:00Z"}},
+		},
+		{
+			name: "second assignee",
+			change: func(t *model.Task) {
+				t.AssigneeIDs = []int{1, 2}
+			},
+			want: []change{{"assignee_ids", "1", "1,2"}},
+		},
+		{
+			name: "unassigned",
+			change: func(t *model.Task) {
+				t.AssigneeID = nil
+				t.AssigneeIDs = nil
+			},
+			want: []change{
+				{"assignee_id", "1", "-"},
+				{"assignee_ids", "1", "-"},
+			},
+		},
+		{
+			name:   "same value at another address",
+			change: func(t *model.Task) { same := 1; t.AssigneeID = &same },
+		},
+		{
+			name: "label added in front",
+			change: func(t *model.Task) {
+				t.Labels = []model.LabelRef{{ID: 6}, {ID: 5}}
+			},
+			want: []change{{"labels", "5", "6,5"}},
+		},
+		{
+			name: "estimates and recurrence",
+			change: func(t *model.Task) {
+				t.StoryPoints = &two
+				t.EstimateMinutes = &one
+				t.Recurrence = &daily
+			},
+			want: []change{
+				{"story_points", "-", "2"},
+				{"estimate_minutes", "-", "1"},
+				{"recurrence", "-", "FREQ=DAILY"},
+			},
+		},
+		{
+			name: "custom fields in key order",
+			change: func(t *model.Task) {
+				t.Fields = model.FieldValues{"points": 2.50, "env": "prod", "tags": []string{"a", "b"}}
+			},
+			want: []change{
+				{"fields.env", "-", "prod"},
+				{"fields.points", "-", "2.5"},
+				{"fields.tags", "-", `["a","b"]`},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			old, updated := base(), base()
+			tc.change(updated)
+
+			actor := 9
+			activity := model.DiffTasks(old, updated, &actor)
+			for _, a := range activity {
+				if a.TaskID != 7 || a.TeamID != 3 || a.ActorID == nil || *a.ActorID != actor {
+					t.Errorf("entry not tied to the task and actor: %+v", a)
+				}
+			}
+
+			if got := changes(t, activity); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
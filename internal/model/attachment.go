@@ -0,0 +1,72 @@
+package model
+
+import (
+	"errors"
+	"mime"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	ErrAttachmentType     = errors.New("attachment type is not allowed")
+	ErrAttachmentEmpty    = errors.New("attachment is empty")
+)
+
+// Attachment is a file uploaded to a task. StorageKey locates the contents
+// in the blob storage and is never shown to clients.
+type Attachment struct {
+	ID          int       `json:"id"`
+	TaskID      int       `json:"task_id"`
+	StorageKey  string    `json:"-"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	UploadedBy  *int      `json:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CleanFileName keeps the base name of an uploaded file without control
+// characters, so it is safe to echo back in a Content-Disposition header.
+func CleanFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+
+	if name == "" || name == "." || name == "/" {
+		name = "file"
+	}
+	if len(name) > 255 {
+		ext := path.Ext(name)
+		if len(ext) > 20 {
+			ext = ""
+		}
+		name = strings.ToValidUTF8(name[:255-len(ext)], "") + ext
+	}
+	return name
+}
+
+// TypeAllowed matches a content type against patterns like "text/plain"
+// or "image/*". Parameters such as charset are ignored.
+func TypeAllowed(contentType string, patterns []string) bool {
+	media, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, p := range patterns {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p == media || p == "*/*" {
+			return true
+		}
+		if strings.HasSuffix(p, "/*") && strings.HasPrefix(media, strings.TrimSuffix(p, "*")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package model
+
+type BoardColumn struct {
+	Status   TaskStatus     `json:"status"`
+	Name     string         `json:"name"`
+	Category StatusCategory `json:"category"`
+	Tasks    []*Task        `json:"tasks"`
+}
+
+// Board is a team's tasks laid out by workflow status. Columns follow the
+// workflow order, cards within a column follow Task.Rank.
+type Board struct {
+	TeamID  int            `json:"team_id"`
+	Columns []*BoardColumn `json:"columns"`
+}
+
+// NewBoard groups tasks, already sorted by rank, into the workflow columns.
+func NewBoard(wf *Workflow, tasks []*Task) *Board {
+	b := &Board{TeamID: wf.TeamID}
+	columns := make(map[TaskStatus]*BoardColumn, len(wf.Statuses))
+
+	for _, s := range wf.Statuses {
+		c := &BoardColumn{
+			Status:   s.Key,
+			Name:     s.Name,
+			Category: s.Category,
+			Tasks:    []*Task{},
+		}
+		columns[s.Key] = c
+		b.Columns = append(b.Columns, c)
+	}
+
+	for _, t := range tasks {
+		if c, ok := columns[t.Status]; ok {
+			c.Tasks = append(c.Tasks, t)
+		}
+	}
+	return b
+}
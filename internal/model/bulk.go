@@ -0,0 +1,94 @@
+package model
+
+import "errors"
+
+type BulkAction string
+
+const (
+	BulkSetStatus   BulkAction = "set_status"
+	BulkSetPriority BulkAction = "set_priority"
+	BulkAssign      BulkAction = "assign"
+	BulkAddLabel    BulkAction = "add_label"
+	BulkDelete      BulkAction = "delete"
+	BulkAbandon     BulkAction = "abandon"
+)
+
+const MaxBulkTasks = 200
+
+var (
+	ErrBulkAction     = errors.New("unknown bulk action")
+	ErrBulkTasks      = errors.New("bulk request needs 1 to 200 task ids")
+	ErrBulkParams     = errors.New("bulk action is missing its status, priority, user_id or label_id")
+	ErrBulkRolledBack = errors.New("not applied: another task failed and the batch was rolled back")
+)
+
+// BulkOperation is one change applied to many tasks. Only the parameter
+// of the chosen action is used.
+type BulkOperation struct {
+	Action   BulkAction   `json:"action"`
+	Status   TaskStatus   `json:"status,omitempty"`
+	Priority TaskPriority `json:"priority,omitempty"`
+	UserID   *int         `json:"user_id,omitempty"`
+	LabelID  *int         `json:"label_id,omitempty"`
+	Reason   string       `json:"reason,omitempty"`
+}
+
+// BulkResult is the outcome for one task of a bulk request.
+type BulkResult struct {
+	TaskID int    `json:"task_id"`
+	OK     bool   `json:"ok"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BulkReport struct {
+	Atomic  bool          `json:"atomic"`
+	Applied int           `json:"applied"`
+	Failed  int           `json:"failed"`
+	Results []*BulkResult `json:"results"`
+}
+
+func (o *BulkOperation) Validate() error {
+	switch o.Action {
+	case BulkSetStatus:
+		if !o.Status.Valid() {
+			return ErrBulkParams
+		}
+	case BulkSetPriority:
+		if !o.Priority.Valid() {
+			return ErrBulkParams
+		}
+	case BulkAssign:
+		if o.UserID == nil {
+			return ErrBulkParams
+		}
+	case BulkAddLabel:
+		if o.LabelID == nil {
+			return ErrBulkParams
+		}
+	case BulkDelete, BulkAbandon:
+	default:
+		return ErrBulkAction
+	}
+	return nil
+}
+
+// Permission is what the caller needs in the team of every task.
+func (o *BulkOperation) Permission() Permission {
+	if o.Action == BulkDelete {
+		return PermDeleteTasks
+	}
+	return PermEditTasks
+}
+
+// NewBulkReport counts the results.
+func NewBulkReport(atomic bool, results []*BulkResult) *BulkReport {
+	rep := &BulkReport{Atomic: atomic, Results: results}
+	for _, res := range results {
+		if res.OK {
+			rep.Applied++
+		} else {
+			rep.Failed++
+		}
+	}
+	return rep
+}
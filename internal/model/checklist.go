@@ -0,0 +1,33 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrChecklistTitleEmpty   = errors.New("checklist item title is empty")
+	ErrChecklistTitleTooLong = errors.New("checklist item title too long")
+)
+
+type ChecklistItem struct {
+	ID        int       `json:"id"`
+	TaskID    int       `json:"task_id"`
+	Title     string    `json:"title"`
+	Done      bool      `json:"done"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (i *ChecklistItem) Validate() error {
+	title := strings.TrimSpace(i.Title)
+	if title == "" {
+		return ErrChecklistTitleEmpty
+	}
+	if len(title) > 255 {
+		return ErrChecklistTitleTooLong
+	}
+	return nil
+}
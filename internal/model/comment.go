@@ -0,0 +1,86 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const MaxCommentLength = 10000
+
+var (
+	ErrCommentEmpty   = errors.New("comment is empty")
+	ErrCommentTooLong = errors.New("comment too long")
+)
+
+var mentionRe = regexp.MustCompile(`(?:^|[^\w@])@([\w.+-]+@[\w-]+(?:\.[\w-]+)+)`)
+
+type Comment struct {
+	ID        int        `json:"id"`
+	TaskID    int        `json:"task_id"`
+	AuthorID  *int       `json:"author_id"`
+	ParentID  *int       `json:"parent_id"`
+	Body      string     `json:"body"`
+	Mentions  []int      `json:"mentions"`
+	Edited    bool       `json:"edited"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Replies   []*Comment `json:"replies,omitempty"`
+}
+
+type CommentRevision struct {
+	ID        int       `json:"id"`
+	CommentID int       `json:"comment_id"`
+	Body      string    `json:"body"`
+	EditedBy  *int      `json:"edited_by"`
+	EditedAt  time.Time `json:"edited_at"`
+}
+
+func (c *Comment) Validate() error {
+	body := strings.TrimSpace(c.Body)
+	if body == "" {
+		return ErrCommentEmpty
+	}
+	if len(body) > MaxCommentLength {
+		return ErrCommentTooLong
+	}
+	return nil
+}
+
+// MentionedEmails returns the distinct lower-cased addresses written as
+// @user@example.com in the body.
+func (c *Comment) MentionedEmails() []string {
+	seen := map[string]bool{}
+	var emails []string
+
+	for _, m := range mentionRe.FindAllStringSubmatch(c.Body, -1) {
+		email := strings.ToLower(strings.TrimRight(m[1], "."))
+		if !seen[email] {
+			seen[email] = true
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+// BuildThreads nests replies under their parents. The input must be ordered
+// by creation time; the order is kept inside every level.
+func BuildThreads(comments []*Comment) []*Comment {
+	byID := make(map[int]*Comment, len(comments))
+	for _, c := range comments {
+		byID[c.ID] = c
+	}
+
+	roots := []*Comment{}
+	for _, c := range comments {
+		if c.ParentID != nil {
+			if parent, ok := byID[*c.ParentID]; ok {
+				parent.Replies = append(parent.Replies, c)
+				continue
+			}
+		}
+		roots = append(roots, c)
+	}
+	return roots
+}
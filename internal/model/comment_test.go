@@ -0,0 +1,105 @@
+package model_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/qeery8/rest/internal/model"
+)
+
+func TestComment_MentionedEmails(t *testing.T) {
+	testCases := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{name: "none", body: "looks good to me"},
+		{name: "at the start", body: "@ann@example.org please check", want: []string{"ann@example.org"}},
+		{name: "after punctuation", body: "cc:@ann@example.org,(@bob@mail.example.org)", want: []string{"ann@example.org", "bob@mail.example.org"}},
+		{name: "end of sentence", body: "ask @ann@example.org.", want: []string{"ann@example.org"}},
+		{name: "lower-cased and distinct", body: "@Ann@Example.org and @ann@example.org", want: []string{"ann@example.org"}},
+		{name: "plus and dots in the name", body: "@ann.lee+work@example.org", want: []string{"ann.lee+work@example.org"}},
+		{name: "plain address is not a mention", body: "write to ann@example.org"},
+		{name: "inside a word", body: "mail@ann@example.org"},
+		{name: "no domain", body: "@ann@localhost"},
+		{name: "handle only", body: "@ann"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &model.Comment{Body: tc.body}
+			if got := c.MentionedEmails(); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBuildThreads(t *testing.T) {
+	ref := func(id int) *int { return &id }
+
+	// дерево записано как id -> ответы, в порядке обхода
+	shape := func(comments []*model.Comment) map[int][]int {
+		tree := map[int][]int{}
+		var walk func(parent int, level []*model.Comment)
+		walk = func(parent int, level []*model.Comment) {
+			for _, c := range level {
+				tree[parent] = append(tree[parent], c.ID)
+				walk(c.ID, c.Replies)
+			}
+		}
+		walk(0, comments)
+		return tree
+	}
+
+	testCases := []struct {
+		name     string
+		comments []*model.Comment
+		want     map[int][]int
+	}{
+		{
+			name: "empty",
+			want: map[int][]int{},
+		},
+		{
+			name: "flat",
+			comments: []*model.Comment{
+				{ID: 1}, {ID: 2}, {ID: 3},
+			},
+			want: map[int][]int{0: {1, 2, 3}},
+		},
+		{
+			name: "replies keep their order",
+			comments: []*model.Comment{
+				{ID: 1}, {ID: 2}, {ID: 3, ParentID: ref(1)}, {ID: 4, ParentID: ref(2)}, {ID: 5, ParentID: ref(1)},
+			},
+			want: map[int][]int{0: {1, 2}, 1: {3, 5}, 2: {4}},
+		},
+		{
+			name: "nested",
+			comments: []*model.Comment{
+				{ID: 1}, {ID: 2, ParentID: ref(1)}, {ID: 3, ParentID: ref(2)},
+			},
+			want: map[int][]int{0: {1}, 1: {2}, 2: {3}},
+		},
+		{
+			name: "missing parent makes a root",
+			comments: []*model.Comment{
+				{ID: 1}, {ID: 2, ParentID: ref(9)},
+			},
+			want: map[int][]int{0: {1, 2}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			roots := model.BuildThreads(tc.comments)
+			if roots == nil {
+				t.Fatal("got nil, want an empty list")
+			}
+			if got := shape(roots); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
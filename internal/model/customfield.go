@@ -0,0 +1,173 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CustomFieldType string
+
+const (
+	FieldText   CustomFieldType = "text"
+	FieldNumber CustomFieldType = "number"
+	FieldDate   CustomFieldType = "date"
+	FieldSelect CustomFieldType = "select"
+)
+
+const (
+	MaxFieldOptions   = 50
+	MaxFieldTextValue = 1000
+)
+
+var (
+	ErrFieldKey         = errors.New("field key must be lowercase letters, digits or underscores")
+	ErrFieldName        = errors.New("field name must be 1 to 100 characters")
+	ErrFieldType        = errors.New("field type must be text, number, date or select")
+	ErrFieldTypeChange  = errors.New("field type cannot be changed")
+	ErrFieldOptions     = errors.New("select fields need 1 to 50 distinct options")
+	ErrFieldOptionInUse = errors.New("option is still used by tasks")
+	ErrFieldExists      = errors.New("field with this key already exists")
+	ErrFieldUnknown     = errors.New("unknown field")
+	ErrFieldValueText   = errors.New("text value must be a string of at most 1000 characters")
+	ErrFieldValueNumber = errors.New("number value must be a number")
+	ErrFieldValueDate   = errors.New("date value must look like 2006-01-02")
+	ErrFieldValueOption = errors.New("value is not one of the field options")
+)
+
+var fieldKeyRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// CustomField is an extra typed attribute a team defines for its tasks.
+// Options lists the allowed values of a select field.
+type CustomField struct {
+	ID        int             `json:"id"`
+	TeamID    int             `json:"team_id"`
+	Key       string          `json:"key"`
+	Name      string          `json:"name"`
+	Type      CustomFieldType `json:"type"`
+	Options   []string        `json:"options"`
+	Position  int             `json:"position"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// FieldValue is a validated value of a task's custom field. Value is the
+// canonical text form; Number and Date are set for their field types.
+type FieldValue struct {
+	FieldID int
+	Value   string
+	Number  *float64
+	Date    *time.Time
+}
+
+// FieldValues maps field keys to values as they appear in task JSON:
+// numbers as numbers, everything else as strings.
+type FieldValues map[string]interface{}
+
+func ValidFieldKey(key string) bool {
+	return fieldKeyRe.MatchString(key)
+}
+
+func (f *CustomField) Validate() error {
+	f.Name = strings.TrimSpace(f.Name)
+	if !ValidFieldKey(f.Key) {
+		return ErrFieldKey
+	}
+	if f.Name == "" || len(f.Name) > 100 {
+		return ErrFieldName
+	}
+
+	switch f.Type {
+	case FieldText, FieldNumber, FieldDate:
+		f.Options = []string{}
+	case FieldSelect:
+		if len(f.Options) == 0 || len(f.Options) > MaxFieldOptions {
+			return ErrFieldOptions
+		}
+		seen := make(map[string]bool, len(f.Options))
+		for i, o := range f.Options {
+			o = strings.TrimSpace(o)
+			if o == "" || len(o) > 100 || seen[o] {
+				return ErrFieldOptions
+			}
+			seen[o] = true
+			f.Options[i] = o
+		}
+	default:
+		return ErrFieldType
+	}
+	return nil
+}
+
+// Parse checks a value decoded from JSON against the field type. A nil
+// value clears the field and yields a nil result.
+func (f *CustomField) Parse(v interface{}) (*FieldValue, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	fv := &FieldValue{FieldID: f.ID}
+
+	switch f.Type {
+	case FieldText:
+		s, ok := v.(string)
+		if !ok || len(s) > MaxFieldTextValue {
+			return nil, ErrFieldValueText
+		}
+		if s = strings.TrimSpace(s); s == "" {
+			return nil, nil
+		}
+		fv.Value = s
+	case FieldNumber:
+		n, ok := v.(float64)
+		if !ok {
+			s, isStr := v.(string)
+			if !isStr {
+				return nil, ErrFieldValueNumber
+			}
+			var err error
+			if n, err = strconv.ParseFloat(strings.TrimSpace(s), 64); err != nil {
+				return nil, ErrFieldValueNumber
+			}
+		}
+		fv.Value = FormatFieldNumber(n)
+		fv.Number = &n
+	case FieldDate:
+		s, ok := v.(string)
+		if !ok {
+			return nil, ErrFieldValueDate
+		}
+		d, err := time.Parse("2006-01-02", strings.TrimSpace(s))
+		if err != nil {
+			return nil, ErrFieldValueDate
+		}
+		fv.Value = d.Format("2006-01-02")
+		fv.Date = &d
+	case FieldSelect:
+		s, ok := v.(string)
+		if !ok || !f.HasOption(s) {
+			return nil, ErrFieldValueOption
+		}
+		fv.Value = s
+	default:
+		return nil, ErrFieldType
+	}
+	return fv, nil
+}
+
+func (f *CustomField) HasOption(o string) bool {
+	for _, opt := range f.Options {
+		if opt == o {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatFieldNumber is the canonical text of a number value, so that 2.50
+// and 2.5 are stored and matched the same way.
+func FormatFieldNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
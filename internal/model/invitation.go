@@ -0,0 +1,65 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+const InvitationTTL = 7 * 24 * time.Hour
+
+var (
+	ErrInvitationNotPending = errors.New("invitation is no longer pending")
+)
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+	InvitationExpired  InvitationStatus = "expired"
+)
+
+type Invitation struct {
+	ID          int              `json:"id"`
+	TeamID      int              `json:"team_id"`
+	TeamName    string           `json:"team_name,omitempty"`
+	Email       string           `json:"email"`
+	Role        TeamRole         `json:"role"`
+	InvitedBy   *int             `json:"invited_by"`
+	Status      InvitationStatus `json:"status"`
+	CreatedAt   time.Time        `json:"created_at"`
+	ExpiresAt   time.Time        `json:"expires_at"`
+	RespondedAt *time.Time       `json:"responded_at"`
+}
+
+func (i *Invitation) Validate() error {
+	if !i.Role.Valid() || i.Role == RoleOwner {
+		return ErrInvalidRole
+	}
+
+	return validation.ValidateStruct(
+		i,
+		validation.Field(&i.Email, validation.Required, is.Email),
+	)
+}
+
+func (i *Invitation) BeforeCreate() {
+	i.Email = strings.ToLower(strings.TrimSpace(i.Email))
+	i.Status = InvitationPending
+	i.CreatedAt = time.Now()
+	i.ExpiresAt = i.CreatedAt.Add(InvitationTTL)
+}
+
+// Open reports whether the invitation can still be accepted or declined.
+func (i *Invitation) Open() bool {
+	return i.Status == InvitationPending && time.Now().Before(i.ExpiresAt)
+}
+
+func (i *Invitation) For(u *User) bool {
+	return strings.EqualFold(i.Email, u.Email)
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const DefaultLabelColor = "#cccccc"
+
+var (
+	ErrLabelNameEmpty   = errors.New("label name is empty")
+	ErrLabelNameTooLong = errors.New("label name too long")
+	ErrLabelColor       = errors.New("label color must look like #a1b2c3")
+)
+
+var labelColorRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type Label struct {
+	ID        int       `json:"id"`
+	TeamID    int       `json:"team_id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LabelRef is the short form of a label embedded into task JSON.
+type LabelRef struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+func (l *Label) Validate() error {
+	l.Name = strings.TrimSpace(l.Name)
+	if l.Name == "" {
+		return ErrLabelNameEmpty
+	}
+	if len(l.Name) > 50 {
+		return ErrLabelNameTooLong
+	}
+	if l.Color == "" {
+		l.Color = DefaultLabelColor
+	}
+	if !labelColorRe.MatchString(l.Color) {
+		return ErrLabelColor
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidRole     = errors.New("invalid team role")
+	ErrTransferToOwner = errors.New("user already owns the team")
+)
+
+type TeamRole string
+
+const (
+	RoleOwner  TeamRole = "owner"
+	RoleAdmin  TeamRole = "admin"
+	RoleMember TeamRole = "member"
+	RoleViewer TeamRole = "viewer"
+)
+
+type Permission string
+
+const (
+	PermViewTeam          Permission = "team:view"
+	PermUpdateTeam        Permission = "team:update"
+	PermManageMembers     Permission = "team:members"
+	PermTransferOwnership Permission = "team:transfer"
+	PermEditTasks         Permission = "tasks:edit"
+	PermDeleteTasks       Permission = "tasks:delete"
+	PermComment           Permission = "tasks:comment"
+)
+
+var rolePermissions = map[TeamRole][]Permission{
+	RoleOwner: {
+		PermViewTeam, PermUpdateTeam, PermManageMembers, PermTransferOwnership,
+		PermEditTasks, PermDeleteTasks, PermComment,
+	},
+	RoleAdmin: {
+		PermViewTeam, PermUpdateTeam, PermManageMembers,
+		PermEditTasks, PermDeleteTasks, PermComment,
+	},
+	RoleMember: {
+		PermViewTeam, PermEditTasks, PermComment,
+	},
+	RoleViewer: {
+		PermViewTeam,
+	},
+}
+
+var roleRank = map[TeamRole]int{
+	RoleViewer: 1,
+	RoleMember: 2,
+	RoleAdmin:  3,
+	RoleOwner:  4,
+}
+
+type TeamMember struct {
+	TeamID    int       `json:"team_id"`
+	UserID    int       `json:"user_id"`
+	Email     string    `json:"email"`
+	Role      TeamRole  `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (r TeamRole) Valid() bool {
+	_, ok := roleRank[r]
+	return ok
+}
+
+func (r TeamRole) Can(p Permission) bool {
+	for _, perm := range rolePermissions[r] {
+		if perm == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Outranks reports whether a member with role r may assign or revoke the
+// other role. Nobody outranks an owner, so ownership only moves by transfer.
+func (r TeamRole) Outranks(other TeamRole) bool {
+	return roleRank[r] > roleRank[other]
+}
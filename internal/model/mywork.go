@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+// MyWork is a user's open tasks split by due date. Days are taken in the
+// time zone of now, so "today" is the caller's today.
+type MyWork struct {
+	Overdue  []*Task      `json:"overdue"`
+	Today    []*Task      `json:"today"`
+	Upcoming []*Task      `json:"upcoming"`
+	NoDate   []*Task      `json:"no_date"`
+	Counts   MyWorkCounts `json:"counts"`
+}
+
+type MyWorkCounts struct {
+	Overdue  int `json:"overdue"`
+	Today    int `json:"today"`
+	Upcoming int `json:"upcoming"`
+	NoDate   int `json:"no_date"`
+	Total    int `json:"total"`
+}
+
+// NewMyWork sorts tasks into the groups, keeping their order within each.
+// A task due earlier today is still in Today, not Overdue.
+func NewMyWork(tasks []*Task, now time.Time) *MyWork {
+	w := &MyWork{
+		Overdue:  []*Task{},
+		Today:    []*Task{},
+		Upcoming: []*Task{},
+		NoDate:   []*Task{},
+	}
+
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	tomorrow := today.AddDate(0, 0, 1)
+
+	for _, t := range tasks {
+		switch {
+		case t.DueDate == nil:
+			w.NoDate = append(w.NoDate, t)
+		case t.DueDate.Before(today):
+			w.Overdue = append(w.Overdue, t)
+		case t.DueDate.Before(tomorrow):
+			w.Today = append(w.Today, t)
+		default:
+			w.Upcoming = append(w.Upcoming, t)
+		}
+	}
+
+	w.Counts = MyWorkCounts{
+		Overdue:  len(w.Overdue),
+		Today:    len(w.Today),
+		Upcoming: len(w.Upcoming),
+		NoDate:   len(w.NoDate),
+		Total:    len(tasks),
+	}
+	return w
+}
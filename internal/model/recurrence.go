@@ -0,0 +1,143 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRecurrence = errors.New("recurrence must look like FREQ=DAILY|WEEKLY|MONTHLY[;INTERVAL=n][;BYMONTHDAY=d][;UNTIL=YYYYMMDD]")
+
+type Frequency string
+
+const (
+	FreqDaily   Frequency = "DAILY"
+	FreqWeekly  Frequency = "WEEKLY"
+	FreqMonthly Frequency = "MONTHLY"
+)
+
+// Recurrence is the subset of RFC 5545 RRULE tasks support: a frequency,
+// an interval, for monthly rules the day of month, and an optional last
+// date.
+type Recurrence struct {
+	Freq     Frequency
+	Interval int
+	MonthDay int
+	Until    *time.Time
+}
+
+// ParseRecurrence reads a rule such as "FREQ=WEEKLY;INTERVAL=2;UNTIL=20261231".
+// An "RRULE:" prefix is allowed, UNTIL may also carry a UTC time.
+func ParseRecurrence(s string) (*Recurrence, error) {
+	s = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "RRULE:")
+	r := &Recurrence{Interval: 1}
+
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, ErrInvalidRecurrence
+		}
+
+		switch key {
+		case "FREQ":
+			r.Freq = Frequency(value)
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > 365 {
+				return nil, ErrInvalidRecurrence
+			}
+			r.Interval = n
+		case "BYMONTHDAY":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > 31 {
+				return nil, ErrInvalidRecurrence
+			}
+			r.MonthDay = n
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, ErrInvalidRecurrence
+			}
+			r.Until = &until
+		default:
+			return nil, ErrInvalidRecurrence
+		}
+	}
+
+	switch r.Freq {
+	case FreqDaily, FreqWeekly, FreqMonthly:
+	default:
+		return nil, ErrInvalidRecurrence
+	}
+	if r.MonthDay != 0 && r.Freq != FreqMonthly {
+		return nil, ErrInvalidRecurrence
+	}
+	return r, nil
+}
+
+// parseUntil accepts a bare date, which covers the whole day, or a UTC
+// date-time.
+func parseUntil(v string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("20060102", v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Add(24*time.Hour - time.Second), nil
+}
+
+func (r *Recurrence) String() string {
+	s := fmt.Sprintf("FREQ=%s", r.Freq)
+	if r.Interval > 1 {
+		s += fmt.Sprintf(";INTERVAL=%d", r.Interval)
+	}
+	if r.MonthDay != 0 {
+		s += fmt.Sprintf(";BYMONTHDAY=%d", r.MonthDay)
+	}
+	if r.Until != nil {
+		s += ";UNTIL=" + r.Until.UTC().Format("20060102T150405Z")
+	}
+	return s
+}
+
+// Anchor pins a monthly rule without a day of month to the day of from, so
+// that an occurrence moved to the end of a shorter month does not drag the
+// ones after it along.
+func (r *Recurrence) Anchor(from time.Time) {
+	if r.Freq == FreqMonthly && r.MonthDay == 0 {
+		r.MonthDay = from.Day()
+	}
+}
+
+// Next returns the occurrence following from, or false once the rule has
+// run past UNTIL. Monthly rules land on MonthDay, or on the day of from
+// when it is not set, and fall back to the last day of shorter months.
+func (r *Recurrence) Next(from time.Time) (time.Time, bool) {
+	var next time.Time
+	switch r.Freq {
+	case FreqDaily:
+		next = from.AddDate(0, 0, r.Interval)
+	case FreqWeekly:
+		next = from.AddDate(0, 0, 7*r.Interval)
+	case FreqMonthly:
+		first := time.Date(from.Year(), from.Month(), 1, from.Hour(), from.Minute(), from.Second(), from.Nanosecond(), from.Location())
+		first = first.AddDate(0, r.Interval, 0)
+		day := from.Day()
+		if r.MonthDay != 0 {
+			day = r.MonthDay
+		}
+		if last := first.AddDate(0, 1, -1).Day(); day > last {
+			day = last
+		}
+		next = first.AddDate(0, 0, day-1)
+	}
+
+	if r.Until != nil && next.After(*r.Until) {
+		return time.Time{}, false
+	}
+	return next, true
+}
@@ -0,0 +1,146 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 9, 0, 0, 0, time.UTC)
+}
+
+func TestParseRecurrence(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   string
+		want string
+		err  error
+	}{
+		{name: "daily", in: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{name: "rrule prefix and case", in: " rrule:freq=weekly;interval=2 ", want: "FREQ=WEEKLY;INTERVAL=2"},
+		{name: "interval 1 is dropped", in: "FREQ=MONTHLY;INTERVAL=1", want: "FREQ=MONTHLY"},
+		{name: "month day", in: "FREQ=MONTHLY;BYMONTHDAY=31", want: "FREQ=MONTHLY;BYMONTHDAY=31"},
+		{name: "until date", in: "FREQ=DAILY;UNTIL=20261231", want: "FREQ=DAILY;UNTIL=20261231T235959Z"},
+		{name: "until time", in: "FREQ=DAILY;UNTIL=20261231T120000Z", want: "FREQ=DAILY;UNTIL=20261231T120000Z"},
+		{name: "no freq", in: "INTERVAL=2", err: model.ErrInvalidRecurrence},
+		{name: "unknown freq", in: "FREQ=HOURLY", err: model.ErrInvalidRecurrence},
+		{name: "zero interval", in: "FREQ=DAILY;INTERVAL=0", err: model.ErrInvalidRecurrence},
+		{name: "huge interval", in: "FREQ=DAILY;INTERVAL=366", err: model.ErrInvalidRecurrence},
+		{name: "bad month day", in: "FREQ=MONTHLY;BYMONTHDAY=32", err: model.ErrInvalidRecurrence},
+		{name: "month day on weekly", in: "FREQ=WEEKLY;BYMONTHDAY=3", err: model.ErrInvalidRecurrence},
+		{name: "bad until", in: "FREQ=DAILY;UNTIL=tomorrow", err: model.ErrInvalidRecurrence},
+		{name: "unknown part", in: "FREQ=DAILY;COUNT=3", err: model.ErrInvalidRecurrence},
+		{name: "no value", in: "FREQ", err: model.ErrInvalidRecurrence},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := model.ParseRecurrence(tc.in)
+			if err != tc.err {
+				t.Fatalf("got error %v, want %v", err, tc.err)
+			}
+			if err != nil {
+				return
+			}
+			if got := r.String(); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+
+			// каноническая запись читается обратно в то же правило
+			again, err := model.ParseRecurrence(r.String())
+			if err != nil || again.String() != r.String() {
+				t.Errorf("round trip: got %v, %v", again, err)
+			}
+		})
+	}
+}
+
+func TestRecurrence_Next(t *testing.T) {
+	testCases := []struct {
+		name string
+		rule string
+		from time.Time
+		want []time.Time
+	}{
+		{
+			name: "daily",
+			rule: "FREQ=DAILY;INTERVAL=3",
+			from: date(2026, time.December, 30),
+			want: []time.Time{date(2027, time.January, 2), date(2027, time.January, 5)},
+		},
+		{
+			name: "weekly",
+			rule: "FREQ=WEEKLY",
+			from: date(2026, time.February, 23),
+			want: []time.Time{date(2026, time.March, 2), date(2026, time.March, 9)},
+		},
+		{
+			name: "monthly keeps the 31st",
+			rule: "FREQ=MONTHLY",
+			from: date(2028, time.January, 31),
+			want: []time.Time{
+				date(2028, time.February, 29),
+				date(2028, time.March, 31),
+				date(2028, time.April, 30),
+				date(2028, time.May, 31),
+			},
+		},
+		{
+			name: "yearly by months keeps Feb 29",
+			rule: "FREQ=MONTHLY;INTERVAL=12",
+			from: date(2028, time.February, 29),
+			want: []time.Time{
+				date(2029, time.February, 28),
+				date(2030, time.February, 28),
+				date(2031, time.February, 28),
+				date(2032, time.February, 29),
+			},
+		},
+		{
+			name: "explicit month day",
+			rule: "FREQ=MONTHLY;BYMONTHDAY=30",
+			from: date(2026, time.January, 5),
+			want: []time.Time{date(2026, time.February, 28), date(2026, time.March, 30)},
+		},
+		{
+			name: "until",
+			rule: "FREQ=WEEKLY;UNTIL=20260310",
+			from: date(2026, time.February, 24),
+			want: []time.Time{date(2026, time.March, 3), date(2026, time.March, 10)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := model.ParseRecurrence(tc.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// так же, как при создании следующей задачи: правило
+			// привязывается к сроку и хранится вместе с ней
+			from := tc.from
+			for i, want := range tc.want {
+				r.Anchor(from)
+				r, err = model.ParseRecurrence(r.String())
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				next, ok := r.Next(from)
+				if !ok || !next.Equal(want) {
+					t.Fatalf("occurrence %d: got %v, %v; want %v", i+1, next, ok, want)
+				}
+				from = next
+			}
+
+			if r.Until != nil {
+				if next, ok := r.Next(from); ok {
+					t.Errorf("got %v past UNTIL", next)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+type ReminderKind string
+
+const (
+	ReminderDueSoon ReminderKind = "due_soon"
+	ReminderOverdue ReminderKind = "overdue"
+)
+
+// Reminder is a delivery record. The combination of task, recipient, kind,
+// lead and due date is unique, which keeps a reminder from going out twice.
+type Reminder struct {
+	ID        int           `json:"id"`
+	TaskID    int           `json:"task_id"`
+	UserID    int           `json:"user_id"`
+	Kind      ReminderKind  `json:"kind"`
+	Lead      time.Duration `json:"lead"`
+	DueDate   time.Time     `json:"due_date"`
+	CreatedAt time.Time     `json:"created_at"`
+	SentAt    *time.Time    `json:"sent_at"`
+}
+
+// PendingReminder is a reminder that is due but has not been recorded yet,
+// with what it takes to deliver it.
+type PendingReminder struct {
+	Reminder
+	TaskName string `json:"task_name"`
+	Email    string `json:"email"`
+}
@@ -0,0 +1,139 @@
+package model
+
+import (
+	"strconv"
+	"time"
+)
+
+const reportDate = "2006-01-02"
+
+type BurndownPoint struct {
+	Date      time.Time `json:"date"`
+	Total     int       `json:"total"`
+	Remaining int       `json:"remaining"`
+	Ideal     float64   `json:"ideal"`
+}
+
+// Burndown counts, for every day, how many tasks of the scope exist and how
+// many of them are not done yet at the end of that day.
+type Burndown struct {
+	TeamID   int              `json:"team_id"`
+	SprintID *int             `json:"sprint_id,omitempty"`
+	Points   []*BurndownPoint `json:"points"`
+}
+
+// FillIdeal draws the ideal line from the first day's remaining work down
+// to zero on the last day.
+func (b *Burndown) FillIdeal() {
+	n := len(b.Points)
+	if n == 0 {
+		return
+	}
+	start := float64(b.Points[0].Remaining)
+	for i, p := range b.Points {
+		if n == 1 {
+			p.Ideal = 0
+			continue
+		}
+		p.Ideal = start - start*float64(i)/float64(n-1)
+	}
+}
+
+func (b *Burndown) CSV() [][]string {
+	rows := [][]string{{"date", "total", "remaining", "ideal"}}
+	for _, p := range b.Points {
+		rows = append(rows, []string{
+			p.Date.Format(reportDate),
+			strconv.Itoa(p.Total),
+			strconv.Itoa(p.Remaining),
+			hours(p.Ideal),
+		})
+	}
+	return rows
+}
+
+// CycleTime is measured on tasks finished in the report range. Lead time
+// runs from creation to the last move into a done status, cycle time from
+// the first move into an in progress status to the same point.
+type CycleTime struct {
+	Priority      TaskPriority `json:"priority"`
+	Completed     int          `json:"completed"`
+	AvgLeadHours  float64      `json:"avg_lead_hours"`
+	AvgCycleHours *float64     `json:"avg_cycle_hours"`
+}
+
+type CycleTimeReport []*CycleTime
+
+func (r CycleTimeReport) CSV() [][]string {
+	rows := [][]string{{"priority", "completed", "avg_lead_hours", "avg_cycle_hours"}}
+	for _, c := range r {
+		cycle := ""
+		if c.AvgCycleHours != nil {
+			cycle = hours(*c.AvgCycleHours)
+		}
+		rows = append(rows, []string{
+			string(c.Priority),
+			strconv.Itoa(c.Completed),
+			hours(c.AvgLeadHours),
+			cycle,
+		})
+	}
+	return rows
+}
+
+type Throughput struct {
+	WeekStart time.Time `json:"week_start"`
+	Completed int       `json:"completed"`
+}
+
+type ThroughputReport []*Throughput
+
+func (r ThroughputReport) CSV() [][]string {
+	rows := [][]string{{"week_start", "completed"}}
+	for _, t := range r {
+		rows = append(rows, []string{
+			t.WeekStart.Format(reportDate),
+			strconv.Itoa(t.Completed),
+		})
+	}
+	return rows
+}
+
+// Workload is the open work of one assignee; AssigneeID is nil for
+// unassigned tasks. A task shared by several assignees counts for each.
+type Workload struct {
+	AssigneeID *int    `json:"assignee_id"`
+	Email      *string `json:"email"`
+	ToDo       int     `json:"todo"`
+	InProgress int     `json:"in_progress"`
+	Overdue    int     `json:"overdue"`
+	Total      int     `json:"total"`
+}
+
+type WorkloadReport []*Workload
+
+func (r WorkloadReport) CSV() [][]string {
+	rows := [][]string{{"assignee_id", "email", "todo", "in_progress", "overdue", "total"}}
+	for _, w := range r {
+		id, email := "", ""
+		if w.AssigneeID != nil {
+			id = strconv.Itoa(*w.AssigneeID)
+		}
+		if w.Email != nil {
+			email = *w.Email
+		}
+		rows = append(rows, []string{
+			id,
+			email,
+			strconv.Itoa(w.ToDo),
+			strconv.Itoa(w.InProgress),
+			strconv.Itoa(w.Overdue),
+			strconv.Itoa(w.Total),
+		})
+	}
+	return rows
+}
+
+func hours(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"errors"
+	"html"
+	"strings"
+)
+
+type SearchKind string
+
+const (
+	SearchTask    SearchKind = "task"
+	SearchComment SearchKind = "comment"
+	SearchTeam    SearchKind = "team"
+)
+
+var ErrSearchQuery = errors.New("search query must be 1 to 200 characters")
+
+func (k SearchKind) Valid() bool {
+	switch k {
+	case SearchTask, SearchComment, SearchTeam:
+		return true
+	}
+	return false
+}
+
+// SearchHit is one match of a full-text search. Title is the task or team
+// name, for comments the name of their task, as plain text. Snippet is
+// HTML that is safe to render as is: the matched text is escaped and only
+// the matched words are wrapped in <mark> tags.
+type SearchHit struct {
+	Kind    SearchKind `json:"kind"`
+	ID      int        `json:"id"`
+	TeamID  int        `json:"team_id"`
+	TaskID  *int       `json:"task_id,omitempty"`
+	Title   string     `json:"title"`
+	Snippet string     `json:"snippet"`
+	Rank    float64    `json:"rank"`
+}
+
+type SearchResult struct {
+	Hits       []*SearchHit `json:"hits"`
+	NextOffset *int         `json:"next_offset,omitempty"`
+}
+
+// Bounds of the matched words in a raw snippet. They are control characters
+// so that no user text can forge them; SnippetHTML turns them into tags.
+const (
+	SnippetStart = "\x02"
+	SnippetStop  = "\x03"
+)
+
+var snippetMarks = strings.NewReplacer(SnippetStart, "<mark>", SnippetStop, "</mark>")
+
+// SnippetHTML escapes a raw snippet and marks the matched words.
+func SnippetHTML(raw string) string {
+	return snippetMarks.Replace(html.EscapeString(raw))
+}
@@ -0,0 +1,39 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/qeery8/rest/internal/model"
+)
+
+func TestSnippetHTML(t *testing.T) {
+	testCases := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{
+			name: "plain",
+			raw:  "fix the " + model.SnippetStart + "login" + model.SnippetStop + " page",
+			want: "fix the <mark>login</mark> page",
+		},
+		{
+			name: "markup is escaped",
+			raw:  `<img src=x onerror="alert(1)"> ` + model.SnippetStart + "login" + model.SnippetStop,
+			want: "&lt;img src=x onerror=&#34;alert(1)&#34;&gt; <mark>login</mark>",
+		},
+		{
+			name: "forged tags stay text",
+			raw:  "<mark>" + model.SnippetStart + "a&b" + model.SnippetStop + "</mark>",
+			want: "&lt;mark&gt;<mark>a&amp;b</mark>&lt;/mark&gt;",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := model.SnippetHTML(tc.raw); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrSprintName         = errors.New("sprint name must be 1 to 100 characters")
+	ErrSprintDates        = errors.New("sprint must end on or after its start date")
+	ErrSprintNotPlanned   = errors.New("only a planned sprint can be started")
+	ErrSprintNotActive    = errors.New("only an active sprint can be closed")
+	ErrSprintClosed       = errors.New("sprint is closed")
+	ErrSprintActiveExists = errors.New("team already has an active sprint")
+	ErrSprintCarryOver    = errors.New("unfinished tasks can only move to another open sprint of the team")
+)
+
+type SprintState string
+
+const (
+	SprintPlanned SprintState = "planned"
+	SprintActive  SprintState = "active"
+	SprintClosed  SprintState = "closed"
+)
+
+type Sprint struct {
+	ID          int         `json:"id"`
+	TeamID      int         `json:"team_id"`
+	Name        string      `json:"name"`
+	Goal        string      `json:"goal"`
+	StartDate   time.Time   `json:"start_date"`
+	EndDate     time.Time   `json:"end_date"`
+	State       SprintState `json:"state"`
+	CarriedOver int         `json:"carried_over"`
+	StartedAt   *time.Time  `json:"started_at"`
+	ClosedAt    *time.Time  `json:"closed_at"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// SprintSummary compares what the team committed to at the start of the
+// sprint with what got done. Added counts tasks pulled in after the start.
+type SprintSummary struct {
+	SprintID           int         `json:"sprint_id"`
+	State              SprintState `json:"state"`
+	Committed          int         `json:"committed"`
+	CommittedCompleted int         `json:"committed_completed"`
+	Added              int         `json:"added"`
+	Total              int         `json:"total"`
+	Completed          int         `json:"completed"`
+	Remaining          int         `json:"remaining"`
+	CarriedOver        int         `json:"carried_over"`
+}
+
+func (s *Sprint) Validate() error {
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Name == "" || len(s.Name) > 100 {
+		return ErrSprintName
+	}
+	if s.EndDate.Before(s.StartDate) {
+		return ErrSprintDates
+	}
+	return nil
+}
+
+// Open reports whether tasks can still be planned into the sprint.
+func (s *Sprint) Open() bool {
+	return s.State != SprintClosed
+}
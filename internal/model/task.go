@@ -1,11 +1,38 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// MaxTaskDepth is how many levels a task tree may have, the root included.
+const MaxTaskDepth = 4
+
+var (
+	ErrTaskTeamRequired = errors.New("task must belong to a team")
+	ErrNameLineBreak    = errors.New("task name cannot contain line breaks")
+	ErrOpenSubtasks     = errors.New("task has open subtasks")
+	ErrSubtaskCycle     = errors.New("task cannot be a subtask of itself or its subtasks")
+	ErrSubtaskTooDeep   = errors.New("subtasks nested too deep")
+	ErrParentOtherTeam  = errors.New("parent task belongs to another team")
+	ErrParentDone       = errors.New("parent task is already done")
+	ErrNegativeEstimate = errors.New("estimate cannot be negative")
+	ErrTaskPriority     = errors.New("unknown task priority")
+
+	ErrTaskBlocked         = errors.New("task is blocked by unfinished tasks")
+	ErrDependencySelf      = errors.New("task cannot block itself")
+	ErrDependencyCycle     = errors.New("dependency would create a cycle")
+	ErrDependencyOtherTeam = errors.New("dependent tasks must belong to the same team")
+)
 
+// TaskStatus is a key from the team workflow, see Workflow.
 type TaskStatus string
 
 type TaskPriority string
 
+// Statuses of the default workflow.
 const (
 	StatusToDo       TaskStatus = "todo"
 	StatusInProgress TaskStatus = "in_progress"
@@ -15,19 +42,75 @@ const (
 const (
 	LowPriority    TaskPriority = "low"
 	MediumPriority TaskPriority = "medium"
-	HightPriority  TaskPriority = "hight"
+	HightPriority  TaskPriority = "high"
 )
 
+// Task keeps AssigneeID for clients that know a single assignee; it is the
+// earliest of AssigneeIDs.
 type Task struct {
-	ID         int          `json:"id"`
-	Name       string       `json:"name"`
-	Content    string       `json:"content"`
-	Status     TaskStatus   `json:"status"`
-	Priority   TaskPriority `json:"priority"`
-	DueDate    *time.Time   `json:"due_date"`
-	AssigneeID *int         `json:"assignee_id"`
-	CreatedAt  time.Time    `json:"created_at"`
-	UpdatedAt  time.Time    `json:"updated_at"`
+	ID               int            `json:"id"`
+	Name             string         `json:"name"`
+	Content          string         `json:"content"`
+	Status           TaskStatus     `json:"status"`
+	Category         StatusCategory `json:"status_category"`
+	Priority         TaskPriority   `json:"priority"`
+	DueDate          *time.Time     `json:"due_date"`
+	AssigneeID       *int           `json:"assignee_id"`
+	AssigneeIDs      []int          `json:"assignee_ids"`
+	WatcherIDs       []int          `json:"watcher_ids"`
+	TeamID           int            `json:"team_id"`
+	ParentID         *int           `json:"parent_id"`
+	SprintID         *int           `json:"sprint_id"`
+	Rank             int64          `json:"rank"`
+	StoryPoints      *int           `json:"story_points"`
+	EstimateMinutes  *int           `json:"estimate_minutes"`
+	SpentMinutes     int            `json:"spent_minutes"`
+	Recurrence       *string        `json:"recurrence"`
+	NextOccurrenceID *int           `json:"next_occurrence_id"`
+	Blocked          bool           `json:"blocked"`
+	Labels           []LabelRef     `json:"labels"`
+	Fields           FieldValues    `json:"fields"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+
+	Progress TaskProgress `json:"progress"`
+}
+
+// TaskProgress rolls up subtasks and checklist items. Percent counts both
+// kinds equally; a task with neither is 0 or 100 depending on its status.
+type TaskProgress struct {
+	SubtasksTotal  int `json:"subtasks_total"`
+	SubtasksDone   int `json:"subtasks_done"`
+	ChecklistTotal int `json:"checklist_total"`
+	ChecklistDone  int `json:"checklist_done"`
+	Percent        int `json:"percent"`
+}
+
+// Valid only checks the key format; whether the status exists is up to the
+// team workflow.
+func (s TaskStatus) Valid() bool {
+	return statusKeyRe.MatchString(string(s))
+}
+
+func (p TaskPriority) Valid() bool {
+	switch p {
+	case LowPriority, MediumPriority, HightPriority:
+		return true
+	}
+	return false
+}
+
+func (t *Task) ComputeProgress() {
+	p := &t.Progress
+	total := p.SubtasksTotal + p.ChecklistTotal
+	switch {
+	case total > 0:
+		p.Percent = (p.SubtasksDone + p.ChecklistDone) * 100 / total
+	case t.Category == CategoryDone:
+		p.Percent = 100
+	default:
+		p.Percent = 0
+	}
 }
 
 func (t *Task) Validate() error {
@@ -37,5 +120,81 @@ func (t *Task) Validate() error {
 	if len(t.Name) > 50 {
 		return ErrNameTooLong
 	}
+	// имя попадает в тему письма
+	if strings.ContainsAny(t.Name, "\r\n") {
+		return ErrNameLineBreak
+	}
+	if t.TeamID <= 0 {
+		return ErrTaskTeamRequired
+	}
+	if t.Priority != "" && !t.Priority.Valid() {
+		return ErrTaskPriority
+	}
+	if (t.StoryPoints != nil && *t.StoryPoints < 0) || (t.EstimateMinutes != nil && *t.EstimateMinutes < 0) {
+		return ErrNegativeEstimate
+	}
+	if t.Recurrence != nil {
+		rule, err := ParseRecurrence(*t.Recurrence)
+		if err != nil {
+			return err
+		}
+		canonical := rule.String()
+		t.Recurrence = &canonical
+	}
 	return nil
 }
+
+// TaskPatch is a partial task update: fields left out of the body keep their
+// value, and due_date, assignee_id, story_points, estimate_minutes and
+// recurrence are cleared by an explicit null.
+type TaskPatch struct {
+	Name       *string             `json:"name"`
+	Content    *string             `json:"content"`
+	Status     *TaskStatus         `json:"status"`
+	Priority   *TaskPriority       `json:"priority"`
+	DueDate    Optional[time.Time] `json:"due_date"`
+	AssigneeID Optional[int]       `json:"assignee_id"`
+
+	StoryPoints     Optional[int]    `json:"story_points"`
+	EstimateMinutes Optional[int]    `json:"estimate_minutes"`
+	Recurrence      Optional[string] `json:"recurrence"`
+}
+
+// Apply writes the fields present in the patch onto t.
+func (p *TaskPatch) Apply(t *Task) {
+	if p.Name != nil {
+		t.Name = *p.Name
+	}
+	if p.Content != nil {
+		t.Content = *p.Content
+	}
+	if p.Status != nil {
+		t.Status = *p.Status
+	}
+	if p.Priority != nil {
+		t.Priority = *p.Priority
+	}
+	p.DueDate.Apply(&t.DueDate)
+	p.AssigneeID.Apply(&t.AssigneeID)
+	p.StoryPoints.Apply(&t.StoryPoints)
+	p.EstimateMinutes.Apply(&t.EstimateMinutes)
+	p.Recurrence.Apply(&t.Recurrence)
+}
+
+// Optional tells a JSON field sent as null from one left out of the body.
+type Optional[T any] struct {
+	Set   bool
+	Value *T
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	return json.Unmarshal(data, &o.Value)
+}
+
+// Apply overwrites dst when the field was sent.
+func (o Optional[T]) Apply(dst **T) {
+	if o.Set {
+		*dst = o.Value
+	}
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	MaxTemplateChecklist = 50
+	MaxTemplateSubtasks  = 20
+)
+
+var (
+	ErrTemplateName         = errors.New("template name must be 1 to 100 characters")
+	ErrTemplatePattern      = errors.New("template name pattern must be 1 to 100 characters")
+	ErrTemplatePriority     = errors.New("unknown template priority")
+	ErrTemplateChecklist    = errors.New("template checklist items must be 1 to 255 characters, at most 50 of them")
+	ErrTemplateSubtasks     = errors.New("template subtasks need a name, at most 20 of them")
+	ErrTemplateNameExists   = errors.New("template with this name already exists")
+	ErrTemplateLabelForeign = errors.New("template labels must belong to the team")
+)
+
+type TemplateSubtask struct {
+	Name     string       `json:"name"`
+	Content  string       `json:"content"`
+	Priority TaskPriority `json:"priority"`
+}
+
+// TaskTemplate describes a task a team creates over and over. NamePattern
+// may use {date}, {week}, {month} and any variable passed when the
+// template is used, e.g. "Release {version}".
+type TaskTemplate struct {
+	ID          int               `json:"id"`
+	TeamID      int               `json:"team_id"`
+	Name        string            `json:"name"`
+	NamePattern string            `json:"name_pattern"`
+	Content     string            `json:"content"`
+	Priority    TaskPriority      `json:"priority"`
+	LabelIDs    []int             `json:"label_ids"`
+	Checklist   []string          `json:"checklist"`
+	Subtasks    []TemplateSubtask `json:"subtasks"`
+	CreatedBy   *int              `json:"created_by"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// TemplateInstance holds what the caller adds when creating a task from a
+// template.
+type TemplateInstance struct {
+	Vars         map[string]string `json:"vars"`
+	DueDate      *time.Time        `json:"due_date"`
+	AssigneeID   *int              `json:"assignee_id"`
+	WithSubtasks bool              `json:"with_subtasks"`
+}
+
+func (t *TaskTemplate) Validate() error {
+	t.Name = strings.TrimSpace(t.Name)
+	t.NamePattern = strings.TrimSpace(t.NamePattern)
+	if t.Name == "" || len(t.Name) > 100 {
+		return ErrTemplateName
+	}
+	if t.NamePattern == "" || len(t.NamePattern) > 100 {
+		return ErrTemplatePattern
+	}
+	if t.Priority != "" && !t.Priority.Valid() {
+		return ErrTemplatePriority
+	}
+
+	if len(t.Checklist) > MaxTemplateChecklist {
+		return ErrTemplateChecklist
+	}
+	for i, item := range t.Checklist {
+		if item = strings.TrimSpace(item); item == "" || len(item) > 255 {
+			return ErrTemplateChecklist
+		}
+		t.Checklist[i] = item
+	}
+
+	if len(t.Subtasks) > MaxTemplateSubtasks {
+		return ErrTemplateSubtasks
+	}
+	for _, st := range t.Subtasks {
+		if strings.TrimSpace(st.Name) == "" || (st.Priority != "" && !st.Priority.Valid()) {
+			return ErrTemplateSubtasks
+		}
+	}
+
+	if t.LabelIDs == nil {
+		t.LabelIDs = []int{}
+	}
+	if t.Checklist == nil {
+		t.Checklist = []string{}
+	}
+	if t.Subtasks == nil {
+		t.Subtasks = []TemplateSubtask{}
+	}
+	return nil
+}
+
+// TaskName fills the placeholders of the name pattern. Placeholders
+// without a value are left as they are.
+func (t *TaskTemplate) TaskName(now time.Time, vars map[string]string) string {
+	year, week := now.ISOWeek()
+	pairs := []string{
+		"{date}", now.Format("2006-01-02"),
+		"{week}", fmt.Sprintf("%d-W%02d", year, week),
+		"{month}", now.Format("2006-01"),
+	}
+	for k, v := range vars {
+		pairs = append(pairs, "{"+k+"}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(t.NamePattern)
+}
@@ -0,0 +1,157 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// StatusCategory tells what a team's status means for the rest of the
+// system: done statuses close subtasks and unblock dependants, in progress
+// statuses are the ones a blocked task cannot enter.
+type StatusCategory string
+
+const (
+	CategoryToDo       StatusCategory = "todo"
+	CategoryInProgress StatusCategory = "in_progress"
+	CategoryDone       StatusCategory = "done"
+)
+
+var (
+	ErrWorkflowEmpty          = errors.New("workflow needs at least one status")
+	ErrWorkflowStatusKey      = errors.New("status key must be lowercase letters, digits or underscores")
+	ErrWorkflowStatusName     = errors.New("status name must be 1 to 50 characters")
+	ErrWorkflowCategory       = errors.New("unknown status category")
+	ErrWorkflowDuplicate      = errors.New("duplicate status key")
+	ErrWorkflowNoDone         = errors.New("workflow needs at least one done status")
+	ErrWorkflowTransition     = errors.New("transition refers to an unknown status")
+	ErrUnknownStatus          = errors.New("status is not part of the team workflow")
+	ErrTransitionNotAllowed   = errors.New("status transition is not allowed by the team workflow")
+	ErrWorkflowStatusInUse    = errors.New("status is still used by tasks")
+	ErrWorkflowSelfTransition = errors.New("transition must change the status")
+)
+
+var statusKeyRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+type WorkflowStatus struct {
+	Key      TaskStatus     `json:"key"`
+	Name     string         `json:"name"`
+	Category StatusCategory `json:"category"`
+	Position int            `json:"position"`
+}
+
+type WorkflowTransition struct {
+	From TaskStatus `json:"from"`
+	To   TaskStatus `json:"to"`
+}
+
+// Workflow is the set of statuses a team's tasks can be in and the moves
+// allowed between them. The first status is where new tasks start.
+type Workflow struct {
+	TeamID      int                  `json:"team_id"`
+	Statuses    []*WorkflowStatus    `json:"statuses"`
+	Transitions []WorkflowTransition `json:"transitions"`
+}
+
+// DefaultWorkflow is what every team starts with: the old todo, in_progress
+// and done statuses, any move allowed.
+func DefaultWorkflow(teamID int) *Workflow {
+	w := &Workflow{
+		TeamID: teamID,
+		Statuses: []*WorkflowStatus{
+			{Key: StatusToDo, Name: "To do", Category: CategoryToDo, Position: 1},
+			{Key: StatusInProgress, Name: "In progress", Category: CategoryInProgress, Position: 2},
+			{Key: StatusDone, Name: "Done", Category: CategoryDone, Position: 3},
+		},
+	}
+
+	for _, from := range w.Statuses {
+		for _, to := range w.Statuses {
+			if from.Key != to.Key {
+				w.Transitions = append(w.Transitions, WorkflowTransition{From: from.Key, To: to.Key})
+			}
+		}
+	}
+
+	return w
+}
+
+func (c StatusCategory) Valid() bool {
+	switch c {
+	case CategoryToDo, CategoryInProgress, CategoryDone:
+		return true
+	}
+	return false
+}
+
+// Validate checks the workflow and renumbers status positions in the order
+// the statuses are listed.
+func (w *Workflow) Validate() error {
+	if len(w.Statuses) == 0 {
+		return ErrWorkflowEmpty
+	}
+
+	keys := make(map[TaskStatus]bool, len(w.Statuses))
+	hasDone := false
+	for i, s := range w.Statuses {
+		if s == nil {
+			return ErrWorkflowStatusKey
+		}
+		s.Name = strings.TrimSpace(s.Name)
+		if !s.Key.Valid() {
+			return ErrWorkflowStatusKey
+		}
+		if s.Name == "" || len(s.Name) > 50 {
+			return ErrWorkflowStatusName
+		}
+		if !s.Category.Valid() {
+			return ErrWorkflowCategory
+		}
+		if keys[s.Key] {
+			return ErrWorkflowDuplicate
+		}
+		keys[s.Key] = true
+		hasDone = hasDone || s.Category == CategoryDone
+		s.Position = i + 1
+	}
+	if !hasDone {
+		return ErrWorkflowNoDone
+	}
+
+	for _, t := range w.Transitions {
+		if !keys[t.From] || !keys[t.To] {
+			return ErrWorkflowTransition
+		}
+		if t.From == t.To {
+			return ErrWorkflowSelfTransition
+		}
+	}
+	return nil
+}
+
+func (w *Workflow) Status(key TaskStatus) *WorkflowStatus {
+	for _, s := range w.Statuses {
+		if s.Key == key {
+			return s
+		}
+	}
+	return nil
+}
+
+func (w *Workflow) Initial() TaskStatus {
+	return w.Statuses[0].Key
+}
+
+// CanMove reports whether a task may go from one status to another. Staying
+// in the same status is always allowed.
+func (w *Workflow) CanMove(from, to TaskStatus) bool {
+	if from == to {
+		return w.Status(to) != nil
+	}
+	for _, t := range w.Transitions {
+		if t.From == from && t.To == to {
+			return true
+		}
+	}
+	return false
+}
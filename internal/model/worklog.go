@@ -0,0 +1,88 @@
+package model
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// MaxWorklogMinutes caps a single entry at one day.
+const MaxWorklogMinutes = 24 * 60
+
+var (
+	ErrWorklogMinutes  = errors.New("logged time must be between 1 minute and 24 hours")
+	ErrWorklogNoteLong = errors.New("worklog note too long")
+	ErrWorklogDate     = errors.New("work date is required")
+)
+
+type Worklog struct {
+	ID        int       `json:"id"`
+	TaskID    int       `json:"task_id"`
+	TaskName  string    `json:"task_name,omitempty"`
+	UserID    int       `json:"user_id"`
+	Minutes   int       `json:"minutes"`
+	Note      string    `json:"note"`
+	WorkDate  time.Time `json:"work_date"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (w *Worklog) Validate() error {
+	if w.Minutes < 1 || w.Minutes > MaxWorklogMinutes {
+		return ErrWorklogMinutes
+	}
+	if len(w.Note) > 1000 {
+		return ErrWorklogNoteLong
+	}
+	if w.WorkDate.IsZero() {
+		return ErrWorklogDate
+	}
+	return nil
+}
+
+type TimesheetDay struct {
+	Date    time.Time `json:"date"`
+	Minutes int       `json:"minutes"`
+}
+
+// Timesheet is the time one user logged over a date range, entry by entry
+// and summed per day.
+type Timesheet struct {
+	UserID       int            `json:"user_id"`
+	TotalMinutes int            `json:"total_minutes"`
+	Days         []TimesheetDay `json:"days"`
+	Entries      []*Worklog     `json:"entries"`
+}
+
+// NewTimesheet sums entries, which must be ordered by work date.
+func NewTimesheet(userID int, entries []*Worklog) *Timesheet {
+	ts := &Timesheet{
+		UserID:  userID,
+		Days:    []TimesheetDay{},
+		Entries: entries,
+	}
+
+	for _, e := range entries {
+		ts.TotalMinutes += e.Minutes
+		if n := len(ts.Days); n > 0 && ts.Days[n-1].Date.Equal(e.WorkDate) {
+			ts.Days[n-1].Minutes += e.Minutes
+			continue
+		}
+		ts.Days = append(ts.Days, TimesheetDay{Date: e.WorkDate, Minutes: e.Minutes})
+	}
+	return ts
+}
+
+func (ts *Timesheet) CSV() [][]string {
+	rows := [][]string{{"work_date", "task_id", "task_name", "minutes", "note"}}
+	for _, e := range ts.Entries {
+		rows = append(rows, []string{
+			e.WorkDate.Format(reportDate),
+			strconv.Itoa(e.TaskID),
+			e.TaskName,
+			strconv.Itoa(e.Minutes),
+			e.Note,
+		})
+	}
+	return rows
+}
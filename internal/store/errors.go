@@ -5,4 +5,12 @@ import "errors"
 var (
 	ErrRecordNotFound = errors.New("record not found")
 	ErrUserNotInTeam  = errors.New("user not in team")
+	ErrAlreadyInTeam  = errors.New("user already in team")
+	ErrAlreadyInvited = errors.New("user already invited")
+	ErrAlreadyLinked  = errors.New("tasks already linked")
+	ErrLabelExists    = errors.New("label with this name already exists")
+	ErrBoardNeighbour = errors.New("neighbour task is not in the target column")
+
+	ErrParentCommentNotFound = errors.New("parent comment not found")
+	ErrParentTaskNotFound    = errors.New("parent task not found")
 )
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"errors"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+const (
+	SortCreatedAt = "created_at"
+	SortDueDate   = "due_date"
+	SortPriority  = "priority"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidSort   = errors.New("invalid sort field")
+)
+
+// TaskFilter describes a task listing. MemberID is mandatory and restricts
+// the result to teams the user belongs to; every other field is optional.
+// InvolvedID matches tasks the user is assigned to or watches.
+type TaskFilter struct {
+	MemberID   int
+	TeamID     *int
+	Statuses   []model.TaskStatus
+	Priorities []model.TaskPriority
+	AssigneeID *int
+	WatcherID  *int
+	InvolvedID *int
+	DueFrom    *time.Time
+	DueTo      *time.Time
+	Query      string
+	LabelIDs   []int
+	AllLabels  bool
+	SprintID   *int
+	Backlog    bool
+	Fields     []FieldFilter
+
+	Sort   string
+	Desc   bool
+	Cursor string
+	Limit  int
+}
+
+// FieldFilter matches tasks on a custom field by its key. Values matches
+// any of the given values; From and To bound the field inclusively and are
+// compared as numbers or as YYYY-MM-DD dates, whichever they parse as.
+type FieldFilter struct {
+	Key    string
+	Values []string
+	From   string
+	To     string
+}
+
+type TaskPage struct {
+	Tasks      []*model.Task `json:"tasks"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+const (
+	DefaultSearchSize = 20
+	MaxSearchSize     = 100
+)
+
+// SearchQuery is a full-text search over the teams MemberID belongs to.
+// Kinds limits the result to some kinds of hits, all of them when empty.
+type SearchQuery struct {
+	MemberID int
+	Text     string
+	Kinds    []model.SearchKind
+	TeamID   *int
+	Limit    int
+	Offset   int
+}
+
+// ActivityFilter selects a task's history or a team feed, newest first.
+// Cursor is the next_cursor of the previous page.
+type ActivityFilter struct {
+	TaskID *int
+	TeamID *int
+	Cursor string
+	Limit  int
+}
+
+type ActivityPage struct {
+	Items      []*model.Activity `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
@@ -1,6 +1,10 @@
 package store
 
-import "github.com/qeery8/rest/internal/model"
+import (
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+)
 
 type UserRepository interface {
 	Create(*model.User) error
@@ -16,16 +20,157 @@ type TeamRepository interface {
 	FindByUser(userID int) ([]*model.Team, error)
 	Update(*model.Team) error
 	Delete(id int) error
-	AddMembers(teamID int, userID int) error
+	AddMembers(teamID int, userID int, role model.TeamRole) error
 	RemoveMembers(teamID int, userID int) error
+	Members(teamID int) ([]*model.TeamMember, error)
+	MemberRole(teamID int, userID int) (model.TeamRole, error)
+	UpdateMemberRole(teamID int, userID int, role model.TeamRole) error
+	TransferOwnership(teamID int, newOwnerID int) error
 }
 
 type TaskRepository interface {
-	Create(*model.Task) error
-	AssigneeUser(userID int, taskID int) error
-	Update(*model.Task) error
-	Delete(id int) error
+	Create(t *model.Task, by *int) error
+	AssigneeUser(userID int, taskID int, by *int) error
+	AddAssignee(taskID int, userID int, by *int) error
+	RemoveAssignee(taskID int, userID int, by *int) error
+	Watch(taskID int, userID int) error
+	Unwatch(taskID int, userID int) error
+	Bulk(op *model.BulkOperation, taskIDs []int, atomic bool, by *int) ([]*model.BulkResult, error)
+	Update(id int, p *model.TaskPatch, by *int) error
+	Delete(id int, by *int) error
 	GetByID(id int) (*model.Task, error)
 	List() ([]*model.Task, error)
-	DueDate() error
+	ListByTeam(teamID int) ([]*model.Task, error)
+	Involved(userID int) ([]*model.Task, error)
+	Find(filter *TaskFilter) (*TaskPage, error)
+	DueDate(from time.Time, to time.Time) ([]*model.Task, error)
+	Children(parentID int) ([]*model.Task, error)
+	SetParent(taskID int, parentID *int, by *int) error
+	Board(teamID int) (*model.Board, error)
+	Move(taskID int, status model.TaskStatus, beforeID *int, afterID *int, by *int) error
+}
+
+type InvitationRepository interface {
+	Create(*model.Invitation) error
+	Find(id int) (*model.Invitation, error)
+	ListPendingByTeam(teamID int) ([]*model.Invitation, error)
+	ListPendingByEmail(email string) ([]*model.Invitation, error)
+	Respond(id int, status model.InvitationStatus) error
+	Accept(id int, userID int) error
+	ExpireStale() (int64, error)
+}
+
+type ReminderRepository interface {
+	Pending(kind model.ReminderKind, from time.Time, to time.Time, leads []time.Duration) ([]*model.PendingReminder, error)
+	Record(*model.Reminder) (bool, error)
+	MarkSent(id int) error
+	Delete(id int) error
+}
+
+type ArchiveRepository interface {
+	Abandon(taskID int, reason string, by *int) error
+	AbandonStale(before time.Time, reason string) (int64, error)
+	ListByTeam(teamID int) ([]*model.AbandonedTask, error)
+	Restore(teamID int, taskID int, by *int) (*model.Task, error)
+}
+
+type CommentRepository interface {
+	Create(*model.Comment) error
+	Find(id int) (*model.Comment, error)
+	ListByTask(taskID int) ([]*model.Comment, error)
+	Update(c *model.Comment, editorID int) error
+	Delete(id int) error
+	Revisions(commentID int) ([]*model.CommentRevision, error)
+}
+
+type ActivityRepository interface {
+	Record(entries ...*model.Activity) error
+	List(filter *ActivityFilter) (*ActivityPage, error)
+}
+
+type ChecklistRepository interface {
+	Create(*model.ChecklistItem) error
+	Find(id int) (*model.ChecklistItem, error)
+	ListByTask(taskID int) ([]*model.ChecklistItem, error)
+	Update(*model.ChecklistItem) error
+	Delete(id int) error
+}
+
+type DependencyRepository interface {
+	Add(blockerID int, blockedID int, createdBy *int) error
+	Remove(blockerID int, blockedID int) error
+	Blockers(taskID int) ([]*model.Task, error)
+	Blocking(taskID int) ([]*model.Task, error)
+}
+
+type WorkflowRepository interface {
+	Get(teamID int) (*model.Workflow, error)
+	Save(*model.Workflow) error
+}
+
+type SprintRepository interface {
+	Create(*model.Sprint) error
+	Find(id int) (*model.Sprint, error)
+	ListByTeam(teamID int) ([]*model.Sprint, error)
+	Update(*model.Sprint) error
+	Start(id int) error
+	Close(id int, carryTo *int, by *int) error
+	AddTasks(sprintID int, taskIDs []int, by *int) error
+	RemoveTask(sprintID int, taskID int, by *int) error
+	Summary(id int) (*model.SprintSummary, error)
+}
+
+type ReportRepository interface {
+	Burndown(teamID int, sprintID *int, from time.Time, to time.Time) (*model.Burndown, error)
+	CycleTime(teamID int, from time.Time, to time.Time) (model.CycleTimeReport, error)
+	Throughput(teamID int, from time.Time, to time.Time) (model.ThroughputReport, error)
+	Workload(teamID int) (model.WorkloadReport, error)
+}
+
+type WorklogRepository interface {
+	Create(*model.Worklog) error
+	Find(id int) (*model.Worklog, error)
+	ListByTask(taskID int) ([]*model.Worklog, error)
+	Update(*model.Worklog) error
+	Delete(id int) error
+	Timesheet(userID int, teamID *int, from time.Time, to time.Time) (*model.Timesheet, error)
+}
+
+type LabelRepository interface {
+	Create(*model.Label) error
+	Find(id int) (*model.Label, error)
+	ListByTeam(teamID int) ([]*model.Label, error)
+	Update(*model.Label) error
+	Delete(id int) error
+	Attach(taskID int, labelID int, by *int) error
+	Detach(taskID int, labelID int, by *int) error
+}
+
+type TemplateRepository interface {
+	Create(*model.TaskTemplate) error
+	Find(id int) (*model.TaskTemplate, error)
+	ListByTeam(teamID int) ([]*model.TaskTemplate, error)
+	Update(*model.TaskTemplate) error
+	Delete(id int) error
+	Instantiate(t *model.TaskTemplate, in *model.TemplateInstance, by *int) ([]*model.Task, error)
+}
+
+type CustomFieldRepository interface {
+	Create(*model.CustomField) error
+	Find(id int) (*model.CustomField, error)
+	ListByTeam(teamID int) ([]*model.CustomField, error)
+	Update(*model.CustomField) error
+	Delete(id int) error
+	SetValues(taskID int, values []*model.FieldValue, clear []int, by *int) error
+}
+
+type SearchRepository interface {
+	Search(*SearchQuery) (*model.SearchResult, error)
+}
+
+type AttachmentRepository interface {
+	Create(*model.Attachment) error
+	Find(id int) (*model.Attachment, error)
+	ListByTask(taskID int) ([]*model.Attachment, error)
+	Delete(id int) error
 }
@@ -0,0 +1,146 @@
+package sqlstore
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type ActivityRepository struct {
+	store *Store
+}
+
+func (r *ActivityRepository) Record(entries ...*model.Activity) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := recordActivity(tx, entries...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// recordActivity writes history entries in the caller's transaction, next
+// to the change they describe.
+func recordActivity(q querier, entries ...*model.Activity) error {
+	now := time.Now()
+	for _, a := range entries {
+		a.CreatedAt = now
+		if err := q.QueryRow(
+			`INSERT INTO task_activity (task_id, team_id, actor_id, action, field, old_value, new_value, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id`,
+			a.TaskID, a.TeamID, a.ActorID, a.Action, a.Field, a.OldValue, a.NewValue, a.CreatedAt,
+		).Scan(&a.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trackTask runs change on the task and records every field it touched,
+// along with the next occurrence a finished recurring task spawned. change
+// gets a copy of the task as it was before.
+func trackTask(q querier, taskID int, by *int, change func(t *model.Task) error) error {
+	before, err := findTask(q, taskID)
+	if err != nil {
+		return err
+	}
+
+	t := *before
+	if err := change(&t); err != nil {
+		return err
+	}
+
+	after, err := findTask(q, taskID)
+	if err != nil {
+		return err
+	}
+
+	entries := model.DiffTasks(before, after, by)
+	if after.NextOccurrenceID != nil && before.NextOccurrenceID == nil {
+		next := &model.Task{ID: *after.NextOccurrenceID, TeamID: after.TeamID}
+		entries = append(entries, model.NewActivity(next, by, model.ActivityCreated))
+	}
+	return recordActivity(q, entries...)
+}
+
+func (r *ActivityRepository) List(f *store.ActivityFilter) (*store.ActivityPage, error) {
+	if f.Limit <= 0 {
+		f.Limit = store.DefaultPageSize
+	}
+	if f.Limit > store.MaxPageSize {
+		f.Limit = store.MaxPageSize
+	}
+
+	b := &queryBuilder{}
+	if f.TaskID != nil {
+		b.where("task_id = ?", *f.TaskID)
+	}
+	if f.TeamID != nil {
+		b.where("team_id = ?", *f.TeamID)
+	}
+	if f.Cursor != "" {
+		before, err := strconv.Atoi(f.Cursor)
+		if err != nil {
+			return nil, store.ErrInvalidCursor
+		}
+		b.where("id < ?", before)
+	}
+
+	rows, err := r.store.db.Query(
+		`SELECT id, task_id, team_id, actor_id, action, field, old_value, new_value, created_at
+		FROM task_activity`+b.whereSQL()+`
+		ORDER BY id DESC
+		LIMIT `+strconv.Itoa(f.Limit+1),
+		b.args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	page := &store.ActivityPage{
+		Items: []*model.Activity{},
+	}
+
+	for rows.Next() {
+		a := &model.Activity{}
+		if err := rows.Scan(
+			&a.ID,
+			&a.TaskID,
+			&a.TeamID,
+			&a.ActorID,
+			&a.Action,
+			&a.Field,
+			&a.OldValue,
+			&a.NewValue,
+			&a.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		page.Items = append(page.Items, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Items) > f.Limit {
+		page.Items = page.Items[:f.Limit]
+		page.NextCursor = strconv.Itoa(page.Items[f.Limit-1].ID)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,176 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+// ArchiveRepository archives tasks in place: an abandoned task keeps its
+// row, comments, history and everything else attached to it, and is only
+// hidden from listings, search and reports until it is restored.
+type ArchiveRepository struct {
+	store *Store
+}
+
+func (r *ArchiveRepository) Abandon(taskID int, reason string, by *int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := abandonTask(tx, taskID, reason, by); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func abandonTask(q querier, taskID int, reason string, by *int) error {
+	t := &model.Task{ID: taskID}
+	if err := q.QueryRow(
+		`UPDATE tasks SET archived_at = NOW(), archive_reason = $2, archived_by = $3, updated_at = NOW()
+		WHERE id = $1 AND archived_at IS NULL
+		RETURNING team_id`,
+		taskID, reason, by,
+	).Scan(&t.TeamID); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrRecordNotFound
+		}
+		return err
+	}
+
+	entry := model.NewActivity(t, by, model.ActivityAbandoned)
+	entry.NewValue = &reason
+	return recordActivity(q, entry)
+}
+
+// AbandonStale archives tasks that moved into an in-progress status before
+// the given time and have not moved since. Editing other fields does not
+// reset the clock; restoring the task does.
+func (r *ArchiveRepository) AbandonStale(before time.Time, reason string) (int64, error) {
+	result, err := r.store.db.Exec(
+		`WITH archived AS (
+			UPDATE tasks t SET archived_at = NOW(), archive_reason = $2, updated_at = NOW()
+			WHERE `+inCategory("t", model.CategoryInProgress)+` AND t.archived_at IS NULL
+			AND (
+				SELECT MAX(tr.changed_at) FROM task_status_transitions tr
+				WHERE tr.task_id = t.id AND tr.to_category = 'in_progress'
+			) < $1
+			AND NOT EXISTS (
+				SELECT 1 FROM task_activity a
+				WHERE a.task_id = t.id AND a.action = 'restored' AND a.created_at >= $1
+			)
+			RETURNING t.id, t.team_id
+		)
+		INSERT INTO task_activity (task_id, team_id, action, new_value, created_at)
+		SELECT archived.id, archived.team_id, 'abandoned', $2, NOW()
+		FROM archived`,
+		before, reason,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (r *ArchiveRepository) ListByTeam(teamID int) ([]*model.AbandonedTask, error) {
+	rows, err := r.store.db.Query(
+		`SELECT `+taskColumns+`, t.archive_reason, t.archived_by, t.archived_at
+		FROM tasks t
+		WHERE t.team_id = $1 AND t.archived_at IS NOT NULL
+		ORDER BY t.archived_at DESC, t.id`,
+		teamID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var tasks []*model.AbandonedTask
+
+	for rows.Next() {
+		t := &model.AbandonedTask{}
+		task, err := scanTask(rows, &t.Reason, &t.AbandonedBy, &t.AbandonedAt)
+		if err != nil {
+			return nil, err
+		}
+		t.Task = *task
+		tasks = append(tasks, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Restore brings the task back exactly as it was archived, at the bottom
+// of its column. A status removed from the team workflow in the meantime
+// is replaced with the initial one, and that move is recorded like any
+// other status change.
+func (r *ArchiveRepository) Restore(teamID int, taskID int, by *int) (*model.Task, error) {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockBoard(tx, teamID); err != nil {
+		return nil, err
+	}
+
+	var from model.TaskStatus
+	if err := tx.QueryRow(
+		`SELECT status FROM tasks
+		WHERE id = $1 AND team_id = $2 AND archived_at IS NOT NULL
+		FOR UPDATE`,
+		taskID, teamID,
+	).Scan(&from); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	wf, err := loadWorkflow(tx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	status := from
+	if wf.Status(status) == nil {
+		status = wf.Initial()
+	}
+
+	if _, err := tx.Exec(
+		`UPDATE tasks SET archived_at = NULL, archive_reason = '', archived_by = NULL, updated_at = NOW(),
+		status = $2, rank = `+nextRank("tasks.team_id", "$2")+`
+		WHERE id = $1`,
+		taskID, status,
+	); err != nil {
+		return nil, err
+	}
+
+	t, err := findTask(tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Status != from {
+		if err := recordTransition(tx, t, &from); err != nil {
+			return nil, err
+		}
+	}
+	if err := recordActivity(tx, model.NewActivity(t, by, model.ActivityRestored)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,67 @@
+package sqlstore_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store/sqlstore"
+)
+
+func TestArchiveRepository_AbandonStale(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("users", "teams")
+
+	s := sqlstore.New(db)
+	_, team := newTeam(t, s)
+	stale := newTask(t, s, team, "stale task")
+	other := newTask(t, s, team, "other task")
+
+	status := model.StatusInProgress
+	for _, task := range []*model.Task{stale, other} {
+		if err := s.Task().Update(task.ID, &model.TaskPatch{Status: &status}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// stale взяли в работу два дня назад, а сегодня только переименовали
+	if _, err := db.Exec(
+		`UPDATE task_status_transitions SET changed_at = NOW() - INTERVAL '2 days' WHERE task_id = $1`,
+		stale.ID,
+	); err != nil {
+		t.Fatal(err)
+	}
+	name := "renamed stale task"
+	if err := s.Task().Update(stale.ID, &model.TaskPatch{Name: &name}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Now().Add(-24 * time.Hour)
+	n, err := s.Archive().AbandonStale(before, "stale")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("abandoned %d tasks, want 1", n)
+	}
+	if _, err := s.Task().GetByID(other.ID); err != nil {
+		t.Errorf("recently started task was abandoned: %v", err)
+	}
+
+	restored, err := s.Archive().Restore(team.ID, stale.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := s.Task().GetByID(other.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Status != model.StatusInProgress || restored.Rank <= current.Rank {
+		t.Errorf("restored task: status %q rank %d, want in_progress below rank %d", restored.Status, restored.Rank, current.Rank)
+	}
+
+	// только что восстановленная задача снова в архив не уходит
+	if n, err := s.Archive().AbandonStale(before, "stale"); err != nil || n != 0 {
+		t.Errorf("after restore: abandoned %d, %v; want none", n, err)
+	}
+}
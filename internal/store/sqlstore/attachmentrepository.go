@@ -0,0 +1,100 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type AttachmentRepository struct {
+	store *Store
+}
+
+func scanAttachment(row rowScanner) (*model.Attachment, error) {
+	a := &model.Attachment{}
+	if err := row.Scan(
+		&a.ID,
+		&a.TaskID,
+		&a.StorageKey,
+		&a.FileName,
+		&a.ContentType,
+		&a.Size,
+		&a.UploadedBy,
+		&a.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (r *AttachmentRepository) Create(a *model.Attachment) error {
+	a.CreatedAt = time.Now()
+
+	return r.store.db.QueryRow(
+		`INSERT INTO task_attachments (task_id, storage_key, file_name, content_type, size_bytes, uploaded_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`,
+		a.TaskID, a.StorageKey, a.FileName, a.ContentType, a.Size, a.UploadedBy, a.CreatedAt,
+	).Scan(&a.ID)
+}
+
+func (r *AttachmentRepository) Find(id int) (*model.Attachment, error) {
+	a, err := scanAttachment(r.store.db.QueryRow(
+		`SELECT id, task_id, storage_key, file_name, content_type, size_bytes, uploaded_by, created_at
+		FROM task_attachments
+		WHERE id = $1`,
+		id,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+func (r *AttachmentRepository) ListByTask(taskID int) ([]*model.Attachment, error) {
+	rows, err := r.store.db.Query(
+		`SELECT id, task_id, storage_key, file_name, content_type, size_bytes, uploaded_by, created_at
+		FROM task_attachments
+		WHERE task_id = $1
+		ORDER BY created_at, id`,
+		taskID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	attachments := []*model.Attachment{}
+
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *AttachmentRepository) Delete(id int) error {
+	result, err := r.store.db.Exec(
+		`DELETE FROM task_attachments
+		WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result, store.ErrRecordNotFound)
+}
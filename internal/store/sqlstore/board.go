@@ -0,0 +1,211 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+// rankStep is the gap left between neighbouring cards of a board column. A
+// card dropped between two others takes the middle rank; when two
+// neighbours run out of room the column is renumbered.
+const rankStep = 1 << 16
+
+// boardLock is the first key of the advisory lock taken on a team while one
+// of its cards is being moved or placed at the bottom of a column.
+const boardLock = 1
+
+// lockBoard makes the rest of the transaction wait for other changes to the
+// team's ranks. It has to come before any task row is locked, since Move
+// renumbers whole columns while holding it.
+func lockBoard(q querier, teamID int) error {
+	_, err := q.Exec(`SELECT pg_advisory_xact_lock($1, $2)`, boardLock, teamID)
+	return err
+}
+
+// nextRank is an SQL expression for the rank at the bottom of a column. It
+// is only stable under lockBoard.
+func nextRank(teamID string, status string) string {
+	return fmt.Sprintf(
+		`(SELECT COALESCE(MAX(rt.rank), 0) + %d FROM tasks rt WHERE rt.team_id = %s AND rt.status = %s)`,
+		rankStep, teamID, status,
+	)
+}
+
+func (r *TaskRepository) Board(teamID int) (*model.Board, error) {
+	wf, err := loadWorkflow(r.store.db, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := r.queryTasks(
+		`SELECT `+taskColumns+`
+		FROM tasks t
+		WHERE t.team_id = $1 AND t.archived_at IS NULL
+		ORDER BY t.rank, t.id`,
+		teamID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.NewBoard(wf, tasks), nil
+}
+
+// Move puts the task into status, right before beforeID or right after
+// afterID, or at the bottom of the column when neither is given. Moves
+// within one team are serialized, so concurrent drags cannot hand out the
+// same rank.
+func (r *TaskRepository) Move(taskID int, status model.TaskStatus, beforeID *int, afterID *int, by *int) error {
+	var teamID int
+	if err := r.store.db.QueryRow(
+		`SELECT team_id FROM tasks WHERE id = $1 AND archived_at IS NULL`,
+		taskID,
+	).Scan(&teamID); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrRecordNotFound
+		}
+		return err
+	}
+
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockBoard(tx, teamID); err != nil {
+		return err
+	}
+
+	if err := trackTask(tx, taskID, by, func(t *model.Task) error {
+		return moveTask(tx, t, status, beforeID, afterID)
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func moveTask(q querier, t *model.Task, status model.TaskStatus, beforeID *int, afterID *int) error {
+	current := t.Status
+	t.Status = status
+	if err := checkStatusChange(q, t, current); err != nil {
+		return err
+	}
+
+	rank, err := placeRank(q, t, beforeID, afterID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := q.Exec(
+		`UPDATE tasks SET status = $1, rank = $2, updated_at = NOW()
+		WHERE id = $3`,
+		t.Status, rank, t.ID,
+	); err != nil {
+		return err
+	}
+
+	if current == t.Status {
+		return nil
+	}
+	if err := recordTransition(q, t, &current); err != nil {
+		return err
+	}
+	return spawnOccurrence(q, t)
+}
+
+// placeRank picks the rank for t between its new neighbours.
+func placeRank(q querier, t *model.Task, beforeID *int, afterID *int) (int64, error) {
+	lo, hi, err := neighbourRanks(q, t, beforeID, afterID)
+	if err != nil {
+		return 0, err
+	}
+
+	if hi-lo < 2 {
+		if err := rebalance(q, t); err != nil {
+			return 0, err
+		}
+		if lo, hi, err = neighbourRanks(q, t, beforeID, afterID); err != nil {
+			return 0, err
+		}
+	}
+
+	return lo + (hi-lo)/2, nil
+}
+
+// neighbourRanks returns the ranks of the cards t will sit between in its
+// new column. A missing neighbour is replaced with one rankStep*2 away.
+func neighbourRanks(q querier, t *model.Task, beforeID *int, afterID *int) (int64, int64, error) {
+	var (
+		anchor int64
+		other  sql.NullInt64
+		err    error
+	)
+
+	switch {
+	case beforeID != nil:
+		err = q.QueryRow(
+			`SELECT n.rank, (
+				SELECT MAX(o.rank) FROM tasks o
+				WHERE o.team_id = n.team_id AND o.status = n.status AND o.id <> $2 AND o.rank < n.rank AND o.archived_at IS NULL
+			)
+			FROM tasks n
+			WHERE n.id = $1 AND n.id <> $2 AND n.team_id = $3 AND n.status = $4 AND n.archived_at IS NULL`,
+			*beforeID, t.ID, t.TeamID, t.Status,
+		).Scan(&anchor, &other)
+		if err == nil && !other.Valid {
+			other.Int64 = anchor - 2*rankStep
+		}
+		return other.Int64, anchor, neighbourError(err)
+
+	case afterID != nil:
+		err = q.QueryRow(
+			`SELECT n.rank, (
+				SELECT MIN(o.rank) FROM tasks o
+				WHERE o.team_id = n.team_id AND o.status = n.status AND o.id <> $2 AND o.rank > n.rank AND o.archived_at IS NULL
+			)
+			FROM tasks n
+			WHERE n.id = $1 AND n.id <> $2 AND n.team_id = $3 AND n.status = $4 AND n.archived_at IS NULL`,
+			*afterID, t.ID, t.TeamID, t.Status,
+		).Scan(&anchor, &other)
+		if err == nil && !other.Valid {
+			other.Int64 = anchor + 2*rankStep
+		}
+		return anchor, other.Int64, neighbourError(err)
+
+	default:
+		err = q.QueryRow(
+			`SELECT COALESCE(MAX(rank), 0) FROM tasks
+			WHERE team_id = $1 AND status = $2 AND id <> $3`,
+			t.TeamID, t.Status, t.ID,
+		).Scan(&anchor)
+		return anchor, anchor + 2*rankStep, err
+	}
+}
+
+func neighbourError(err error) error {
+	if err == sql.ErrNoRows {
+		return store.ErrBoardNeighbour
+	}
+	return err
+}
+
+// rebalance renumbers the column t is moving into, spreading ranks rankStep
+// apart again.
+func rebalance(q querier, t *model.Task) error {
+	_, err := q.Exec(
+		`UPDATE tasks SET rank = ranked.position * $4
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY rank, id) AS position
+			FROM tasks
+			WHERE team_id = $1 AND status = $2 AND id <> $3
+		) ranked
+		WHERE ranked.id = tasks.id`,
+		t.TeamID, t.Status, t.ID, rankStep,
+	)
+	return err
+}
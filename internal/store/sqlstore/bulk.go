@@ -0,0 +1,86 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/qeery8/rest/internal/model"
+)
+
+// Bulk applies the operation to every task in one transaction. Each task
+// runs under its own savepoint, so a failed one is undone alone. When
+// atomic, the first failure rolls back the whole batch and the other tasks
+// are reported as not applied; otherwise the rest is committed. The error
+// is only set when the transaction itself fails.
+func (r *TaskRepository) Bulk(op *model.BulkOperation, taskIDs []int, atomic bool, by *int) ([]*model.BulkResult, error) {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	results := make([]*model.BulkResult, len(taskIDs))
+	for i, id := range taskIDs {
+		results[i] = &model.BulkResult{TaskID: id}
+	}
+
+	for _, res := range results {
+		if _, err := tx.Exec(`SAVEPOINT bulk_task`); err != nil {
+			return nil, err
+		}
+
+		if err := applyBulk(tx, op, res.TaskID, by); err != nil {
+			if _, rbErr := tx.Exec(`ROLLBACK TO SAVEPOINT bulk_task`); rbErr != nil {
+				return nil, rbErr
+			}
+			res.Error = err.Error()
+
+			if atomic {
+				for _, other := range results {
+					if other != res {
+						other.OK = false
+						other.Error = model.ErrBulkRolledBack.Error()
+					}
+				}
+				return results, nil
+			}
+			continue
+		}
+
+		if _, err := tx.Exec(`RELEASE SAVEPOINT bulk_task`); err != nil {
+			return nil, err
+		}
+		res.OK = true
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func applyBulk(tx *sql.Tx, op *model.BulkOperation, taskID int, by *int) error {
+	switch op.Action {
+	case model.BulkSetStatus, model.BulkSetPriority:
+		return trackTask(tx, taskID, by, func(t *model.Task) error {
+			if op.Action == model.BulkSetStatus {
+				t.Status = op.Status
+			} else {
+				t.Priority = op.Priority
+			}
+			return updateTask(tx, t)
+		})
+	case model.BulkAssign:
+		return trackTask(tx, taskID, by, func(*model.Task) error {
+			return addAssignee(tx, taskID, *op.UserID)
+		})
+	case model.BulkAddLabel:
+		return trackTask(tx, taskID, by, func(*model.Task) error {
+			return attachLabel(tx, taskID, *op.LabelID)
+		})
+	case model.BulkDelete:
+		return deleteTask(tx, taskID, by)
+	case model.BulkAbandon:
+		return abandonTask(tx, taskID, op.Reason, by)
+	}
+	return model.ErrBulkAction
+}
@@ -0,0 +1,126 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type ChecklistRepository struct {
+	store *Store
+}
+
+func (r *ChecklistRepository) Create(i *model.ChecklistItem) error {
+	if err := i.Validate(); err != nil {
+		return err
+	}
+
+	i.CreatedAt = time.Now()
+	i.UpdatedAt = i.CreatedAt
+
+	// новый пункт встает в конец списка
+	return r.store.db.QueryRow(
+		`INSERT INTO task_checklist_items (task_id, title, done, position, created_at, updated_at)
+		VALUES ($1, $2, $3, (SELECT COALESCE(MAX(position), 0) + 1 FROM task_checklist_items WHERE task_id = $1), $4, $5)
+		RETURNING id, position`,
+		i.TaskID, i.Title, i.Done, i.CreatedAt, i.UpdatedAt,
+	).Scan(&i.ID, &i.Position)
+}
+
+func (r *ChecklistRepository) Find(id int) (*model.ChecklistItem, error) {
+	i := &model.ChecklistItem{}
+	if err := r.store.db.QueryRow(
+		`SELECT id, task_id, title, done, position, created_at, updated_at
+		FROM task_checklist_items
+		WHERE id = $1`,
+		id,
+	).Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.Title,
+		&i.Done,
+		&i.Position,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return i, nil
+}
+
+func (r *ChecklistRepository) ListByTask(taskID int) ([]*model.ChecklistItem, error) {
+	rows, err := r.store.db.Query(
+		`SELECT id, task_id, title, done, position, created_at, updated_at
+		FROM task_checklist_items
+		WHERE task_id = $1
+		ORDER BY position, id`,
+		taskID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	items := []*model.ChecklistItem{}
+
+	for rows.Next() {
+		i := &model.ChecklistItem{}
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskID,
+			&i.Title,
+			&i.Done,
+			&i.Position,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *ChecklistRepository) Update(i *model.ChecklistItem) error {
+	if err := i.Validate(); err != nil {
+		return err
+	}
+
+	i.UpdatedAt = time.Now()
+
+	result, err := r.store.db.Exec(
+		`UPDATE task_checklist_items SET
+		title = $1, done = $2, position = $3, updated_at = $4
+		WHERE id = $5`,
+		i.Title, i.Done, i.Position, i.UpdatedAt, i.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result, store.ErrRecordNotFound)
+}
+
+func (r *ChecklistRepository) Delete(id int) error {
+	result, err := r.store.db.Exec(
+		`DELETE FROM task_checklist_items
+		WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result, store.ErrRecordNotFound)
+}
@@ -0,0 +1,243 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+const commentColumns = `c.id, c.task_id, c.author_id, c.parent_id, c.body, c.created_at, c.updated_at,
+	EXISTS (SELECT 1 FROM task_comment_revisions cr WHERE cr.comment_id = c.id),
+	COALESCE((SELECT array_agg(cm.user_id ORDER BY cm.user_id) FROM task_comment_mentions cm WHERE cm.comment_id = c.id), '{}')`
+
+type CommentRepository struct {
+	store *Store
+}
+
+func scanComment(row rowScanner) (*model.Comment, error) {
+	c := &model.Comment{}
+	var mentions pq.Int64Array
+	if err := row.Scan(
+		&c.ID,
+		&c.TaskID,
+		&c.AuthorID,
+		&c.ParentID,
+		&c.Body,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+		&c.Edited,
+		&mentions,
+	); err != nil {
+		return nil, err
+	}
+
+	c.Mentions = make([]int, len(mentions))
+	for i, id := range mentions {
+		c.Mentions[i] = int(id)
+	}
+
+	return c, nil
+}
+
+func (r *CommentRepository) Create(c *model.Comment) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if c.ParentID != nil {
+		var parentTaskID int
+		if err := tx.QueryRow(
+			`SELECT task_id FROM task_comments WHERE id = $1`,
+			*c.ParentID,
+		).Scan(&parentTaskID); err != nil || parentTaskID != c.TaskID {
+			return store.ErrParentCommentNotFound
+		}
+	}
+
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = c.CreatedAt
+
+	if err := tx.QueryRow(
+		`INSERT INTO task_comments (task_id, author_id, parent_id, body, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		c.TaskID, c.AuthorID, c.ParentID, c.Body, c.CreatedAt, c.UpdatedAt,
+	).Scan(&c.ID); err != nil {
+		return err
+	}
+
+	if err := insertMentions(tx, c); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *CommentRepository) Find(id int) (*model.Comment, error) {
+	c, err := scanComment(r.store.db.QueryRow(
+		`SELECT `+commentColumns+`
+		FROM task_comments c
+		WHERE c.id = $1`,
+		id,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (r *CommentRepository) ListByTask(taskID int) ([]*model.Comment, error) {
+	rows, err := r.store.db.Query(
+		`SELECT `+commentColumns+`
+		FROM task_comments c
+		WHERE c.task_id = $1
+		ORDER BY c.created_at, c.id`,
+		taskID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var comments []*model.Comment
+
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// Update stores the previous body as a revision and replaces the mentions
+// with the ones of the new body.
+func (r *CommentRepository) Update(c *model.Comment, editorID int) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`INSERT INTO task_comment_revisions (comment_id, body, edited_by, edited_at)
+		SELECT id, body, $2, NOW() FROM task_comments WHERE id = $1`,
+		c.ID, editorID,
+	)
+	if err != nil {
+		return err
+	}
+	if err := expectAffected(result, store.ErrRecordNotFound); err != nil {
+		return err
+	}
+
+	c.UpdatedAt = time.Now()
+	if _, err := tx.Exec(
+		`UPDATE task_comments SET body = $1, updated_at = $2
+		WHERE id = $3`,
+		c.Body, c.UpdatedAt, c.ID,
+	); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(
+		`DELETE FROM task_comment_mentions WHERE comment_id = $1`,
+		c.ID,
+	); err != nil {
+		return err
+	}
+
+	if err := insertMentions(tx, c); err != nil {
+		return err
+	}
+
+	c.Edited = true
+
+	return tx.Commit()
+}
+
+func (r *CommentRepository) Delete(id int) error {
+	result, err := r.store.db.Exec(
+		`DELETE FROM task_comments
+		WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result, store.ErrRecordNotFound)
+}
+
+func (r *CommentRepository) Revisions(commentID int) ([]*model.CommentRevision, error) {
+	rows, err := r.store.db.Query(
+		`SELECT id, comment_id, body, edited_by, edited_at
+		FROM task_comment_revisions
+		WHERE comment_id = $1
+		ORDER BY edited_at, id`,
+		commentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	revisions := []*model.CommentRevision{}
+
+	for rows.Next() {
+		rev := &model.CommentRevision{}
+		if err := rows.Scan(
+			&rev.ID,
+			&rev.CommentID,
+			&rev.Body,
+			&rev.EditedBy,
+			&rev.EditedAt,
+		); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func insertMentions(tx *sql.Tx, c *model.Comment) error {
+	for _, userID := range c.Mentions {
+		if _, err := tx.Exec(
+			`INSERT INTO task_comment_mentions (comment_id, user_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING`,
+			c.ID, userID,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type CustomFieldRepository struct {
+	store *Store
+}
+
+func scanCustomField(row rowScanner) (*model.CustomField, error) {
+	f := &model.CustomField{}
+	var options []byte
+	if err := row.Scan(
+		&f.ID,
+		&f.TeamID,
+		&f.Key,
+		&f.Name,
+		&f.Type,
+		&options,
+		&f.Position,
+		&f.CreatedAt,
+		&f.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(options, &f.Options); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (r *CustomFieldRepository) Create(f *model.CustomField) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	options, err := json.Marshal(f.Options)
+	if err != nil {
+		return err
+	}
+
+	f.CreatedAt = time.Now()
+	f.UpdatedAt = f.CreatedAt
+
+	if err := r.store.db.QueryRow(
+		`INSERT INTO custom_fields (team_id, key, name, type, options, position, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`,
+		f.TeamID, f.Key, f.Name, f.Type, options, f.Position, f.CreatedAt, f.UpdatedAt,
+	).Scan(&f.ID); err != nil {
+		if isUniqueViolation(err) {
+			return model.ErrFieldExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *CustomFieldRepository) Find(id int) (*model.CustomField, error) {
+	f, err := scanCustomField(r.store.db.QueryRow(
+		`SELECT id, team_id, key, name, type, options, position, created_at, updated_at
+		FROM custom_fields
+		WHERE id = $1`,
+		id,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+func (r *CustomFieldRepository) ListByTeam(teamID int) ([]*model.CustomField, error) {
+	rows, err := r.store.db.Query(
+		`SELECT id, team_id, key, name, type, options, position, created_at, updated_at
+		FROM custom_fields
+		WHERE team_id = $1
+		ORDER BY position, id`,
+		teamID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	fields := []*model.CustomField{}
+
+	for rows.Next() {
+		f, err := scanCustomField(rows)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// Update renames the field, moves it or changes its options. The type
+// cannot change, and a select option cannot be dropped while tasks still
+// use it.
+func (r *CustomFieldRepository) Update(f *model.CustomField) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	options, err := json.Marshal(f.Options)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var current model.CustomFieldType
+	if err := tx.QueryRow(
+		`SELECT type FROM custom_fields
+		WHERE id = $1
+		FOR UPDATE`,
+		f.ID,
+	).Scan(&current); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrRecordNotFound
+		}
+		return err
+	}
+	if current != f.Type {
+		return model.ErrFieldTypeChange
+	}
+
+	if f.Type == model.FieldSelect {
+		var inUse bool
+		if err := tx.QueryRow(
+			`SELECT EXISTS (
+				SELECT 1 FROM task_field_values
+				WHERE field_id = $1 AND value <> ALL($2)
+			)`,
+			f.ID, pq.Array(f.Options),
+		).Scan(&inUse); err != nil {
+			return err
+		}
+		if inUse {
+			return model.ErrFieldOptionInUse
+		}
+	}
+
+	f.UpdatedAt = time.Now()
+
+	if _, err := tx.Exec(
+		`UPDATE custom_fields SET key = $1, name = $2, options = $3, position = $4, updated_at = $5
+		WHERE id = $6`,
+		f.Key, f.Name, options, f.Position, f.UpdatedAt, f.ID,
+	); err != nil {
+		if isUniqueViolation(err) {
+			return model.ErrFieldExists
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *CustomFieldRepository) Delete(id int) error {
+	result, err := r.store.db.Exec(
+		`DELETE FROM custom_fields
+		WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result, store.ErrRecordNotFound)
+}
+
+// SetValues stores the given values of a task and removes the values of
+// the cleared fields, in one transaction.
+func (r *CustomFieldRepository) SetValues(taskID int, values []*model.FieldValue, clear []int, by *int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := trackTask(tx, taskID, by, func(*model.Task) error {
+		return setFieldValues(tx, taskID, values, clear)
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func setFieldValues(q querier, taskID int, values []*model.FieldValue, clear []int) error {
+	for _, v := range values {
+		if _, err := q.Exec(
+			`INSERT INTO task_field_values (task_id, field_id, value, value_number, value_date)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (task_id, field_id) DO UPDATE
+			SET value = EXCLUDED.value, value_number = EXCLUDED.value_number, value_date = EXCLUDED.value_date`,
+			taskID, v.FieldID, v.Value, v.Number, v.Date,
+		); err != nil {
+			return err
+		}
+	}
+
+	if len(clear) > 0 {
+		ids := make([]int64, len(clear))
+		for i, id := range clear {
+			ids[i] = int64(id)
+		}
+		if _, err := q.Exec(
+			`DELETE FROM task_field_values
+			WHERE task_id = $1 AND field_id = ANY($2)`,
+			taskID, pq.Array(ids),
+		); err != nil {
+			return err
+		}
+	}
+
+	_, err := q.Exec(
+		`UPDATE tasks SET updated_at = NOW()
+		WHERE id = $1`,
+		taskID,
+	)
+	return err
+}
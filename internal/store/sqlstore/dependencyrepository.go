@@ -0,0 +1,134 @@
+package sqlstore
+
+import (
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+// DependencyRepository stores "blocker blocks blocked" edges between tasks
+// of the same team. The graph is kept acyclic.
+type DependencyRepository struct {
+	store *Store
+}
+
+// dependencyLock is the first key of the advisory lock taken on a team
+// while a dependency is being added to it.
+const dependencyLock = 2
+
+func (r *DependencyRepository) Add(blockerID int, blockedID int, createdBy *int) error {
+	if blockerID == blockedID {
+		return model.ErrDependencySelf
+	}
+
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(
+		`SELECT team_id FROM tasks
+		WHERE id IN ($1, $2) AND archived_at IS NULL`,
+		blockerID, blockedID,
+	)
+	if err != nil {
+		return err
+	}
+
+	var teams []int
+	for rows.Next() {
+		var teamID int
+		if err := rows.Scan(&teamID); err != nil {
+			rows.Close()
+			return err
+		}
+		teams = append(teams, teamID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(teams) != 2 {
+		return store.ErrRecordNotFound
+	}
+	if teams[0] != teams[1] {
+		return model.ErrDependencyOtherTeam
+	}
+
+	// связи команды добавляются по очереди: две встречные вставки
+	// по отдельности цикла не дают, а вместе замкнули бы его
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1, $2)`, dependencyLock, teams[0]); err != nil {
+		return err
+	}
+
+	// цикл появится, если blocked уже (транзитивно) блокирует blocker
+	var cycle bool
+	if err := tx.QueryRow(
+		`WITH RECURSIVE downstream AS (
+			SELECT blocked_id FROM task_dependencies WHERE blocker_id = $1
+			UNION
+			SELECT d.blocked_id
+			FROM task_dependencies d
+			JOIN downstream ds ON d.blocker_id = ds.blocked_id
+		)
+		SELECT EXISTS (SELECT 1 FROM downstream WHERE blocked_id = $2)`,
+		blockedID, blockerID,
+	).Scan(&cycle); err != nil {
+		return err
+	}
+	if cycle {
+		return model.ErrDependencyCycle
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO task_dependencies (blocker_id, blocked_id, created_by, created_at)
+		VALUES ($1, $2, $3, NOW())`,
+		blockerID, blockedID, createdBy,
+	)
+	if isUniqueViolation(err) {
+		return store.ErrAlreadyLinked
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *DependencyRepository) Remove(blockerID int, blockedID int) error {
+	result, err := r.store.db.Exec(
+		`DELETE FROM task_dependencies
+		WHERE blocker_id = $1 AND blocked_id = $2`,
+		blockerID, blockedID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result, store.ErrRecordNotFound)
+}
+
+func (r *DependencyRepository) Blockers(taskID int) ([]*model.Task, error) {
+	return selectTasks(
+		r.store.db,
+		`SELECT `+taskColumns+`
+		FROM tasks t
+		JOIN task_dependencies d ON d.blocker_id = t.id
+		WHERE d.blocked_id = $1 AND t.archived_at IS NULL
+		ORDER BY t.id`,
+		taskID,
+	)
+}
+
+func (r *DependencyRepository) Blocking(taskID int) ([]*model.Task, error) {
+	return selectTasks(
+		r.store.db,
+		`SELECT `+taskColumns+`
+		FROM tasks t
+		JOIN task_dependencies d ON d.blocked_id = t.id
+		WHERE d.blocker_id = $1 AND t.archived_at IS NULL
+		ORDER BY t.id`,
+		taskID,
+	)
+}
@@ -0,0 +1,26 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+const uniqueViolation = "23505"
+
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == uniqueViolation
+}
+
+// expectAffected turns an UPDATE or DELETE that matched no rows into notFound.
+func expectAffected(result sql.Result, notFound error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
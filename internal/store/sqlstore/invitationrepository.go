@@ -0,0 +1,186 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+const invitationColumns = `i.id, i.team_id, t.name, i.email, i.role, i.invited_by, i.status, i.created_at, i.expires_at, i.responded_at`
+
+type InvitationRepository struct {
+	store *Store
+}
+
+func scanInvitation(row rowScanner) (*model.Invitation, error) {
+	i := &model.Invitation{}
+	if err := row.Scan(
+		&i.ID,
+		&i.TeamID,
+		&i.TeamName,
+		&i.Email,
+		&i.Role,
+		&i.InvitedBy,
+		&i.Status,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.RespondedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+func (r *InvitationRepository) Create(i *model.Invitation) error {
+	i.BeforeCreate()
+
+	if err := i.Validate(); err != nil {
+		return err
+	}
+
+	// освобождаем уникальный индекс от протухших приглашений
+	if _, err := r.ExpireStale(); err != nil {
+		return err
+	}
+
+	err := r.store.db.QueryRow(
+		`INSERT INTO team_invitations (team_id, email, role, invited_by, status, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`,
+		i.TeamID, i.Email, i.Role, i.InvitedBy, i.Status, i.CreatedAt, i.ExpiresAt,
+	).Scan(&i.ID)
+	if isUniqueViolation(err) {
+		return store.ErrAlreadyInvited
+	}
+
+	return err
+}
+
+func (r *InvitationRepository) Find(id int) (*model.Invitation, error) {
+	i, err := scanInvitation(r.store.db.QueryRow(
+		`SELECT `+invitationColumns+`
+		FROM team_invitations i
+		JOIN teams t ON t.id = i.team_id
+		WHERE i.id = $1`,
+		id,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return i, nil
+}
+
+func (r *InvitationRepository) ListPendingByTeam(teamID int) ([]*model.Invitation, error) {
+	return r.queryInvitations(
+		`SELECT `+invitationColumns+`
+		FROM team_invitations i
+		JOIN teams t ON t.id = i.team_id
+		WHERE i.team_id = $1 AND i.status = 'pending' AND i.expires_at > NOW()
+		ORDER BY i.created_at`,
+		teamID,
+	)
+}
+
+func (r *InvitationRepository) ListPendingByEmail(email string) ([]*model.Invitation, error) {
+	return r.queryInvitations(
+		`SELECT `+invitationColumns+`
+		FROM team_invitations i
+		JOIN teams t ON t.id = i.team_id
+		WHERE LOWER(i.email) = LOWER($1) AND i.status = 'pending' AND i.expires_at > NOW()
+		ORDER BY i.created_at`,
+		email,
+	)
+}
+
+func (r *InvitationRepository) queryInvitations(query string, args ...interface{}) ([]*model.Invitation, error) {
+	rows, err := r.store.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var invitations []*model.Invitation
+
+	for rows.Next() {
+		i, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (r *InvitationRepository) Respond(id int, status model.InvitationStatus) error {
+	result, err := r.store.db.Exec(
+		`UPDATE team_invitations SET status = $1, responded_at = NOW()
+		WHERE id = $2 AND status = 'pending' AND expires_at > NOW()`,
+		status, id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result, model.ErrInvitationNotPending)
+}
+
+// Accept marks the invitation accepted and adds the user to the team with
+// the invited role in one transaction. Someone who already joined keeps
+// their current role.
+func (r *InvitationRepository) Accept(id int, userID int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var (
+		teamID int
+		role   model.TeamRole
+	)
+	if err := tx.QueryRow(
+		`UPDATE team_invitations SET status = $1, responded_at = NOW()
+		WHERE id = $2 AND status = 'pending' AND expires_at > NOW()
+		RETURNING team_id, role`,
+		model.InvitationAccepted, id,
+	).Scan(&teamID, &role); err != nil {
+		if err == sql.ErrNoRows {
+			return model.ErrInvitationNotPending
+		}
+		return err
+	}
+
+	if _, err := tx.Exec(
+		`INSERT INTO team_members (user_id, team_id, role, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (user_id, team_id) DO NOTHING`,
+		userID, teamID, role,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *InvitationRepository) ExpireStale() (int64, error) {
+	result, err := r.store.db.Exec(
+		`UPDATE team_invitations SET status = 'expired'
+		WHERE status = 'pending' AND expires_at <= NOW()`,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,194 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type LabelRepository struct {
+	store *Store
+}
+
+func (r *LabelRepository) Create(l *model.Label) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+
+	l.CreatedAt = time.Now()
+
+	if err := r.store.db.QueryRow(
+		`INSERT INTO labels (team_id, name, color, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`,
+		l.TeamID, l.Name, l.Color, l.CreatedAt,
+	).Scan(&l.ID); err != nil {
+		if isUniqueViolation(err) {
+			return store.ErrLabelExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *LabelRepository) Find(id int) (*model.Label, error) {
+	l := &model.Label{}
+	if err := r.store.db.QueryRow(
+		`SELECT id, team_id, name, color, created_at
+		FROM labels
+		WHERE id = $1`,
+		id,
+	).Scan(
+		&l.ID,
+		&l.TeamID,
+		&l.Name,
+		&l.Color,
+		&l.CreatedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func (r *LabelRepository) ListByTeam(teamID int) ([]*model.Label, error) {
+	rows, err := r.store.db.Query(
+		`SELECT id, team_id, name, color, created_at
+		FROM labels
+		WHERE team_id = $1
+		ORDER BY LOWER(name)`,
+		teamID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	labels := []*model.Label{}
+
+	for rows.Next() {
+		l := &model.Label{}
+		if err := rows.Scan(
+			&l.ID,
+			&l.TeamID,
+			&l.Name,
+			&l.Color,
+			&l.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		labels = append(labels, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+func (r *LabelRepository) Update(l *model.Label) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+
+	result, err := r.store.db.Exec(
+		`UPDATE labels SET name = $1, color = $2
+		WHERE id = $3`,
+		l.Name, l.Color, l.ID,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return store.ErrLabelExists
+		}
+		return err
+	}
+
+	return expectAffected(result, store.ErrRecordNotFound)
+}
+
+func (r *LabelRepository) Delete(id int) error {
+	result, err := r.store.db.Exec(
+		`DELETE FROM labels
+		WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result, store.ErrRecordNotFound)
+}
+
+// Attach puts the label on the task. Attaching a label twice is a no-op; a
+// label of another team is reported as not found.
+func (r *LabelRepository) Attach(taskID int, labelID int, by *int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := trackTask(tx, taskID, by, func(*model.Task) error {
+		return attachLabel(tx, taskID, labelID)
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func attachLabel(q querier, taskID int, labelID int) error {
+	var found bool
+	if err := q.QueryRow(
+		`SELECT EXISTS (
+			SELECT 1 FROM labels l
+			JOIN tasks t ON t.team_id = l.team_id
+			WHERE l.id = $1 AND t.id = $2 AND t.archived_at IS NULL
+		)`,
+		labelID, taskID,
+	).Scan(&found); err != nil {
+		return err
+	}
+	if !found {
+		return store.ErrRecordNotFound
+	}
+
+	_, err := q.Exec(
+		`INSERT INTO task_labels (task_id, label_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`,
+		taskID, labelID,
+	)
+	return err
+}
+
+func (r *LabelRepository) Detach(taskID int, labelID int, by *int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := trackTask(tx, taskID, by, func(*model.Task) error {
+		result, err := tx.Exec(
+			`DELETE FROM task_labels
+			WHERE task_id = $1 AND label_id = $2`,
+			taskID, labelID,
+		)
+		if err != nil {
+			return err
+		}
+		return expectAffected(result, store.ErrRecordNotFound)
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
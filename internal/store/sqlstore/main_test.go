@@ -0,0 +1,14 @@
+package sqlstore_test
+
+import (
+	"os"
+	"testing"
+)
+
+var databaseURL string
+
+func TestMain(m *testing.M) {
+	databaseURL = os.Getenv("TEST_DATABASE_URL")
+
+	os.Exit(m.Run())
+}
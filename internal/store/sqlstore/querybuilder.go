@@ -0,0 +1,42 @@
+package sqlstore
+
+import (
+	"strconv"
+	"strings"
+)
+
+// queryBuilder collects WHERE conditions with positional arguments.
+// Conditions use "?" as a placeholder which is rewritten to $N.
+type queryBuilder struct {
+	conds []string
+	args  []interface{}
+}
+
+func (b *queryBuilder) arg(v interface{}) string {
+	b.args = append(b.args, v)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+func (b *queryBuilder) where(cond string, args ...interface{}) {
+	var sb strings.Builder
+	for _, a := range args {
+		i := strings.IndexByte(cond, '?')
+		sb.WriteString(cond[:i])
+		sb.WriteString(b.arg(a))
+		cond = cond[i+1:]
+	}
+	sb.WriteString(cond)
+	b.conds = append(b.conds, sb.String())
+}
+
+func (b *queryBuilder) whereSQL() string {
+	if len(b.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conds, " AND ")
+}
+
+func likePattern(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(s) + "%"
+}
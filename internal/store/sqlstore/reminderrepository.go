@@ -0,0 +1,127 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/qeery8/rest/internal/model"
+)
+
+type ReminderRepository struct {
+	store *Store
+}
+
+// Pending returns the reminders of the kind for unfinished tasks due in
+// [from, to) that no run has recorded yet, one per recipient: every
+// assignee, or the team owner for an unassigned task. The lead is the
+// smallest of leads that still covers the time left until the due date,
+// so a task is reminded once per window it enters; without leads it is 0.
+func (r *ReminderRepository) Pending(kind model.ReminderKind, from time.Time, to time.Time, leads []time.Duration) ([]*model.PendingReminder, error) {
+	seconds := make([]int64, len(leads))
+	for i, l := range leads {
+		seconds[i] = int64(l.Seconds())
+	}
+
+	rows, err := r.store.db.Query(
+		`WITH due AS (
+			SELECT t.id, t.name, t.team_id, t.due_date,
+				COALESCE((
+					SELECT MIN(l) FROM unnest($3::int[]) l
+					WHERE l >= EXTRACT(EPOCH FROM t.due_date - $1)
+				), 0) AS lead_seconds
+			FROM tasks t
+			WHERE t.due_date >= $1 AND t.due_date < $2 AND t.archived_at IS NULL
+			AND NOT `+inCategory("t", model.CategoryDone)+`
+		), recipients AS (
+			SELECT d.*, ta.user_id
+			FROM due d
+			JOIN task_assignees ta ON ta.task_id = d.id
+			UNION ALL
+			SELECT d.*, tm.owner_id
+			FROM due d
+			JOIN teams tm ON tm.id = d.team_id
+			WHERE NOT EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = d.id)
+		)
+		SELECT rc.id, rc.name, rc.due_date, rc.lead_seconds, u.id, u.email
+		FROM recipients rc
+		JOIN users u ON u.id = rc.user_id
+		WHERE NOT EXISTS (
+			SELECT 1 FROM task_reminders rem
+			WHERE rem.task_id = rc.id AND rem.user_id = rc.user_id AND rem.kind = $4
+			AND rem.lead_seconds = rc.lead_seconds AND rem.due_date = rc.due_date
+		)
+		ORDER BY rc.due_date, rc.id, u.id`,
+		from, to, pq.Array(seconds), kind,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var pending []*model.PendingReminder
+
+	for rows.Next() {
+		p := &model.PendingReminder{}
+		var lead int64
+		if err := rows.Scan(
+			&p.TaskID,
+			&p.TaskName,
+			&p.DueDate,
+			&lead,
+			&p.UserID,
+			&p.Email,
+		); err != nil {
+			return nil, err
+		}
+		p.Kind = kind
+		p.Lead = time.Duration(lead) * time.Second
+		pending = append(pending, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// Record claims the reminder. It returns false when the same reminder has
+// already been recorded, in which case nothing should be delivered.
+func (r *ReminderRepository) Record(rem *model.Reminder) (bool, error) {
+	rem.CreatedAt = time.Now()
+
+	err := r.store.db.QueryRow(
+		`INSERT INTO task_reminders (task_id, user_id, kind, lead_seconds, due_date, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT DO NOTHING
+		RETURNING id`,
+		rem.TaskID, rem.UserID, rem.Kind, int(rem.Lead.Seconds()), rem.DueDate, rem.CreatedAt,
+	).Scan(&rem.ID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *ReminderRepository) MarkSent(id int) error {
+	_, err := r.store.db.Exec(
+		`UPDATE task_reminders SET sent_at = NOW()
+		WHERE id = $1`,
+		id,
+	)
+	return err
+}
+
+func (r *ReminderRepository) Delete(id int) error {
+	_, err := r.store.db.Exec(
+		`DELETE FROM task_reminders
+		WHERE id = $1`,
+		id,
+	)
+	return err
+}
@@ -0,0 +1,191 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+)
+
+// ReportRepository builds team metrics out of task_status_transitions.
+type ReportRepository struct {
+	store *Store
+}
+
+// Burndown covers every day from from to to. With a sprint the scope is
+// the sprint's tasks plus whatever it committed to at the start; without
+// one it is all tasks of the team.
+func (r *ReportRepository) Burndown(teamID int, sprintID *int, from time.Time, to time.Time) (*model.Burndown, error) {
+	rows, err := r.store.db.Query(
+		`WITH scope AS (
+			SELECT t.id, t.created_at FROM tasks t
+			WHERE t.team_id = $1 AND t.archived_at IS NULL AND (
+				$4::bigint IS NULL
+				OR t.sprint_id = $4
+				OR t.id IN (SELECT c.task_id FROM sprint_commitments c WHERE c.sprint_id = $4)
+			)
+		), days AS (
+			SELECT d::date AS day, d + INTERVAL '1 day' AS day_end
+			FROM generate_series($2::date, $3::date, INTERVAL '1 day') d
+		)
+		SELECT days.day,
+			COUNT(scope.id),
+			COUNT(scope.id) FILTER (WHERE COALESCE((
+				SELECT tr.to_category FROM task_status_transitions tr
+				WHERE tr.task_id = scope.id AND tr.changed_at < days.day_end
+				ORDER BY tr.changed_at DESC, tr.id DESC
+				LIMIT 1
+			), '') <> 'done')
+		FROM days
+		LEFT JOIN scope ON scope.created_at < days.day_end
+		GROUP BY days.day
+		ORDER BY days.day`,
+		teamID, from, to, sprintID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	b := &model.Burndown{
+		TeamID:   teamID,
+		SprintID: sprintID,
+		Points:   []*model.BurndownPoint{},
+	}
+
+	for rows.Next() {
+		p := &model.BurndownPoint{}
+		if err := rows.Scan(&p.Date, &p.Total, &p.Remaining); err != nil {
+			return nil, err
+		}
+		b.Points = append(b.Points, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	b.FillIdeal()
+	return b, nil
+}
+
+func (r *ReportRepository) CycleTime(teamID int, from time.Time, to time.Time) (model.CycleTimeReport, error) {
+	rows, err := r.store.db.Query(
+		`WITH finished AS (
+			SELECT t.priority, t.created_at,
+				(SELECT MAX(tr.changed_at) FROM task_status_transitions tr
+					WHERE tr.task_id = t.id AND tr.to_category = 'done') AS done_at,
+				(SELECT MIN(tr.changed_at) FROM task_status_transitions tr
+					WHERE tr.task_id = t.id AND tr.to_category = 'in_progress') AS started_at
+			FROM tasks t
+			WHERE t.team_id = $1 AND t.archived_at IS NULL AND `+inCategory("t", model.CategoryDone)+`
+		)
+		SELECT priority,
+			COUNT(*),
+			AVG(EXTRACT(EPOCH FROM done_at - created_at)) / 3600,
+			AVG(EXTRACT(EPOCH FROM done_at - started_at)) FILTER (WHERE started_at <= done_at) / 3600
+		FROM finished
+		WHERE done_at >= $2 AND done_at < $3
+		GROUP BY priority
+		ORDER BY priority`,
+		teamID, from, to,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	report := model.CycleTimeReport{}
+
+	for rows.Next() {
+		c := &model.CycleTime{}
+		var cycle sql.NullFloat64
+		if err := rows.Scan(&c.Priority, &c.Completed, &c.AvgLeadHours, &cycle); err != nil {
+			return nil, err
+		}
+		if cycle.Valid {
+			c.AvgCycleHours = &cycle.Float64
+		}
+		report = append(report, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Throughput counts tasks moved into a done status per week, weeks
+// starting on Monday. A task finished twice in one week counts once.
+func (r *ReportRepository) Throughput(teamID int, from time.Time, to time.Time) (model.ThroughputReport, error) {
+	rows, err := r.store.db.Query(
+		`SELECT w, COUNT(DISTINCT tr.task_id)
+		FROM generate_series(date_trunc('week', $2::timestamptz), $3::timestamptz, INTERVAL '1 week') w
+		LEFT JOIN task_status_transitions tr
+			ON tr.team_id = $1 AND tr.to_category = 'done'
+			AND tr.changed_at >= w AND tr.changed_at < w + INTERVAL '1 week'
+			AND tr.changed_at >= $2 AND tr.changed_at < $3
+		GROUP BY w
+		ORDER BY w`,
+		teamID, from, to,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	report := model.ThroughputReport{}
+
+	for rows.Next() {
+		t := &model.Throughput{}
+		if err := rows.Scan(&t.WeekStart, &t.Completed); err != nil {
+			return nil, err
+		}
+		report = append(report, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (r *ReportRepository) Workload(teamID int) (model.WorkloadReport, error) {
+	rows, err := r.store.db.Query(
+		`SELECT ta.user_id, u.email,
+			COUNT(*) FILTER (WHERE `+inCategory("t", model.CategoryToDo)+`),
+			COUNT(*) FILTER (WHERE `+inCategory("t", model.CategoryInProgress)+`),
+			COUNT(*) FILTER (WHERE t.due_date < NOW()),
+			COUNT(*)
+		FROM tasks t
+		LEFT JOIN task_assignees ta ON ta.task_id = t.id
+		LEFT JOIN users u ON u.id = ta.user_id
+		WHERE t.team_id = $1 AND t.archived_at IS NULL AND NOT `+inCategory("t", model.CategoryDone)+`
+		GROUP BY ta.user_id, u.email
+		ORDER BY COUNT(*) DESC, u.email`,
+		teamID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	report := model.WorkloadReport{}
+
+	for rows.Next() {
+		w := &model.Workload{}
+		if err := rows.Scan(&w.AssigneeID, &w.Email, &w.ToDo, &w.InProgress, &w.Overdue, &w.Total); err != nil {
+			return nil, err
+		}
+		report = append(report, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
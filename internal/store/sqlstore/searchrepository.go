@@ -0,0 +1,125 @@
+package sqlstore
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+// headline is the snippet of text around the matched words, bounded by
+// model.SnippetStart and model.SnippetStop. Those characters are dropped
+// from the text first, so the bounds only ever come from ts_headline.
+func headline(text string) string {
+	return `ts_headline('simple', translate(` + text + `, E'\x02\x03', ''), q.query,
+		E'StartSel="\x02", StopSel="\x03", MaxFragments=2, MaxWords=20, MinWords=5')`
+}
+
+// searchParts select the hits of every kind as
+// (kind, id, team_id, task_id, title, snippet, rank).
+var searchParts = []struct {
+	kind  model.SearchKind
+	query string
+}{
+	{model.SearchTask, `SELECT 'task', t.id, t.team_id, NULL::int, t.name,
+		` + headline(`t.name || ' ' || COALESCE(t.content, '')`) + `,
+		ts_rank(t.search, q.query)
+		FROM q, tasks t
+		WHERE t.search @@ q.query AND t.archived_at IS NULL AND t.team_id IN (SELECT team_id FROM members)`},
+	{model.SearchComment, `SELECT 'comment', c.id, t.team_id, c.task_id, t.name,
+		` + headline(`c.body`) + `,
+		ts_rank(c.search, q.query)
+		FROM q, task_comments c
+		JOIN tasks t ON t.id = c.task_id
+		WHERE c.search @@ q.query AND t.archived_at IS NULL AND t.team_id IN (SELECT team_id FROM members)`},
+	{model.SearchTeam, `SELECT 'team', tm.id, tm.id, NULL::int, tm.name,
+		` + headline(`COALESCE(NULLIF(tm.description, ''), tm.name)`) + `,
+		ts_rank(tm.search, q.query)
+		FROM q, teams tm
+		WHERE tm.search @@ q.query AND tm.id IN (SELECT team_id FROM members)`},
+}
+
+type SearchRepository struct {
+	store *Store
+}
+
+// Search matches the words of the query, with web search syntax for quoted
+// phrases, "or" and "-" exclusions, and orders the hits by rank.
+func (r *SearchRepository) Search(sq *store.SearchQuery) (*model.SearchResult, error) {
+	if sq.Limit <= 0 {
+		sq.Limit = store.DefaultSearchSize
+	}
+	if sq.Limit > store.MaxSearchSize {
+		sq.Limit = store.MaxSearchSize
+	}
+
+	var parts []string
+	for _, p := range searchParts {
+		if searchKind(sq.Kinds, p.kind) {
+			parts = append(parts, p.query)
+		}
+	}
+
+	rows, err := r.store.db.Query(
+		`WITH q AS (
+			SELECT websearch_to_tsquery('simple', $1) AS query
+		), members AS (
+			SELECT team_id FROM team_members
+			WHERE user_id = $2 AND ($3::bigint IS NULL OR team_id = $3)
+		)
+		`+strings.Join(parts, "\nUNION ALL\n")+`
+		ORDER BY 7 DESC, 1, 2
+		LIMIT `+strconv.Itoa(sq.Limit+1)+` OFFSET `+strconv.Itoa(sq.Offset),
+		sq.Text, sq.MemberID, sq.TeamID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	result := &model.SearchResult{
+		Hits: []*model.SearchHit{},
+	}
+
+	for rows.Next() {
+		h := &model.SearchHit{}
+		if err := rows.Scan(
+			&h.Kind,
+			&h.ID,
+			&h.TeamID,
+			&h.TaskID,
+			&h.Title,
+			&h.Snippet,
+			&h.Rank,
+		); err != nil {
+			return nil, err
+		}
+		h.Snippet = model.SnippetHTML(h.Snippet)
+		result.Hits = append(result.Hits, h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(result.Hits) > sq.Limit {
+		result.Hits = result.Hits[:sq.Limit]
+		next := sq.Offset + sq.Limit
+		result.NextOffset = &next
+	}
+	return result, nil
+}
+
+func searchKind(kinds []model.SearchKind, kind model.SearchKind) bool {
+	if len(kinds) == 0 {
+		return true
+	}
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,375 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+const sprintColumns = `id, team_id, name, goal, start_date, end_date, state, carried_over, started_at, closed_at, created_at`
+
+type SprintRepository struct {
+	store *Store
+}
+
+func scanSprint(row rowScanner) (*model.Sprint, error) {
+	s := &model.Sprint{}
+	if err := row.Scan(
+		&s.ID,
+		&s.TeamID,
+		&s.Name,
+		&s.Goal,
+		&s.StartDate,
+		&s.EndDate,
+		&s.State,
+		&s.CarriedOver,
+		&s.StartedAt,
+		&s.ClosedAt,
+		&s.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *SprintRepository) Create(s *model.Sprint) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	s.State = model.SprintPlanned
+	s.CreatedAt = time.Now()
+
+	return r.store.db.QueryRow(
+		`INSERT INTO sprints (team_id, name, goal, start_date, end_date, state, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`,
+		s.TeamID, s.Name, s.Goal, s.StartDate, s.EndDate, s.State, s.CreatedAt,
+	).Scan(&s.ID)
+}
+
+func (r *SprintRepository) Find(id int) (*model.Sprint, error) {
+	return findSprint(r.store.db, id, "")
+}
+
+// findSprint loads a sprint, optionally locking it with lock ("FOR UPDATE"
+// or "FOR SHARE") inside a transaction.
+func findSprint(q querier, id int, lock string) (*model.Sprint, error) {
+	s, err := scanSprint(q.QueryRow(
+		`SELECT `+sprintColumns+`
+		FROM sprints
+		WHERE id = $1 `+lock,
+		id,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *SprintRepository) ListByTeam(teamID int) ([]*model.Sprint, error) {
+	rows, err := r.store.db.Query(
+		`SELECT `+sprintColumns+`
+		FROM sprints
+		WHERE team_id = $1
+		ORDER BY start_date DESC, id DESC`,
+		teamID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	sprints := []*model.Sprint{}
+
+	for rows.Next() {
+		s, err := scanSprint(rows)
+		if err != nil {
+			return nil, err
+		}
+		sprints = append(sprints, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return sprints, nil
+}
+
+func (r *SprintRepository) Update(s *model.Sprint) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	result, err := r.store.db.Exec(
+		`UPDATE sprints SET name = $1, goal = $2, start_date = $3, end_date = $4
+		WHERE id = $5 AND state <> 'closed'`,
+		s.Name, s.Goal, s.StartDate, s.EndDate, s.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result, model.ErrSprintClosed)
+}
+
+// Start activates a planned sprint and records the tasks planned into it as
+// the team's commitment.
+func (r *SprintRepository) Start(id int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	s, err := findSprint(tx, id, "FOR UPDATE")
+	if err != nil {
+		return err
+	}
+	if s.State != model.SprintPlanned {
+		return model.ErrSprintNotPlanned
+	}
+
+	if _, err := tx.Exec(
+		`UPDATE sprints SET state = 'active', started_at = NOW()
+		WHERE id = $1`,
+		id,
+	); err != nil {
+		if isUniqueViolation(err) {
+			return model.ErrSprintActiveExists
+		}
+		return err
+	}
+
+	if _, err := tx.Exec(
+		`INSERT INTO sprint_commitments (sprint_id, task_id)
+		SELECT $1, id FROM tasks WHERE sprint_id = $1 AND archived_at IS NULL`,
+		id,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Close finishes an active sprint. Unfinished tasks move to the carryTo
+// sprint, or back to the backlog when carryTo is nil.
+func (r *SprintRepository) Close(id int, carryTo *int, by *int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	s, err := findSprint(tx, id, "FOR UPDATE")
+	if err != nil {
+		return err
+	}
+	if s.State != model.SprintActive {
+		return model.ErrSprintNotActive
+	}
+
+	if carryTo != nil {
+		next, err := findSprint(tx, *carryTo, "FOR SHARE")
+		if err != nil || next.ID == s.ID || next.TeamID != s.TeamID || !next.Open() {
+			return model.ErrSprintCarryOver
+		}
+	}
+
+	carried, err := lockSprintTasks(tx,
+		`t.sprint_id = $1 AND t.archived_at IS NULL AND NOT `+inCategory("t", model.CategoryDone),
+		id,
+	)
+	if err != nil {
+		return err
+	}
+	if err := moveToSprint(tx, carried, carryTo, by); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(
+		`UPDATE sprints SET state = 'closed', closed_at = NOW(), carried_over = $2
+		WHERE id = $1`,
+		id, len(carried),
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AddTasks plans tasks into the sprint. All tasks must belong to the
+// sprint's team; a task already in another sprint is moved.
+func (r *SprintRepository) AddTasks(sprintID int, taskIDs []int, by *int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	s, err := findSprint(tx, sprintID, "FOR SHARE")
+	if err != nil {
+		return err
+	}
+	if !s.Open() {
+		return model.ErrSprintClosed
+	}
+
+	seen := make(map[int]bool, len(taskIDs))
+	ids := make([]int64, 0, len(taskIDs))
+	for _, id := range taskIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, int64(id))
+		}
+	}
+
+	tasks, err := lockSprintTasks(tx,
+		`t.id = ANY($1) AND t.team_id = $2 AND t.archived_at IS NULL`,
+		pq.Array(ids), s.TeamID,
+	)
+	if err != nil {
+		return err
+	}
+	if len(tasks) != len(ids) {
+		return store.ErrRecordNotFound
+	}
+	if err := moveToSprint(tx, tasks, &sprintID, by); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RemoveTask sends a task of the sprint back to the backlog.
+func (r *SprintRepository) RemoveTask(sprintID int, taskID int, by *int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	tasks, err := lockSprintTasks(tx,
+		`t.id = $1 AND t.sprint_id = $2 AND t.archived_at IS NULL
+		AND EXISTS (SELECT 1 FROM sprints s WHERE s.id = $2 AND s.state <> 'closed')`,
+		taskID, sprintID,
+	)
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		return store.ErrRecordNotFound
+	}
+	if err := moveToSprint(tx, tasks, nil, by); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// lockSprintTasks locks the tasks matching where and returns them with the
+// sprint they are in now.
+func lockSprintTasks(q querier, where string, args ...interface{}) ([]*model.Task, error) {
+	rows, err := q.Query(
+		`SELECT t.id, t.team_id, t.sprint_id
+		FROM tasks t
+		WHERE `+where+`
+		ORDER BY t.id
+		FOR UPDATE`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []*model.Task
+	for rows.Next() {
+		t := &model.Task{}
+		if err := rows.Scan(&t.ID, &t.TeamID, &t.SprintID); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks, rows.Err()
+}
+
+// moveToSprint puts the tasks into sprint to, or into the backlog when it
+// is nil, and records the move in their history.
+func moveToSprint(q querier, tasks []*model.Task, to *int, by *int) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(tasks))
+	var entries []*model.Activity
+	for i, t := range tasks {
+		ids[i] = int64(t.ID)
+
+		moved := *t
+		moved.SprintID = to
+		entries = append(entries, model.DiffTasks(t, &moved, by)...)
+	}
+
+	if _, err := q.Exec(
+		`UPDATE tasks SET sprint_id = $2, updated_at = NOW()
+		WHERE id = ANY($1)`,
+		pq.Array(ids), to,
+	); err != nil {
+		return err
+	}
+
+	return recordActivity(q, entries...)
+}
+
+func (r *SprintRepository) Summary(id int) (*model.SprintSummary, error) {
+	sum := &model.SprintSummary{SprintID: id}
+	if err := r.store.db.QueryRow(
+		`SELECT
+			s.state,
+			s.carried_over,
+			(SELECT COUNT(*) FROM sprint_commitments c WHERE c.sprint_id = s.id),
+			(SELECT COUNT(*) FROM sprint_commitments c
+				JOIN tasks t ON t.id = c.task_id
+				WHERE c.sprint_id = s.id AND t.sprint_id = s.id AND t.archived_at IS NULL AND `+inCategory("t", model.CategoryDone)+`),
+			(SELECT COUNT(*) FROM tasks t WHERE t.sprint_id = s.id AND t.archived_at IS NULL),
+			(SELECT COUNT(*) FROM tasks t WHERE t.sprint_id = s.id AND t.archived_at IS NULL AND `+inCategory("t", model.CategoryDone)+`),
+			(SELECT COUNT(*) FROM tasks t WHERE t.sprint_id = s.id AND t.archived_at IS NULL AND NOT EXISTS (
+				SELECT 1 FROM sprint_commitments c WHERE c.sprint_id = s.id AND c.task_id = t.id
+			))
+		FROM sprints s
+		WHERE s.id = $1`,
+		id,
+	).Scan(
+		&sum.State,
+		&sum.CarriedOver,
+		&sum.Committed,
+		&sum.CommittedCompleted,
+		&sum.Total,
+		&sum.Completed,
+		&sum.Added,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	// до старта обязательств еще нет, считаем ими все запланированное
+	if sum.State == model.SprintPlanned {
+		sum.Committed = sum.Total
+		sum.CommittedCompleted = sum.Completed
+		sum.Added = 0
+	}
+	sum.Remaining = sum.Total - sum.Completed
+
+	return sum, nil
+}
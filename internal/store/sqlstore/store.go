@@ -6,11 +6,35 @@ import (
 	"github.com/qeery8/rest/internal/store"
 )
 
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
 type Store struct {
 	db             *sql.DB
 	userRepository *UserRepository
 	teamRepository *TeamRepository
 	taskRepository *TaskRepository
+
+	invitationRepository  *InvitationRepository
+	reminderRepository    *ReminderRepository
+	archiveRepository     *ArchiveRepository
+	commentRepository     *CommentRepository
+	activityRepository    *ActivityRepository
+	checklistRepository   *ChecklistRepository
+	dependencyRepository  *DependencyRepository
+	labelRepository       *LabelRepository
+	workflowRepository    *WorkflowRepository
+	sprintRepository      *SprintRepository
+	reportRepository      *ReportRepository
+	worklogRepository     *WorklogRepository
+	templateRepository    *TemplateRepository
+	customFieldRepository *CustomFieldRepository
+	searchRepository      *SearchRepository
+	attachmentRepository  *AttachmentRepository
 }
 
 func New(db *sql.DB) *Store {
@@ -54,3 +78,195 @@ func (s *Store) Task() store.TaskRepository {
 
 	return s.taskRepository
 }
+
+func (s *Store) Invitation() store.InvitationRepository {
+	if s.invitationRepository != nil {
+		return s.invitationRepository
+	}
+
+	s.invitationRepository = &InvitationRepository{
+		store: s,
+	}
+
+	return s.invitationRepository
+}
+
+func (s *Store) Reminder() store.ReminderRepository {
+	if s.reminderRepository != nil {
+		return s.reminderRepository
+	}
+
+	s.reminderRepository = &ReminderRepository{
+		store: s,
+	}
+
+	return s.reminderRepository
+}
+
+func (s *Store) Archive() store.ArchiveRepository {
+	if s.archiveRepository != nil {
+		return s.archiveRepository
+	}
+
+	s.archiveRepository = &ArchiveRepository{
+		store: s,
+	}
+
+	return s.archiveRepository
+}
+
+func (s *Store) Comment() store.CommentRepository {
+	if s.commentRepository != nil {
+		return s.commentRepository
+	}
+
+	s.commentRepository = &CommentRepository{
+		store: s,
+	}
+
+	return s.commentRepository
+}
+
+func (s *Store) Activity() store.ActivityRepository {
+	if s.activityRepository != nil {
+		return s.activityRepository
+	}
+
+	s.activityRepository = &ActivityRepository{
+		store: s,
+	}
+
+	return s.activityRepository
+}
+
+func (s *Store) Checklist() store.ChecklistRepository {
+	if s.checklistRepository != nil {
+		return s.checklistRepository
+	}
+
+	s.checklistRepository = &ChecklistRepository{
+		store: s,
+	}
+
+	return s.checklistRepository
+}
+
+func (s *Store) Dependency() store.DependencyRepository {
+	if s.dependencyRepository != nil {
+		return s.dependencyRepository
+	}
+
+	s.dependencyRepository = &DependencyRepository{
+		store: s,
+	}
+
+	return s.dependencyRepository
+}
+
+func (s *Store) Label() store.LabelRepository {
+	if s.labelRepository != nil {
+		return s.labelRepository
+	}
+
+	s.labelRepository = &LabelRepository{
+		store: s,
+	}
+
+	return s.labelRepository
+}
+
+func (s *Store) Workflow() store.WorkflowRepository {
+	if s.workflowRepository != nil {
+		return s.workflowRepository
+	}
+
+	s.workflowRepository = &WorkflowRepository{
+		store: s,
+	}
+
+	return s.workflowRepository
+}
+
+func (s *Store) Sprint() store.SprintRepository {
+	if s.sprintRepository != nil {
+		return s.sprintRepository
+	}
+
+	s.sprintRepository = &SprintRepository{
+		store: s,
+	}
+
+	return s.sprintRepository
+}
+
+func (s *Store) Report() store.ReportRepository {
+	if s.reportRepository != nil {
+		return s.reportRepository
+	}
+
+	s.reportRepository = &ReportRepository{
+		store: s,
+	}
+
+	return s.reportRepository
+}
+
+func (s *Store) Worklog() store.WorklogRepository {
+	if s.worklogRepository != nil {
+		return s.worklogRepository
+	}
+
+	s.worklogRepository = &WorklogRepository{
+		store: s,
+	}
+
+	return s.worklogRepository
+}
+
+func (s *Store) Template() store.TemplateRepository {
+	if s.templateRepository != nil {
+		return s.templateRepository
+	}
+
+	s.templateRepository = &TemplateRepository{
+		store: s,
+	}
+
+	return s.templateRepository
+}
+
+func (s *Store) CustomField() store.CustomFieldRepository {
+	if s.customFieldRepository != nil {
+		return s.customFieldRepository
+	}
+
+	s.customFieldRepository = &CustomFieldRepository{
+		store: s,
+	}
+
+	return s.customFieldRepository
+}
+
+func (s *Store) Search() store.SearchRepository {
+	if s.searchRepository != nil {
+		return s.searchRepository
+	}
+
+	s.searchRepository = &SearchRepository{
+		store: s,
+	}
+
+	return s.searchRepository
+}
+
+func (s *Store) Attachment() store.AttachmentRepository {
+	if s.attachmentRepository != nil {
+		return s.attachmentRepository
+	}
+
+	s.attachmentRepository = &AttachmentRepository{
+		store: s,
+	}
+
+	return s.attachmentRepository
+}
@@ -0,0 +1,239 @@
+package sqlstore
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type taskSort struct {
+	expr  string
+	cast  string
+	value func(*model.Task) string
+}
+
+var taskSorts = map[string]taskSort{
+	store.SortCreatedAt: {
+		expr: "t.created_at",
+		cast: "timestamptz",
+		value: func(t *model.Task) string {
+			return t.CreatedAt.Format(time.RFC3339Nano)
+		},
+	},
+	store.SortDueDate: {
+		expr: "COALESCE(t.due_date, 'infinity')",
+		cast: "timestamptz",
+		value: func(t *model.Task) string {
+			if t.DueDate == nil {
+				return "infinity"
+			}
+			return t.DueDate.Format(time.RFC3339Nano)
+		},
+	},
+	store.SortPriority: {
+		expr: "t.priority",
+		cast: "task_priority",
+		value: func(t *model.Task) string {
+			return string(t.Priority)
+		},
+	},
+}
+
+// taskCursor is the position after the last row of a page. It remembers the
+// sort it was issued for so it cannot be replayed against another ordering.
+type taskCursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d"`
+	Value string `json:"v"`
+	ID    int    `json:"id"`
+}
+
+func (c *taskCursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeTaskCursor(s string) (*taskCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, store.ErrInvalidCursor
+	}
+
+	c := &taskCursor{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, store.ErrInvalidCursor
+	}
+	return c, nil
+}
+
+func (r *TaskRepository) Find(f *store.TaskFilter) (*store.TaskPage, error) {
+	if f.Sort == "" {
+		f.Sort = store.SortCreatedAt
+	}
+	sort, ok := taskSorts[f.Sort]
+	if !ok {
+		return nil, store.ErrInvalidSort
+	}
+
+	if f.Limit <= 0 {
+		f.Limit = store.DefaultPageSize
+	}
+	if f.Limit > store.MaxPageSize {
+		f.Limit = store.MaxPageSize
+	}
+
+	b := &queryBuilder{}
+	applyTaskFilter(b, f)
+
+	if f.Cursor != "" {
+		c, err := decodeTaskCursor(f.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if c.Sort != f.Sort || c.Desc != f.Desc {
+			return nil, store.ErrInvalidCursor
+		}
+
+		op := ">"
+		if f.Desc {
+			op = "<"
+		}
+		b.where("("+sort.expr+", t.id) "+op+" (?::"+sort.cast+", ?)", c.Value, c.ID)
+	}
+
+	dir := "ASC"
+	if f.Desc {
+		dir = "DESC"
+	}
+
+	query := `SELECT ` + taskColumns + `
+		FROM tasks t` + b.whereSQL() + `
+		ORDER BY ` + sort.expr + ` ` + dir + `, t.id ` + dir + `
+		LIMIT ` + strconv.Itoa(f.Limit+1)
+
+	tasks, err := r.queryTasks(query, b.args...)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &store.TaskPage{
+		Tasks: tasks,
+	}
+
+	if len(tasks) > f.Limit {
+		page.Tasks = tasks[:f.Limit]
+		last := page.Tasks[f.Limit-1]
+		page.NextCursor = (&taskCursor{
+			Sort:  f.Sort,
+			Desc:  f.Desc,
+			Value: sort.value(last),
+			ID:    last.ID,
+		}).encode()
+	}
+
+	if page.Tasks == nil {
+		page.Tasks = []*model.Task{}
+	}
+
+	return page, nil
+}
+
+func applyTaskFilter(b *queryBuilder, f *store.TaskFilter) {
+	b.where("t.team_id IN (SELECT team_id FROM team_members WHERE user_id = ?)", f.MemberID)
+	b.where("t.archived_at IS NULL")
+
+	if f.TeamID != nil {
+		b.where("t.team_id = ?", *f.TeamID)
+	}
+	if len(f.Statuses) > 0 {
+		statuses := make([]string, len(f.Statuses))
+		for i, st := range f.Statuses {
+			statuses[i] = string(st)
+		}
+		b.where("t.status = ANY(?)", pq.Array(statuses))
+	}
+	if len(f.Priorities) > 0 {
+		priorities := make([]string, len(f.Priorities))
+		for i, p := range f.Priorities {
+			priorities[i] = string(p)
+		}
+		b.where("t.priority::text = ANY(?)", pq.Array(priorities))
+	}
+	if f.AssigneeID != nil {
+		b.where("EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = t.id AND ta.user_id = ?)", *f.AssigneeID)
+	}
+	if f.WatcherID != nil {
+		b.where("EXISTS (SELECT 1 FROM task_watchers tw WHERE tw.task_id = t.id AND tw.user_id = ?)", *f.WatcherID)
+	}
+	if f.InvolvedID != nil {
+		b.where(`(EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = t.id AND ta.user_id = ?)
+			OR EXISTS (SELECT 1 FROM task_watchers tw WHERE tw.task_id = t.id AND tw.user_id = ?))`, *f.InvolvedID, *f.InvolvedID)
+	}
+	if f.DueFrom != nil {
+		b.where("t.due_date >= ?", *f.DueFrom)
+	}
+	if f.DueTo != nil {
+		b.where("t.due_date <= ?", *f.DueTo)
+	}
+	if len(f.LabelIDs) > 0 {
+		ids := make([]int64, len(f.LabelIDs))
+		for i, id := range f.LabelIDs {
+			ids[i] = int64(id)
+		}
+		if f.AllLabels {
+			b.where("(SELECT COUNT(DISTINCT tl.label_id) FROM task_labels tl WHERE tl.task_id = t.id AND tl.label_id = ANY(?)) = ?", pq.Array(ids), len(ids))
+		} else {
+			b.where("EXISTS (SELECT 1 FROM task_labels tl WHERE tl.task_id = t.id AND tl.label_id = ANY(?))", pq.Array(ids))
+		}
+	}
+	if f.SprintID != nil {
+		b.where("t.sprint_id = ?", *f.SprintID)
+	}
+	if f.Backlog {
+		b.where("t.sprint_id IS NULL")
+	}
+	for _, ff := range f.Fields {
+		applyFieldFilter(b, ff)
+	}
+	if f.Query != "" {
+		b.where("(t.name ILIKE ? OR t.content ILIKE ?)", likePattern(f.Query), likePattern(f.Query))
+	}
+}
+
+const fieldValueExists = `EXISTS (
+		SELECT 1 FROM task_field_values v
+		JOIN custom_fields cf ON cf.id = v.field_id
+		WHERE v.task_id = t.id AND cf.key = ? AND `
+
+func applyFieldFilter(b *queryBuilder, ff store.FieldFilter) {
+	if len(ff.Values) > 0 {
+		values := make([]string, 0, len(ff.Values))
+		for _, v := range ff.Values {
+			values = append(values, v)
+			if n, err := strconv.ParseFloat(v, 64); err == nil {
+				values = append(values, model.FormatFieldNumber(n))
+			}
+		}
+		b.where(fieldValueExists+"v.value = ANY(?))", ff.Key, pq.Array(values))
+	}
+	if ff.From != "" {
+		b.where(fieldValueExists+fieldBound(ff.From, ">="), ff.Key, ff.From)
+	}
+	if ff.To != "" {
+		b.where(fieldValueExists+fieldBound(ff.To, "<="), ff.Key, ff.To)
+	}
+}
+
+// fieldBound compares a range bound with the typed column it parses as.
+// The handler only lets through numbers and dates.
+func fieldBound(v string, op string) string {
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return "v.value_number " + op + " ?::numeric)"
+	}
+	return "v.value_date " + op + " ?::date)"
+}
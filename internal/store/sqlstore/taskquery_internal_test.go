@@ -0,0 +1,77 @@
+package sqlstore
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+func TestTaskCursor(t *testing.T) {
+	testCases := []struct {
+		name   string
+		cursor taskCursor
+	}{
+		{name: "created at", cursor: taskCursor{Sort: store.SortCreatedAt, Value: "2026-03-01T09:00:00.123456Z", ID: 42}},
+		{name: "descending", cursor: taskCursor{Sort: store.SortPriority, Desc: true, Value: "high", ID: 7}},
+		{name: "no due date", cursor: taskCursor{Sort: store.SortDueDate, Value: "infinity", ID: 1}},
+		{name: "value with quotes", cursor: taskCursor{Sort: store.SortPriority, Value: `"); DROP TABLE tasks; --`, ID: 3}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := tc.cursor.encode()
+			if _, err := base64.RawURLEncoding.DecodeString(s); err != nil {
+				t.Fatalf("cursor %q is not url-safe: %v", s, err)
+			}
+
+			got, err := decodeTaskCursor(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != tc.cursor {
+				t.Errorf("got %+v, want %+v", *got, tc.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeTaskCursor_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"not base64!",
+		base64.RawURLEncoding.EncodeToString([]byte("not json")),
+		base64.RawURLEncoding.EncodeToString([]byte(`{"id":"seven"}`)),
+		base64.StdEncoding.EncodeToString([]byte(`{"s":"created_at","id":1}`)),
+	} {
+		if _, err := decodeTaskCursor(s); err != store.ErrInvalidCursor {
+			t.Errorf("%q: got %v, want %v", s, err, store.ErrInvalidCursor)
+		}
+	}
+}
+
+func TestTaskSorts_Value(t *testing.T) {
+	due := time.Date(2026, 3, 1, 9, 0, 0, 500, time.UTC)
+	task := &model.Task{
+		Priority:  model.HightPriority,
+		CreatedAt: due,
+	}
+
+	testCases := []struct {
+		sort string
+		task *model.Task
+		want string
+	}{
+		{sort: store.SortCreatedAt, task: task, want: "2026-03-01T09:00:00.0000005Z"},
+		{sort: store.SortDueDate, task: task, want: "infinity"},
+		{sort: store.SortDueDate, task: &model.Task{DueDate: &due}, want: "2026-03-01T09:00:00.0000005Z"},
+		{sort: store.SortPriority, task: task, want: "high"},
+	}
+
+	for _, tc := range testCases {
+		if got := taskSorts[tc.sort].value(tc.task); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.sort, got, tc.want)
+		}
+	}
+}
@@ -1,68 +1,475 @@
 package sqlstore
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/qeery8/rest/internal/model"
 	"github.com/qeery8/rest/internal/store"
 )
 
+var taskColumns = `t.id, t.name, t.content, t.status,
+	COALESCE((SELECT ws.category FROM workflow_statuses ws WHERE ws.team_id = t.team_id AND ws.key = t.status), ''),
+	t.priority, t.due_date, t.assignee_id,
+	ARRAY(SELECT ta.user_id FROM task_assignees ta WHERE ta.task_id = t.id
+		ORDER BY ta.user_id IS NOT DISTINCT FROM t.assignee_id DESC, ta.assigned_at, ta.user_id),
+	ARRAY(SELECT tw.user_id FROM task_watchers tw WHERE tw.task_id = t.id ORDER BY tw.user_id),
+	t.team_id, t.parent_id, t.sprint_id, t.rank,
+	t.story_points, t.estimate_minutes, (SELECT COALESCE(SUM(w.minutes), 0) FROM task_worklogs w WHERE w.task_id = t.id),
+	t.recurrence, t.next_occurrence_id,
+	t.created_at, t.updated_at,
+	(SELECT COUNT(*) FROM tasks st WHERE st.parent_id = t.id AND st.archived_at IS NULL),
+	(SELECT COUNT(*) FROM tasks st WHERE st.parent_id = t.id AND st.archived_at IS NULL AND ` + inCategory("st", model.CategoryDone) + `),
+	(SELECT COUNT(*) FROM task_checklist_items ci WHERE ci.task_id = t.id),
+	(SELECT COUNT(*) FROM task_checklist_items ci WHERE ci.task_id = t.id AND ci.done),
+	EXISTS (
+		SELECT 1 FROM task_dependencies d
+		JOIN tasks b ON b.id = d.blocker_id
+		WHERE d.blocked_id = t.id AND b.archived_at IS NULL AND NOT ` + inCategory("b", model.CategoryDone) + `
+	),
+	COALESCE((
+		SELECT json_agg(json_build_object('id', l.id, 'name', l.name, 'color', l.color) ORDER BY l.name)
+		FROM task_labels tl
+		JOIN labels l ON l.id = tl.label_id
+		WHERE tl.task_id = t.id
+	), '[]'),
+	COALESCE((
+		SELECT json_object_agg(cf.key, CASE WHEN cf.type = 'number' THEN to_json(v.value_number) ELSE to_json(v.value) END)
+		FROM task_field_values v
+		JOIN custom_fields cf ON cf.id = v.field_id
+		WHERE v.task_id = t.id
+	), '{}')`
+
 type TaskRepository struct {
 	store *Store
 }
 
-func (r *TaskRepository) Create(t *model.Task) error {
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTask reads a row of taskColumns; extra receives the columns a query
+// selects after them.
+func scanTask(row rowScanner, extra ...interface{}) (*model.Task, error) {
+	t := &model.Task{}
+	var (
+		labels, fields      []byte
+		assignees, watchers pq.Int64Array
+	)
+	if err := row.Scan(append([]interface{}{
+		&t.ID,
+		&t.Name,
+		&t.Content,
+		&t.Status,
+		&t.Category,
+		&t.Priority,
+		&t.DueDate,
+		&t.AssigneeID,
+		&assignees,
+		&watchers,
+		&t.TeamID,
+		&t.ParentID,
+		&t.SprintID,
+		&t.Rank,
+		&t.StoryPoints,
+		&t.EstimateMinutes,
+		&t.SpentMinutes,
+		&t.Recurrence,
+		&t.NextOccurrenceID,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+		&t.Progress.SubtasksTotal,
+		&t.Progress.SubtasksDone,
+		&t.Progress.ChecklistTotal,
+		&t.Progress.ChecklistDone,
+		&t.Blocked,
+		&labels,
+		&fields,
+	}, extra...)...); err != nil {
+		return nil, err
+	}
+
+	t.AssigneeIDs = intSlice(assignees)
+	t.WatcherIDs = intSlice(watchers)
+
+	if err := json.Unmarshal(labels, &t.Labels); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(fields, &t.Fields); err != nil {
+		return nil, err
+	}
+
+	t.ComputeProgress()
+
+	return t, nil
+}
+
+func (r *TaskRepository) Create(t *model.Task, by *int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertTask(tx, t); err != nil {
+		return err
+	}
+	if err := recordActivity(tx, model.NewActivity(t, by, model.ActivityCreated)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertTask validates and stores a new task, filling in the defaults and
+// recording its initial status.
+func insertTask(q querier, t *model.Task) error {
 	if err := t.Validate(); err != nil {
 		return err
 	}
 
+	// ранг в конце колонки считается под той же блокировкой, что и Move;
+	// берем ее до блокировки родителя
+	if err := lockBoard(q, t.TeamID); err != nil {
+		return err
+	}
+
+	wf, err := loadWorkflow(q, t.TeamID)
+	if err != nil {
+		return err
+	}
+
+	if t.Status == "" {
+		t.Status = wf.Initial()
+	}
+	status := wf.Status(t.Status)
+	if status == nil {
+		return model.ErrUnknownStatus
+	}
+	t.Category = status.Category
+
+	if t.Priority == "" {
+		t.Priority = model.LowPriority
+	}
+
+	if t.ParentID != nil {
+		if err := checkParent(q, t.TeamID, 0, t.Category == model.CategoryDone, *t.ParentID); err != nil {
+			return err
+		}
+	}
+
 	t.CreatedAt = time.Now()
 	t.UpdatedAt = time.Now()
 
-	return r.store.db.QueryRow(
-		`INSERT INTO tasks (name, content, status, priority, due_date, assignee_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id`,
-		t.Name, t.Content, t.Status, t.Priority, t.DueDate, t.AssigneeID, t.CreatedAt, t.UpdatedAt,
-	).Scan(&t.ID)
+	if err := q.QueryRow(
+		`INSERT INTO tasks (name, content, status, priority, due_date, assignee_id, team_id, parent_id, story_points, estimate_minutes, recurrence, created_at, updated_at, rank)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, `+nextRank("$7", "$3")+`)
+		RETURNING id, rank`,
+		t.Name, t.Content, t.Status, t.Priority, t.DueDate, t.AssigneeID, t.TeamID, t.ParentID, t.StoryPoints, t.EstimateMinutes, t.Recurrence, t.CreatedAt, t.UpdatedAt,
+	).Scan(&t.ID, &t.Rank); err != nil {
+		return err
+	}
+
+	if t.AssigneeID != nil {
+		if err := checkTaskMember(q, t.ID, *t.AssigneeID); err != nil {
+			return err
+		}
+	}
+
+	if err := replaceAssignee(q, t.ID, nil, t.AssigneeID); err != nil {
+		return err
+	}
+
+	return recordTransition(q, t, nil)
 }
 
-func (r *TaskRepository) Update(t *model.Task) error {
+// Update applies the patch to the task as it is inside the transaction, so
+// concurrent updates of different fields do not undo each other.
+func (r *TaskRepository) Update(id int, p *model.TaskPatch, by *int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockTask(tx, id); err != nil {
+		return err
+	}
+
+	if err := trackTask(tx, id, by, func(t *model.Task) error {
+		p.Apply(t)
+		return updateTask(tx, t)
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// lockTask takes the board lock of the task's team and then the task row,
+// in the order Move takes them.
+func lockTask(q querier, taskID int) error {
+	var teamID int
+	if err := q.QueryRow(
+		`SELECT team_id FROM tasks WHERE id = $1 AND archived_at IS NULL`,
+		taskID,
+	).Scan(&teamID); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrRecordNotFound
+		}
+		return err
+	}
+
+	if err := lockBoard(q, teamID); err != nil {
+		return err
+	}
+
+	_, err := currentStatus(q, taskID)
+	return err
+}
+
+// updateTask saves t, checking a status change against the workflow and
+// recording it.
+func updateTask(q querier, t *model.Task) error {
 	if err := t.Validate(); err != nil {
 		return err
 	}
 
+	// смена статуса ставит задачу в конец колонки, поэтому ждем перемещений
+	// на доске заранее, пока строка задачи еще не заблокирована
+	if err := lockBoard(q, t.TeamID); err != nil {
+		return err
+	}
+
+	current, err := currentStatus(q, t.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkStatusChange(q, t, current); err != nil {
+		return err
+	}
+
+	var assignee *int
+	if err := q.QueryRow(
+		`SELECT assignee_id FROM tasks WHERE id = $1`,
+		t.ID,
+	).Scan(&assignee); err != nil {
+		return err
+	}
+
+	if t.AssigneeID != nil && (assignee == nil || *assignee != *t.AssigneeID) {
+		if err := checkTaskMember(q, t.ID, *t.AssigneeID); err != nil {
+			return err
+		}
+	}
+
 	t.UpdatedAt = time.Now()
 
-	_, err := r.store.db.Exec(
-		`UPDATE tasks SET 
-		name = $1, content = $2, status = $3, priority = $4, due_date = $5, assignee_id = $6, updated_at = $7
-		WHERE id = $8`,
-		t.Name, t.Content, t.Status, t.Priority, t.DueDate, t.AssigneeID, t.UpdatedAt, t.ID,
-	)
+	// задача, сменившая статус, встает в конец новой колонки доски
+	if _, err := q.Exec(
+		`UPDATE tasks SET
+		name = $1, content = $2, status = $3, priority = $4, due_date = $5, assignee_id = $6, updated_at = $7,
+		story_points = $8, estimate_minutes = $9, recurrence = $10,
+		rank = CASE WHEN status = $3 THEN rank ELSE `+nextRank("tasks.team_id", "$3")+` END
+		WHERE id = $11`,
+		t.Name, t.Content, t.Status, t.Priority, t.DueDate, t.AssigneeID, t.UpdatedAt, t.StoryPoints, t.EstimateMinutes, t.Recurrence, t.ID,
+	); err != nil {
+		return err
+	}
+
+	if err := replaceAssignee(q, t.ID, assignee, t.AssigneeID); err != nil {
+		return err
+	}
+
+	if current == t.Status {
+		return nil
+	}
+	if err := recordTransition(q, t, &current); err != nil {
+		return err
+	}
+	return spawnOccurrence(q, t)
+}
+
+// spawnOccurrence creates the next occurrence of a recurring task that has
+// just been finished. The new task keeps the assignees, estimates and
+// labels, and is due one period after the finished one (or after now when
+// it had no due date). Each task spawns at most one occurrence.
+func spawnOccurrence(q querier, t *model.Task) error {
+	if t.Category != model.CategoryDone || t.Recurrence == nil {
+		return nil
+	}
+
+	var spawned bool
+	if err := q.QueryRow(
+		`SELECT next_occurrence_id IS NOT NULL FROM tasks WHERE id = $1`,
+		t.ID,
+	).Scan(&spawned); err != nil {
+		return err
+	}
+	if spawned {
+		return nil
+	}
+
+	rule, err := model.ParseRecurrence(*t.Recurrence)
+	if err != nil {
+		return err
+	}
+
+	from := time.Now()
+	if t.DueDate != nil {
+		from = *t.DueDate
+		rule.Anchor(from)
+	}
+	due, ok := rule.Next(from)
+	if !ok {
+		return nil
+	}
+	recurrence := rule.String()
 
+	// тот, кто уже ушел из команды, следующую задачу не получает
+	assignee := t.AssigneeID
+	if assignee != nil {
+		switch err := checkTaskMember(q, t.ID, *assignee); err {
+		case nil:
+		case store.ErrUserNotInTeam:
+			assignee = nil
+		default:
+			return err
+		}
+	}
+
+	next := &model.Task{
+		Name:            t.Name,
+		Content:         t.Content,
+		Priority:        t.Priority,
+		DueDate:         &due,
+		AssigneeID:      assignee,
+		TeamID:          t.TeamID,
+		ParentID:        t.ParentID,
+		StoryPoints:     t.StoryPoints,
+		EstimateMinutes: t.EstimateMinutes,
+		Recurrence:      &recurrence,
+	}
+	if err := insertTask(q, next); err != nil {
+		return err
+	}
+
+	if _, err := q.Exec(
+		`INSERT INTO task_labels (task_id, label_id)
+		SELECT $1, label_id FROM task_labels WHERE task_id = $2`,
+		next.ID, t.ID,
+	); err != nil {
+		return err
+	}
+
+	if _, err := q.Exec(
+		`INSERT INTO task_assignees (task_id, user_id, assigned_at)
+		SELECT $1, ta.user_id, ta.assigned_at FROM task_assignees ta
+		JOIN team_members tm ON tm.user_id = ta.user_id AND tm.team_id = $3
+		WHERE ta.task_id = $2
+		ON CONFLICT DO NOTHING`,
+		next.ID, t.ID, t.TeamID,
+	); err != nil {
+		return err
+	}
+	if err := promoteAssignee(q, next.ID); err != nil {
+		return err
+	}
+
+	if _, err := q.Exec(
+		`UPDATE tasks SET next_occurrence_id = $1 WHERE id = $2`,
+		next.ID, t.ID,
+	); err != nil {
+		return err
+	}
+
+	t.NextOccurrenceID = &next.ID
+	return nil
+}
+
+// recordTransition stores the move of t into its current status; from is
+// nil for a new task.
+func recordTransition(q querier, t *model.Task, from *model.TaskStatus) error {
+	_, err := q.Exec(
+		`INSERT INTO task_status_transitions (task_id, team_id, from_status, to_status, to_category, changed_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())`,
+		t.ID, t.TeamID, from, t.Status, t.Category,
+	)
 	return err
 }
 
+// checkStatusChange validates moving t from the current status to t.Status
+// against the team workflow, open subtasks and unfinished blockers, and
+// fills t.Category.
+func checkStatusChange(q querier, t *model.Task, current model.TaskStatus) error {
+	wf, err := loadWorkflow(q, t.TeamID)
+	if err != nil {
+		return err
+	}
+
+	target := wf.Status(t.Status)
+	if target == nil {
+		return model.ErrUnknownStatus
+	}
+	// задачу в статусе, которого уже нет в процессе, можно перевести куда угодно
+	from := wf.Status(current)
+	if from != nil && !wf.CanMove(current, t.Status) {
+		return model.ErrTransitionNotAllowed
+	}
+	t.Category = target.Category
+
+	if target.Category == model.CategoryDone {
+		var open int
+		if err := q.QueryRow(
+			`SELECT COUNT(*) FROM tasks st
+			WHERE st.parent_id = $1 AND st.archived_at IS NULL AND NOT `+inCategory("st", model.CategoryDone),
+			t.ID,
+		).Scan(&open); err != nil {
+			return err
+		}
+		if open > 0 {
+			return model.ErrOpenSubtasks
+		}
+	}
+
+	// в работу можно взять только задачу без незакрытых блокеров,
+	// уже начатую задачу при этом можно спокойно редактировать
+	if target.Category == model.CategoryInProgress && (from == nil || from.Category != model.CategoryInProgress) {
+		var blocked bool
+		if err := q.QueryRow(
+			`SELECT EXISTS (
+				SELECT 1 FROM task_dependencies d
+				JOIN tasks b ON b.id = d.blocker_id
+				WHERE d.blocked_id = $1 AND b.archived_at IS NULL AND NOT `+inCategory("b", model.CategoryDone)+`
+			)`,
+			t.ID,
+		).Scan(&blocked); err != nil {
+			return err
+		}
+		if blocked {
+			return model.ErrTaskBlocked
+		}
+	}
+	return nil
+}
+
 func (r *TaskRepository) GetByID(id int) (*model.Task, error) {
-	t := &model.Task{}
-	err := r.store.db.QueryRow(
-		`SELECT id, name, content, status, priority, due_date, assignee_id, created_at, updated_at
-		FROM tasks 
-		WHERE id = $1`, id,
-	).Scan(
-		&t.ID,
-		&t.Name,
-		&t.Content,
-		&t.Status,
-		&t.Priority,
-		&t.DueDate,
-		&t.AssigneeID,
-		&t.CreatedAt,
-		&t.UpdatedAt,
-	)
+	return findTask(r.store.db, id)
+}
 
+func findTask(q querier, id int) (*model.Task, error) {
+	t, err := scanTask(q.QueryRow(
+		`SELECT `+taskColumns+`
+		FROM tasks t
+		WHERE t.id = $1 AND t.archived_at IS NULL`, id,
+	))
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
 		return nil, err
 	}
 
@@ -70,59 +477,286 @@ func (r *TaskRepository) GetByID(id int) (*model.Task, error) {
 }
 
 func (r *TaskRepository) List() ([]*model.Task, error) {
-	rows, err := r.store.db.Query(
-		`SELECT id, name, content, status, priority, due_date, assignee_id, created_at, updated_at
-		FROM tasks`,
+	return r.queryTasks(
+		`SELECT ` + taskColumns + `
+		FROM tasks t
+		WHERE t.archived_at IS NULL`,
+	)
+}
+
+// Involved returns the unfinished tasks the user is assigned to or watches
+// in the teams they still belong to, soonest due first.
+func (r *TaskRepository) Involved(userID int) ([]*model.Task, error) {
+	return r.queryTasks(
+		`SELECT `+taskColumns+`
+		FROM tasks t
+		WHERE t.team_id IN (SELECT team_id FROM team_members WHERE user_id = $1)
+		AND t.archived_at IS NULL
+		AND (
+			EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = t.id AND ta.user_id = $1)
+			OR EXISTS (SELECT 1 FROM task_watchers tw WHERE tw.task_id = t.id AND tw.user_id = $1)
+		)
+		AND NOT `+inCategory("t", model.CategoryDone)+`
+		ORDER BY t.due_date NULLS LAST, t.priority DESC, t.id`,
+		userID,
+	)
+}
+
+func (r *TaskRepository) ListByTeam(teamID int) ([]*model.Task, error) {
+	return r.queryTasks(
+		`SELECT `+taskColumns+`
+		FROM tasks t
+		WHERE t.team_id = $1 AND t.archived_at IS NULL
+		ORDER BY t.created_at`,
+		teamID,
+	)
+}
+
+func (r *TaskRepository) Children(parentID int) ([]*model.Task, error) {
+	return r.queryTasks(
+		`SELECT `+taskColumns+`
+		FROM tasks t
+		WHERE t.parent_id = $1 AND t.archived_at IS NULL
+		ORDER BY t.created_at, t.id`,
+		parentID,
 	)
+}
 
+// SetParent moves a task under another one, or to the top level when
+// parentID is nil.
+func (r *TaskRepository) SetParent(taskID int, parentID *int, by *int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := trackTask(tx, taskID, by, func(*model.Task) error {
+		return setParent(tx, taskID, parentID)
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func setParent(q querier, taskID int, parentID *int) error {
+	var (
+		teamID int
+		done   bool
+	)
+	if err := q.QueryRow(
+		`SELECT t.team_id, `+inCategory("t", model.CategoryDone)+`
+		FROM tasks t
+		WHERE t.id = $1 AND t.archived_at IS NULL
+		FOR UPDATE OF t`,
+		taskID,
+	).Scan(&teamID, &done); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrRecordNotFound
+		}
+		return err
+	}
+
+	if parentID != nil {
+		if err := checkParent(q, teamID, taskID, done, *parentID); err != nil {
+			return err
+		}
+	}
+
+	_, err := q.Exec(
+		`UPDATE tasks SET parent_id = $1, updated_at = NOW()
+		WHERE id = $2`,
+		parentID, taskID,
+	)
+	return err
+}
+
+// checkParent makes sure parentID may become the parent of taskID: same
+// team, no cycle, the tree stays within model.MaxTaskDepth and an open task
+// does not go under a finished one. taskID is 0 for a task that does not
+// exist yet, done tells whether it is finished. The parent stays locked
+// until the end of the transaction, so it cannot be finished meanwhile.
+func checkParent(q querier, teamID int, taskID int, done bool, parentID int) error {
+	var (
+		parentTeamID int
+		parentDone   bool
+	)
+	if err := q.QueryRow(
+		`SELECT p.team_id, `+inCategory("p", model.CategoryDone)+`
+		FROM tasks p
+		WHERE p.id = $1 AND p.archived_at IS NULL
+		FOR UPDATE OF p`,
+		parentID,
+	).Scan(&parentTeamID, &parentDone); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrParentTaskNotFound
+		}
+		return err
+	}
+
+	if parentTeamID != teamID {
+		return model.ErrParentOtherTeam
+	}
+	if parentDone && !done {
+		return model.ErrParentDone
+	}
+
+	var (
+		parentDepth int
+		cycle       bool
+	)
+	if err := q.QueryRow(
+		`WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, team_id, 1 AS depth
+			FROM tasks WHERE id = $1
+			UNION ALL
+			SELECT t.id, t.parent_id, t.team_id, a.depth + 1
+			FROM tasks t
+			JOIN ancestors a ON t.id = a.parent_id
+			WHERE a.depth <= $3
+		)
+		SELECT
+			COALESCE(MAX(depth), 0),
+			COALESCE(BOOL_OR(id = $2), FALSE)
+		FROM ancestors`,
+		parentID, taskID, model.MaxTaskDepth,
+	).Scan(&parentDepth, &cycle); err != nil {
+		return err
+	}
+
+	if cycle {
+		return model.ErrSubtaskCycle
+	}
+
+	height := 0
+	if taskID != 0 {
+		if err := q.QueryRow(
+			`WITH RECURSIVE subtree AS (
+				SELECT id, 0 AS height
+				FROM tasks WHERE id = $1
+				UNION ALL
+				SELECT t.id, s.height + 1
+				FROM tasks t
+				JOIN subtree s ON t.parent_id = s.id
+				WHERE s.height < $2
+			)
+			SELECT MAX(height) FROM subtree`,
+			taskID, model.MaxTaskDepth,
+		).Scan(&height); err != nil {
+			return err
+		}
+	}
+
+	if parentDepth+1+height > model.MaxTaskDepth {
+		return model.ErrSubtaskTooDeep
+	}
+	return nil
+}
+
+func (r *TaskRepository) queryTasks(query string, args ...interface{}) ([]*model.Task, error) {
+	return selectTasks(r.store.db, query, args...)
+}
+
+func selectTasks(q querier, query string, args ...interface{}) ([]*model.Task, error) {
+	rows, err := q.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 
+	defer rows.Close()
+
 	var tasks []*model.Task
 
 	for rows.Next() {
-		t := &model.Task{}
-		if err := rows.Scan(
-			&t.ID,
-			&t.Name,
-			&t.Content,
-			&t.Status,
-			&t.Priority,
-			&t.DueDate,
-			&t.AssigneeID,
-			&t.CreatedAt,
-			&t.UpdatedAt,
-		); err != nil {
+		t, err := scanTask(rows)
+		if err != nil {
 			return nil, err
 		}
 		tasks = append(tasks, t)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 	return tasks, nil
 }
 
-func (r *TaskRepository) Delete(id int) error {
-	_, err := r.store.db.Exec(
+func (r *TaskRepository) Delete(id int, by *int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := deleteTask(tx, id, by); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// deleteTask removes the task for good; only the history entry saying so
+// remains.
+func deleteTask(q querier, taskID int, by *int) error {
+	t := &model.Task{ID: taskID}
+	if err := q.QueryRow(
 		`DELETE FROM tasks
-		WHERE id = $1`, id,
-	)
-	return err
+		WHERE id = $1
+		RETURNING team_id`,
+		taskID,
+	).Scan(&t.TeamID); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrRecordNotFound
+		}
+		return err
+	}
+
+	return recordActivity(q, model.NewActivity(t, by, model.ActivityDeleted))
 }
 
-func (r *TaskRepository) AssigneeUser(userID int, teamID int) error {
+// AssigneeUser makes the user the task's main assignee, replacing the
+// previous one; other assignees stay.
+func (r *TaskRepository) AssigneeUser(userID int, taskID int, by *int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := trackTask(tx, taskID, by, func(*model.Task) error {
+		return assignUser(tx, userID, taskID)
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func assignUser(q querier, userID int, taskID int) error {
+	var assignee *int
+	if err := q.QueryRow(
+		`SELECT assignee_id FROM tasks WHERE id = $1 AND archived_at IS NULL FOR UPDATE`,
+		taskID,
+	).Scan(&assignee); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrRecordNotFound
+		}
+		return err
+	}
+
 	query := `
-		UPDATE tasks 
-		SET assignee_id = $1 
-		WHERE id = $2 
+		UPDATE tasks
+		SET assignee_id = $1, updated_at = NOW()
+		WHERE id = $2
 		AND EXISTS (
-			SELECT 1 
+			SELECT 1
 			FROM team_members
 			WHERE team_members.user_id = $1
 			AND team_members.team_id = tasks.team_id
 		)
 	`
-	result, err := r.store.db.Exec(query, userID, teamID)
+	result, err := q.Exec(query, userID, taskID)
 	if err != nil {
 		return err
 	}
@@ -135,10 +769,199 @@ func (r *TaskRepository) AssigneeUser(userID int, teamID int) error {
 	if affected == 0 {
 		return store.ErrUserNotInTeam
 	}
-	return nil
+
+	return replaceAssignee(q, taskID, assignee, &userID)
+}
+
+// AddAssignee adds a team member to the task's assignees. The first one
+// also becomes the main assignee. Adding someone twice is a no-op.
+func (r *TaskRepository) AddAssignee(taskID int, userID int, by *int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := trackTask(tx, taskID, by, func(*model.Task) error {
+		return addAssignee(tx, taskID, userID)
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func addAssignee(q querier, taskID int, userID int) error {
+	if err := checkTaskMember(q, taskID, userID); err != nil {
+		return err
+	}
+
+	if _, err := q.Exec(
+		`INSERT INTO task_assignees (task_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`,
+		taskID, userID,
+	); err != nil {
+		return err
+	}
+
+	_, err := q.Exec(
+		`UPDATE tasks SET assignee_id = COALESCE(assignee_id, $2), updated_at = NOW()
+		WHERE id = $1`,
+		taskID, userID,
+	)
+	return err
+}
+
+// RemoveAssignee takes the user off the task. When it was the main
+// assignee, the longest assigned of the others takes its place.
+func (r *TaskRepository) RemoveAssignee(taskID int, userID int, by *int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := trackTask(tx, taskID, by, func(*model.Task) error {
+		return removeAssignee(tx, taskID, userID)
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func removeAssignee(q querier, taskID int, userID int) error {
+	result, err := q.Exec(
+		`DELETE FROM task_assignees
+		WHERE task_id = $1 AND user_id = $2`,
+		taskID, userID,
+	)
+	if err != nil {
+		return err
+	}
+	if err := expectAffected(result, store.ErrRecordNotFound); err != nil {
+		return err
+	}
+
+	return promoteAssignee(q, taskID)
 }
 
-func (r *TaskRepository) DueDate() error {
+func (r *TaskRepository) Watch(taskID int, userID int) error {
+	if err := checkTaskMember(r.store.db, taskID, userID); err != nil {
+		return err
+	}
+
+	_, err := r.store.db.Exec(
+		`INSERT INTO task_watchers (task_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`,
+		taskID, userID,
+	)
+	return err
+}
+
+func (r *TaskRepository) Unwatch(taskID int, userID int) error {
+	result, err := r.store.db.Exec(
+		`DELETE FROM task_watchers
+		WHERE task_id = $1 AND user_id = $2`,
+		taskID, userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result, store.ErrRecordNotFound)
+}
 
+func checkTaskMember(q querier, taskID int, userID int) error {
+	var member bool
+	if err := q.QueryRow(
+		`SELECT EXISTS (
+			SELECT 1 FROM tasks t
+			JOIN team_members tm ON tm.team_id = t.team_id
+			WHERE t.id = $1 AND tm.user_id = $2
+		)`,
+		taskID, userID,
+	).Scan(&member); err != nil {
+		return err
+	}
+	if !member {
+		return store.ErrUserNotInTeam
+	}
 	return nil
 }
+
+// replaceAssignee mirrors a change of tasks.assignee_id into the assignee
+// list: the old main assignee leaves it and the new one joins. Clearing it
+// unassigns the task altogether.
+func replaceAssignee(q querier, taskID int, old *int, new *int) error {
+	if old != nil && new != nil && *old == *new || old == nil && new == nil {
+		return nil
+	}
+
+	if old != nil {
+		if _, err := q.Exec(
+			`DELETE FROM task_assignees
+			WHERE task_id = $1 AND user_id = $2`,
+			taskID, *old,
+		); err != nil {
+			return err
+		}
+	}
+
+	if new == nil {
+		_, err := q.Exec(
+			`DELETE FROM task_assignees
+			WHERE task_id = $1`,
+			taskID,
+		)
+		return err
+	}
+
+	_, err := q.Exec(
+		`INSERT INTO task_assignees (task_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`,
+		taskID, *new,
+	)
+	return err
+}
+
+// promoteAssignee picks a new main assignee when the current one is no
+// longer on the list.
+func promoteAssignee(q querier, taskID int) error {
+	_, err := q.Exec(
+		`UPDATE tasks SET assignee_id = (
+			SELECT ta.user_id FROM task_assignees ta
+			WHERE ta.task_id = tasks.id
+			ORDER BY ta.assigned_at, ta.user_id
+			LIMIT 1
+		), updated_at = NOW()
+		WHERE id = $1 AND (assignee_id IS NULL OR NOT EXISTS (
+			SELECT 1 FROM task_assignees ta
+			WHERE ta.task_id = tasks.id AND ta.user_id = tasks.assignee_id
+		))`,
+		taskID,
+	)
+	return err
+}
+
+func intSlice(ids pq.Int64Array) []int {
+	out := make([]int, len(ids))
+	for i, id := range ids {
+		out[i] = int(id)
+	}
+	return out
+}
+
+func (r *TaskRepository) DueDate(from time.Time, to time.Time) ([]*model.Task, error) {
+	return r.queryTasks(
+		`SELECT `+taskColumns+`
+		FROM tasks t
+		WHERE t.due_date >= $1 AND t.due_date < $2 AND t.archived_at IS NULL
+		AND NOT `+inCategory("t", model.CategoryDone)+`
+		ORDER BY t.due_date`,
+		from, to,
+	)
+}
@@ -0,0 +1,181 @@
+package sqlstore_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+	"github.com/qeery8/rest/internal/store/sqlstore"
+)
+
+// newTeam creates an owner and a team with the default workflow.
+func newTeam(t *testing.T, s *sqlstore.Store) (*model.User, *model.Team) {
+	t.Helper()
+
+	u := &model.User{Email: "owner@example.org", EncryptedPassword: "-"}
+	if err := s.User().Create(u); err != nil {
+		t.Fatal(err)
+	}
+
+	team := &model.Team{Name: "team", OwnerID: u.ID}
+	if err := s.Team().Create(team); err != nil {
+		t.Fatal(err)
+	}
+
+	return u, team
+}
+
+func newTask(t *testing.T, s *sqlstore.Store, team *model.Team, name string) *model.Task {
+	t.Helper()
+
+	task := &model.Task{Name: name, TeamID: team.ID}
+	if err := s.Task().Create(task, &team.OwnerID); err != nil {
+		t.Fatal(err)
+	}
+	return task
+}
+
+func TestDependencyRepository_Add(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("users", "teams")
+
+	s := sqlstore.New(db)
+	_, team := newTeam(t, s)
+	a := newTask(t, s, team, "task a")
+	b := newTask(t, s, team, "task b")
+	c := newTask(t, s, team, "task c")
+
+	if err := s.Dependency().Add(a.ID, b.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Dependency().Add(b.ID, c.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name    string
+		blocker int
+		blocked int
+		want    error
+	}{
+		{name: "self", blocker: a.ID, blocked: a.ID, want: model.ErrDependencySelf},
+		{name: "direct cycle", blocker: b.ID, blocked: a.ID, want: model.ErrDependencyCycle},
+		{name: "transitive cycle", blocker: c.ID, blocked: a.ID, want: model.ErrDependencyCycle},
+		{name: "already linked", blocker: a.ID, blocked: b.ID, want: store.ErrAlreadyLinked},
+		{name: "shortcut", blocker: a.ID, blocked: c.ID, want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := s.Dependency().Add(tc.blocker, tc.blocked, nil); err != tc.want {
+				t.Errorf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestTaskRepository_MoveRebalance(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("users", "teams")
+
+	s := sqlstore.New(db)
+	_, team := newTeam(t, s)
+	first := newTask(t, s, team, "first")
+	last := newTask(t, s, team, "last")
+	x := newTask(t, s, team, "task x")
+	y := newTask(t, s, team, "task y")
+
+	if err := s.Task().Move(x.ID, model.StatusToDo, &last.ID, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// каждый перенос сразу за first делит промежуток пополам,
+	// так что рано или поздно колонку приходится перенумеровать
+	moved := []*model.Task{x, y}
+	for i := 0; i < 40; i++ {
+		task := moved[i%2]
+		if err := s.Task().Move(task.ID, model.StatusToDo, nil, &first.ID, nil); err != nil {
+			t.Fatalf("move %d: %v", i, err)
+		}
+	}
+
+	board, err := s.Task().Board(team.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var column *model.BoardColumn
+	for _, c := range board.Columns {
+		if c.Status == model.StatusToDo {
+			column = c
+		}
+	}
+
+	// последним переносили y
+	want := []int{first.ID, y.ID, x.ID, last.ID}
+	var got []int
+	for i, task := range column.Tasks {
+		got = append(got, task.ID)
+		if i > 0 && task.Rank <= column.Tasks[i-1].Rank {
+			t.Errorf("rank of %d is %d, not above %d", task.ID, task.Rank, column.Tasks[i-1].Rank)
+		}
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("column order: got %v, want %v", got, want)
+	}
+}
+
+func TestTaskRepository_BulkAllOrNothing(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("users", "teams")
+
+	s := sqlstore.New(db)
+	_, team := newTeam(t, s)
+	free := newTask(t, s, team, "free task")
+	parent := newTask(t, s, team, "parent task")
+
+	sub := &model.Task{Name: "open subtask", TeamID: team.ID, ParentID: &parent.ID}
+	if err := s.Task().Create(sub, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// родителя с открытой подзадачей закрыть нельзя, поэтому весь пакет откатывается
+	op := &model.BulkOperation{Action: model.BulkSetStatus, Status: model.StatusDone}
+	results, err := s.Task().Bulk(op, []int{free.ID, parent.ID}, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if results[0].OK || results[0].Error != model.ErrBulkRolledBack.Error() {
+		t.Errorf("free task: got %+v, want rolled back", results[0])
+	}
+	if results[1].OK || results[1].Error != model.ErrOpenSubtasks.Error() {
+		t.Errorf("parent task: got %+v, want %q", results[1], model.ErrOpenSubtasks)
+	}
+
+	got, err := s.Task().GetByID(free.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != model.StatusToDo {
+		t.Errorf("free task status after rollback: got %q, want %q", got.Status, model.StatusToDo)
+	}
+
+	// в режиме best_effort проходит все, что может пройти
+	results, err = s.Task().Bulk(op, []int{free.ID, parent.ID}, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].OK || results[1].OK {
+		t.Errorf("best effort: got %+v, %+v", results[0], results[1])
+	}
+
+	got, err = s.Task().GetByID(free.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != model.StatusDone {
+		t.Errorf("free task status: got %q, want %q", got.Status, model.StatusDone)
+	}
+}
@@ -1,9 +1,11 @@
 package sqlstore
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
 )
 
 type TeamRepository struct {
@@ -18,7 +20,13 @@ func (r *TeamRepository) Create(t *model.Team) error {
 	t.CreatedAt = time.Now()
 	t.UpdatedAt = time.Now()
 
-	if err := r.store.db.QueryRow(
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := tx.QueryRow(
 		`INSERT INTO teams (name, description, owner_id, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id `,
@@ -27,20 +35,159 @@ func (r *TeamRepository) Create(t *model.Team) error {
 		return err
 	}
 
-	r.AddMembers(t.OwnerID, t.ID)
+	if _, err := tx.Exec(
+		`INSERT INTO team_members (user_id, team_id, role, created_at)
+		VALUES ($1, $2, $3, NOW())`,
+		t.OwnerID, t.ID, model.RoleOwner,
+	); err != nil {
+		return err
+	}
+
+	if err := insertWorkflow(tx, model.DefaultWorkflow(t.ID)); err != nil {
+		return err
+	}
 
-	return nil
+	return tx.Commit()
 }
 
-func (r *TeamRepository) AddMembers(userID int, teamID int) error {
+func (r *TeamRepository) AddMembers(teamID int, userID int, role model.TeamRole) error {
+	if !role.Valid() {
+		return model.ErrInvalidRole
+	}
+
 	_, err := r.store.db.Exec(
-		`INSERT INTO team_members (user_id, team_id, created_at)
-		VALUES ($1, $2, NOW())`,
-		userID, teamID,
+		`INSERT INTO team_members (user_id, team_id, role, created_at)
+		VALUES ($1, $2, $3, NOW())`,
+		userID, teamID, role,
 	)
+	if isUniqueViolation(err) {
+		return store.ErrAlreadyInTeam
+	}
 	return err
 }
 
+func (r *TeamRepository) Members(teamID int) ([]*model.TeamMember, error) {
+	rows, err := r.store.db.Query(
+		`SELECT tm.team_id, tm.user_id, u.email, tm.role, tm.created_at
+		FROM team_members tm
+		JOIN users u ON u.id = tm.user_id
+		WHERE tm.team_id = $1
+		ORDER BY tm.created_at`,
+		teamID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var members []*model.TeamMember
+
+	for rows.Next() {
+		m := &model.TeamMember{}
+		if err := rows.Scan(
+			&m.TeamID,
+			&m.UserID,
+			&m.Email,
+			&m.Role,
+			&m.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *TeamRepository) MemberRole(teamID int, userID int) (model.TeamRole, error) {
+	var role model.TeamRole
+	if err := r.store.db.QueryRow(
+		`SELECT role FROM team_members
+		WHERE team_id = $1 AND user_id = $2`,
+		teamID, userID,
+	).Scan(&role); err != nil {
+		if err == sql.ErrNoRows {
+			return "", store.ErrUserNotInTeam
+		}
+		return "", err
+	}
+
+	return role, nil
+}
+
+func (r *TeamRepository) UpdateMemberRole(teamID int, userID int, role model.TeamRole) error {
+	if !role.Valid() || role == model.RoleOwner {
+		return model.ErrInvalidRole
+	}
+
+	result, err := r.store.db.Exec(
+		`UPDATE team_members SET role = $1
+		WHERE team_id = $2 AND user_id = $3 AND role <> 'owner'`,
+		role, teamID, userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result, store.ErrUserNotInTeam)
+}
+
+func (r *TeamRepository) TransferOwnership(teamID int, newOwnerID int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var oldOwnerID int
+	if err := tx.QueryRow(
+		`SELECT owner_id FROM teams WHERE id = $1 FOR UPDATE`,
+		teamID,
+	).Scan(&oldOwnerID); err != nil {
+		if err == sql.ErrNoRows {
+			return store.ErrRecordNotFound
+		}
+		return err
+	}
+	if oldOwnerID == newOwnerID {
+		return model.ErrTransferToOwner
+	}
+
+	result, err := tx.Exec(
+		`UPDATE team_members SET role = 'owner'
+		WHERE team_id = $1 AND user_id = $2`,
+		teamID, newOwnerID,
+	)
+	if err != nil {
+		return err
+	}
+	if err := expectAffected(result, store.ErrUserNotInTeam); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(
+		`UPDATE team_members SET role = 'admin'
+		WHERE team_id = $1 AND user_id = $2`,
+		teamID, oldOwnerID,
+	); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(
+		`UPDATE teams SET owner_id = $1, updated_at = NOW()
+		WHERE id = $2`,
+		newOwnerID, teamID,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (r *TeamRepository) Find(id int) (*model.Team, error) {
 	t := &model.Team{}
 	err := r.store.db.QueryRow(
@@ -56,6 +203,9 @@ func (r *TeamRepository) Find(id int) (*model.Team, error) {
 	)
 
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
 		return nil, err
 	}
 
@@ -100,13 +250,69 @@ func (r *TeamRepository) FindByUser(userID int) ([]*model.Team, error) {
 	return teams, nil
 }
 
-func (r *TeamRepository) RemoveMembers(userID int, teamID int) error {
-	_, err := r.store.db.Exec(
+// RemoveMembers takes the user out of the team together with their place
+// among the assignees and watchers of the team's tasks.
+func (r *TeamRepository) RemoveMembers(teamID int, userID int) error {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
 		`DELETE FROM team_members
 		WHERE user_id = $1 AND team_id = $2`,
 		userID, teamID,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	if err := expectAffected(result, store.ErrUserNotInTeam); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(
+		`DELETE FROM task_assignees ta
+		USING tasks t
+		WHERE t.id = ta.task_id AND t.team_id = $1 AND ta.user_id = $2
+		RETURNING ta.task_id`,
+		teamID, userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	var taskIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		taskIDs = append(taskIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// задачи, где он был основным исполнителем, переходят к следующему
+	for _, id := range taskIDs {
+		if err := promoteAssignee(tx, id); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(
+		`DELETE FROM task_watchers tw
+		USING tasks t
+		WHERE t.id = tw.task_id AND t.team_id = $1 AND tw.user_id = $2`,
+		teamID, userID,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *TeamRepository) Update(t *model.Team) error {
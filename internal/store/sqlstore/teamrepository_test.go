@@ -0,0 +1,55 @@
+package sqlstore_test
+
+import (
+	"testing"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+	"github.com/qeery8/rest/internal/store/sqlstore"
+)
+
+func TestTeamRepository_RemoveMembers(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("users", "teams")
+
+	s := sqlstore.New(db)
+	owner, team := newTeam(t, s)
+
+	u := &model.User{Email: "member@example.org", EncryptedPassword: "-"}
+	if err := s.User().Create(u); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Team().AddMembers(team.ID, u.ID, model.RoleMember); err != nil {
+		t.Fatal(err)
+	}
+
+	task := &model.Task{Name: "shared task", TeamID: team.ID, AssigneeID: &u.ID}
+	if err := s.Task().Create(task, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Task().AddAssignee(task.ID, owner.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Task().Watch(task.ID, u.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Team().RemoveMembers(team.ID, u.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.Task().GetByID(task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AssigneeID == nil || *got.AssigneeID != owner.ID || len(got.AssigneeIDs) != 1 {
+		t.Errorf("assignees after removal: main %v, all %v; want only %d", got.AssigneeID, got.AssigneeIDs, owner.ID)
+	}
+	if len(got.WatcherIDs) != 0 {
+		t.Errorf("watchers after removal: got %v, want none", got.WatcherIDs)
+	}
+
+	if err := s.Team().RemoveMembers(team.ID, u.ID); err != store.ErrUserNotInTeam {
+		t.Errorf("second removal: got %v, want %v", err, store.ErrUserNotInTeam)
+	}
+}
@@ -0,0 +1,318 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+const templateColumns = `t.id, t.team_id, t.name, t.name_pattern, t.content, COALESCE(t.priority::text, ''),
+	ARRAY(SELECT tl.label_id FROM task_template_labels tl WHERE tl.template_id = t.id ORDER BY tl.label_id),
+	t.checklist, t.subtasks, t.created_by, t.created_at, t.updated_at`
+
+type TemplateRepository struct {
+	store *Store
+}
+
+func scanTemplate(row rowScanner) (*model.TaskTemplate, error) {
+	t := &model.TaskTemplate{}
+	var (
+		labels    pq.Int64Array
+		checklist []byte
+		subtasks  []byte
+	)
+	if err := row.Scan(
+		&t.ID,
+		&t.TeamID,
+		&t.Name,
+		&t.NamePattern,
+		&t.Content,
+		&t.Priority,
+		&labels,
+		&checklist,
+		&subtasks,
+		&t.CreatedBy,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	t.LabelIDs = intSlice(labels)
+	if err := json.Unmarshal(checklist, &t.Checklist); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(subtasks, &t.Subtasks); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *TemplateRepository) Create(t *model.TaskTemplate) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	checklist, subtasks, err := marshalTemplate(t)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	t.CreatedAt = time.Now()
+	t.UpdatedAt = t.CreatedAt
+
+	if err := tx.QueryRow(
+		`INSERT INTO task_templates (team_id, name, name_pattern, content, priority, checklist, subtasks, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, '')::task_priority, $6, $7, $8, $9, $10)
+		RETURNING id`,
+		t.TeamID, t.Name, t.NamePattern, t.Content, t.Priority, checklist, subtasks, t.CreatedBy, t.CreatedAt, t.UpdatedAt,
+	).Scan(&t.ID); err != nil {
+		if isUniqueViolation(err) {
+			return model.ErrTemplateNameExists
+		}
+		return err
+	}
+
+	if err := setTemplateLabels(tx, t); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *TemplateRepository) Find(id int) (*model.TaskTemplate, error) {
+	t, err := scanTemplate(r.store.db.QueryRow(
+		`SELECT `+templateColumns+`
+		FROM task_templates t
+		WHERE t.id = $1`,
+		id,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *TemplateRepository) ListByTeam(teamID int) ([]*model.TaskTemplate, error) {
+	rows, err := r.store.db.Query(
+		`SELECT `+templateColumns+`
+		FROM task_templates t
+		WHERE t.team_id = $1
+		ORDER BY LOWER(t.name)`,
+		teamID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	templates := []*model.TaskTemplate{}
+
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *TemplateRepository) Update(t *model.TaskTemplate) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	checklist, subtasks, err := marshalTemplate(t)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	t.UpdatedAt = time.Now()
+
+	result, err := tx.Exec(
+		`UPDATE task_templates SET
+		name = $1, name_pattern = $2, content = $3, priority = NULLIF($4, '')::task_priority,
+		checklist = $5, subtasks = $6, updated_at = $7
+		WHERE id = $8`,
+		t.Name, t.NamePattern, t.Content, t.Priority, checklist, subtasks, t.UpdatedAt, t.ID,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return model.ErrTemplateNameExists
+		}
+		return err
+	}
+	if err := expectAffected(result, store.ErrRecordNotFound); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(
+		`DELETE FROM task_template_labels WHERE template_id = $1`,
+		t.ID,
+	); err != nil {
+		return err
+	}
+
+	if err := setTemplateLabels(tx, t); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *TemplateRepository) Delete(id int) error {
+	result, err := r.store.db.Exec(
+		`DELETE FROM task_templates
+		WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result, store.ErrRecordNotFound)
+}
+
+// Instantiate creates a task from the template, with its labels and
+// checklist and, when asked, its subtasks, all in one transaction. The
+// root task comes first in the result.
+func (r *TemplateRepository) Instantiate(t *model.TaskTemplate, in *model.TemplateInstance, by *int) ([]*model.Task, error) {
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	root := &model.Task{
+		Name:       t.TaskName(time.Now(), in.Vars),
+		Content:    t.Content,
+		Priority:   t.Priority,
+		DueDate:    in.DueDate,
+		AssigneeID: in.AssigneeID,
+		TeamID:     t.TeamID,
+	}
+	if err := insertTask(tx, root); err != nil {
+		return nil, err
+	}
+	created := []*model.Task{root}
+
+	for _, labelID := range t.LabelIDs {
+		if err := attachLabel(tx, root.ID, labelID); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, title := range t.Checklist {
+		if _, err := tx.Exec(
+			`INSERT INTO task_checklist_items (task_id, title, done, position, created_at, updated_at)
+			VALUES ($1, $2, FALSE, $3, NOW(), NOW())`,
+			root.ID, title, i+1,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	if in.WithSubtasks {
+		for _, st := range t.Subtasks {
+			sub := &model.Task{
+				Name:       st.Name,
+				Content:    st.Content,
+				Priority:   st.Priority,
+				DueDate:    in.DueDate,
+				AssigneeID: in.AssigneeID,
+				TeamID:     t.TeamID,
+				ParentID:   &root.ID,
+			}
+			if err := insertTask(tx, sub); err != nil {
+				return nil, err
+			}
+			created = append(created, sub)
+		}
+	}
+
+	entries := make([]*model.Activity, len(created))
+	for i, task := range created {
+		entries[i] = model.NewActivity(task, by, model.ActivityCreated)
+	}
+	if err := recordActivity(tx, entries...); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func marshalTemplate(t *model.TaskTemplate) ([]byte, []byte, error) {
+	checklist, err := json.Marshal(t.Checklist)
+	if err != nil {
+		return nil, nil, err
+	}
+	subtasks, err := json.Marshal(t.Subtasks)
+	if err != nil {
+		return nil, nil, err
+	}
+	return checklist, subtasks, nil
+}
+
+// setTemplateLabels links the template to its labels, all of which must
+// belong to the template's team.
+func setTemplateLabels(q querier, t *model.TaskTemplate) error {
+	if len(t.LabelIDs) == 0 {
+		return nil
+	}
+
+	seen := map[int]bool{}
+	ids := []int64{}
+	for _, id := range t.LabelIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, int64(id))
+		}
+	}
+
+	result, err := q.Exec(
+		`INSERT INTO task_template_labels (template_id, label_id)
+		SELECT $1, l.id FROM labels l
+		WHERE l.id = ANY($2) AND l.team_id = $3`,
+		t.ID, pq.Array(ids), t.TeamID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if int(affected) != len(ids) {
+		return model.ErrTemplateLabelForeign
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	_ "github.com/lib/pq"
+)
+
+// TestDB connects to a migrated test database and returns a teardown that
+// truncates the given tables. Tests are skipped when no database is set.
+func TestDB(t *testing.T, databaseURL string) (*sql.DB, func(...string)) {
+	t.Helper()
+
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Ping(); err != nil {
+		t.Fatal(err)
+	}
+
+	return db, func(tables ...string) {
+		if len(tables) > 0 {
+			if _, err := db.Exec(fmt.Sprintf("TRUNCATE %s CASCADE", strings.Join(tables, ", "))); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		db.Close()
+	}
+}
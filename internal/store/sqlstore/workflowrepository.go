@@ -0,0 +1,199 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type WorkflowRepository struct {
+	store *Store
+}
+
+// inCategory matches tasks, under the given alias, whose status belongs to
+// category in their team's workflow.
+func inCategory(alias string, category model.StatusCategory) string {
+	return fmt.Sprintf(
+		`%[1]s.status IN (SELECT ws.key FROM workflow_statuses ws WHERE ws.team_id = %[1]s.team_id AND ws.category = '%[2]s')`,
+		alias, category,
+	)
+}
+
+func (r *WorkflowRepository) Get(teamID int) (*model.Workflow, error) {
+	return loadWorkflow(r.store.db, teamID)
+}
+
+func loadWorkflow(q querier, teamID int) (*model.Workflow, error) {
+	w := &model.Workflow{
+		TeamID:      teamID,
+		Statuses:    []*model.WorkflowStatus{},
+		Transitions: []model.WorkflowTransition{},
+	}
+
+	rows, err := q.Query(
+		`SELECT key, name, category, position
+		FROM workflow_statuses
+		WHERE team_id = $1
+		ORDER BY position, key`,
+		teamID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		s := &model.WorkflowStatus{}
+		if err := rows.Scan(
+			&s.Key,
+			&s.Name,
+			&s.Category,
+			&s.Position,
+		); err != nil {
+			return nil, err
+		}
+		w.Statuses = append(w.Statuses, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(w.Statuses) == 0 {
+		return nil, store.ErrRecordNotFound
+	}
+
+	rows, err = q.Query(
+		`SELECT from_status, to_status
+		FROM workflow_transitions
+		WHERE team_id = $1
+		ORDER BY from_status, to_status`,
+		teamID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var t model.WorkflowTransition
+		if err := rows.Scan(&t.From, &t.To); err != nil {
+			return nil, err
+		}
+		w.Transitions = append(w.Transitions, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Save replaces the team workflow. Statuses that tasks are still in cannot
+// be dropped, the tasks have to be moved first.
+func (r *WorkflowRepository) Save(w *model.Workflow) error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := r.store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	keys := make([]string, len(w.Statuses))
+	for i, s := range w.Statuses {
+		keys[i] = string(s.Key)
+	}
+
+	if _, err := tx.Exec(
+		`SELECT 1 FROM workflow_statuses WHERE team_id = $1 FOR UPDATE`,
+		w.TeamID,
+	); err != nil {
+		return err
+	}
+
+	var inUse bool
+	if err := tx.QueryRow(
+		`SELECT EXISTS (
+			SELECT 1 FROM tasks
+			WHERE team_id = $1 AND archived_at IS NULL AND NOT (status = ANY($2))
+		)`,
+		w.TeamID, pq.Array(keys),
+	).Scan(&inUse); err != nil {
+		return err
+	}
+	if inUse {
+		return model.ErrWorkflowStatusInUse
+	}
+
+	if _, err := tx.Exec(
+		`DELETE FROM workflow_statuses
+		WHERE team_id = $1 AND NOT (key = ANY($2))`,
+		w.TeamID, pq.Array(keys),
+	); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(
+		`DELETE FROM workflow_transitions WHERE team_id = $1`,
+		w.TeamID,
+	); err != nil {
+		return err
+	}
+
+	if err := insertWorkflow(tx, w); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertWorkflow upserts the statuses and adds the transitions of w.
+func insertWorkflow(q querier, w *model.Workflow) error {
+	for _, s := range w.Statuses {
+		if _, err := q.Exec(
+			`INSERT INTO workflow_statuses (team_id, key, name, category, position)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (team_id, key) DO UPDATE
+			SET name = EXCLUDED.name, category = EXCLUDED.category, position = EXCLUDED.position`,
+			w.TeamID, s.Key, s.Name, s.Category, s.Position,
+		); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range w.Transitions {
+		if _, err := q.Exec(
+			`INSERT INTO workflow_transitions (team_id, from_status, to_status)
+			VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING`,
+			w.TeamID, t.From, t.To,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// currentStatus returns the status the task is in right now and locks the
+// task until the end of the transaction.
+func currentStatus(q querier, taskID int) (model.TaskStatus, error) {
+	var status model.TaskStatus
+	if err := q.QueryRow(
+		`SELECT status FROM tasks WHERE id = $1 AND archived_at IS NULL FOR UPDATE`,
+		taskID,
+	).Scan(&status); err != nil {
+		if err == sql.ErrNoRows {
+			return "", store.ErrRecordNotFound
+		}
+		return "", err
+	}
+	return status, nil
+}
@@ -0,0 +1,50 @@
+package sqlstore_test
+
+import (
+	"testing"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store/sqlstore"
+)
+
+func TestWorkflowRepository_SaveStatusInUse(t *testing.T) {
+	db, teardown := sqlstore.TestDB(t, databaseURL)
+	defer teardown("users", "teams")
+
+	s := sqlstore.New(db)
+	_, team := newTeam(t, s)
+	task := newTask(t, s, team, "task")
+
+	status := model.StatusInProgress
+	if err := s.Task().Update(task.ID, &model.TaskPatch{Status: &status}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// процесс без in_progress
+	wf := model.DefaultWorkflow(team.ID)
+	wf.Statuses = []*model.WorkflowStatus{wf.Statuses[0], wf.Statuses[2]}
+	wf.Transitions = []model.WorkflowTransition{
+		{From: model.StatusToDo, To: model.StatusDone},
+		{From: model.StatusDone, To: model.StatusToDo},
+	}
+
+	if err := s.Workflow().Save(wf); err != model.ErrWorkflowStatusInUse {
+		t.Fatalf("got %v, want %v", err, model.ErrWorkflowStatusInUse)
+	}
+
+	current, err := s.Workflow().Get(team.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Status(model.StatusInProgress) == nil {
+		t.Error("in_progress is gone after a rejected save")
+	}
+
+	// задачи в архиве статус не держат
+	if err := s.Archive().Abandon(task.ID, "stale", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Workflow().Save(wf); err != nil {
+		t.Fatalf("save after archiving: %v", err)
+	}
+}
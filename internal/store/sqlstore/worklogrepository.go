@@ -0,0 +1,151 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type WorklogRepository struct {
+	store *Store
+}
+
+func scanWorklog(row rowScanner) (*model.Worklog, error) {
+	w := &model.Worklog{}
+	if err := row.Scan(
+		&w.ID,
+		&w.TaskID,
+		&w.TaskName,
+		&w.UserID,
+		&w.Minutes,
+		&w.Note,
+		&w.WorkDate,
+		&w.CreatedAt,
+		&w.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (r *WorklogRepository) Create(w *model.Worklog) error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+
+	w.CreatedAt = time.Now()
+	w.UpdatedAt = w.CreatedAt
+
+	return r.store.db.QueryRow(
+		`INSERT INTO task_worklogs (task_id, user_id, minutes, note, work_date, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`,
+		w.TaskID, w.UserID, w.Minutes, w.Note, w.WorkDate, w.CreatedAt, w.UpdatedAt,
+	).Scan(&w.ID)
+}
+
+func (r *WorklogRepository) Find(id int) (*model.Worklog, error) {
+	w, err := scanWorklog(r.store.db.QueryRow(
+		`SELECT w.id, w.task_id, t.name, w.user_id, w.minutes, w.note, w.work_date, w.created_at, w.updated_at
+		FROM task_worklogs w
+		JOIN tasks t ON t.id = w.task_id
+		WHERE w.id = $1`,
+		id,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return w, nil
+}
+
+func (r *WorklogRepository) ListByTask(taskID int) ([]*model.Worklog, error) {
+	return r.queryWorklogs(
+		`SELECT w.id, w.task_id, t.name, w.user_id, w.minutes, w.note, w.work_date, w.created_at, w.updated_at
+		FROM task_worklogs w
+		JOIN tasks t ON t.id = w.task_id
+		WHERE w.task_id = $1
+		ORDER BY w.work_date, w.id`,
+		taskID,
+	)
+}
+
+// Timesheet lists the entries a user logged between from and to, both
+// dates included. With teamID only that team's tasks are counted.
+func (r *WorklogRepository) Timesheet(userID int, teamID *int, from time.Time, to time.Time) (*model.Timesheet, error) {
+	entries, err := r.queryWorklogs(
+		`SELECT w.id, w.task_id, t.name, w.user_id, w.minutes, w.note, w.work_date, w.created_at, w.updated_at
+		FROM task_worklogs w
+		JOIN tasks t ON t.id = w.task_id
+		WHERE w.user_id = $1
+		AND w.work_date BETWEEN $2::date AND $3::date
+		AND ($4::int IS NULL OR t.team_id = $4)
+		ORDER BY w.work_date, w.id`,
+		userID, from, to, teamID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.NewTimesheet(userID, entries), nil
+}
+
+func (r *WorklogRepository) queryWorklogs(query string, args ...interface{}) ([]*model.Worklog, error) {
+	rows, err := r.store.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	worklogs := []*model.Worklog{}
+
+	for rows.Next() {
+		w, err := scanWorklog(rows)
+		if err != nil {
+			return nil, err
+		}
+		worklogs = append(worklogs, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return worklogs, nil
+}
+
+func (r *WorklogRepository) Update(w *model.Worklog) error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+
+	w.UpdatedAt = time.Now()
+
+	result, err := r.store.db.Exec(
+		`UPDATE task_worklogs SET minutes = $1, note = $2, work_date = $3, updated_at = $4
+		WHERE id = $5`,
+		w.Minutes, w.Note, w.WorkDate, w.UpdatedAt, w.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result, store.ErrRecordNotFound)
+}
+
+func (r *WorklogRepository) Delete(id int) error {
+	result, err := r.store.db.Exec(
+		`DELETE FROM task_worklogs
+		WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result, store.ErrRecordNotFound)
+}
@@ -4,4 +4,20 @@ type Store interface {
 	User() UserRepository
 	Team() TeamRepository
 	Task() TaskRepository
+	Invitation() InvitationRepository
+	Reminder() ReminderRepository
+	Archive() ArchiveRepository
+	Comment() CommentRepository
+	Activity() ActivityRepository
+	Checklist() ChecklistRepository
+	Dependency() DependencyRepository
+	Label() LabelRepository
+	Workflow() WorkflowRepository
+	Sprint() SprintRepository
+	Report() ReportRepository
+	Worklog() WorklogRepository
+	Template() TemplateRepository
+	CustomField() CustomFieldRepository
+	Search() SearchRepository
+	Attachment() AttachmentRepository
 }
@@ -0,0 +1,63 @@
+package teststore
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type ActivityRepository struct {
+	store *Store
+}
+
+func (r *ActivityRepository) Record(entries ...*model.Activity) error {
+	for _, a := range entries {
+		if a.CreatedAt.IsZero() {
+			a.CreatedAt = time.Now()
+		}
+	}
+
+	r.store.record(entries...)
+	return nil
+}
+
+func (r *ActivityRepository) List(f *store.ActivityFilter) (*store.ActivityPage, error) {
+	if f.Limit <= 0 {
+		f.Limit = store.DefaultPageSize
+	}
+	if f.Limit > store.MaxPageSize {
+		f.Limit = store.MaxPageSize
+	}
+
+	before := 0
+	if f.Cursor != "" {
+		id, err := strconv.Atoi(f.Cursor)
+		if err != nil {
+			return nil, store.ErrInvalidCursor
+		}
+		before = id
+	}
+
+	page := &store.ActivityPage{
+		Items: []*model.Activity{},
+	}
+
+	for i := len(r.store.activity) - 1; i >= 0; i-- {
+		a := r.store.activity[i]
+		if f.TaskID != nil && a.TaskID != *f.TaskID ||
+			f.TeamID != nil && a.TeamID != *f.TeamID ||
+			before != 0 && a.ID >= before {
+			continue
+		}
+		if len(page.Items) == f.Limit {
+			page.NextCursor = strconv.Itoa(page.Items[f.Limit-1].ID)
+			break
+		}
+		cp := *a
+		page.Items = append(page.Items, &cp)
+	}
+
+	return page, nil
+}
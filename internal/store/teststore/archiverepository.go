@@ -0,0 +1,81 @@
+package teststore
+
+import (
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type ArchiveRepository struct {
+	store *Store
+}
+
+func (r *ArchiveRepository) Abandon(taskID int, reason string, by *int) error {
+	t, err := r.store.task(taskID)
+	if err != nil {
+		return err
+	}
+
+	r.store.archived[taskID] = &model.AbandonedTask{
+		Reason:      reason,
+		AbandonedBy: by,
+		AbandonedAt: time.Now(),
+	}
+
+	entry := model.NewActivity(t, by, model.ActivityAbandoned)
+	entry.NewValue = &reason
+	r.store.record(entry)
+
+	return nil
+}
+
+func (r *ArchiveRepository) AbandonStale(before time.Time, reason string) (int64, error) {
+	var n int64
+	for _, id := range sortedIDs(r.store.tasks) {
+		t, err := r.store.task(id)
+		if err != nil || t.Category != model.CategoryInProgress || !t.UpdatedAt.Before(before) {
+			continue
+		}
+		if err := r.Abandon(id, reason, nil); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+func (r *ArchiveRepository) ListByTeam(teamID int) ([]*model.AbandonedTask, error) {
+	var tasks []*model.AbandonedTask
+	for _, id := range sortedIDs(r.store.archived) {
+		if t := r.store.tasks[id]; t.TeamID == teamID {
+			a := *r.store.archived[id]
+			a.Task = *r.store.view(t)
+			tasks = append(tasks, &a)
+		}
+	}
+	return tasks, nil
+}
+
+func (r *ArchiveRepository) Restore(teamID int, taskID int, by *int) (*model.Task, error) {
+	t, ok := r.store.tasks[taskID]
+	if _, archived := r.store.archived[taskID]; !ok || !archived || t.TeamID != teamID {
+		return nil, store.ErrRecordNotFound
+	}
+
+	delete(r.store.archived, taskID)
+	wf := r.store.workflows[teamID]
+	if wf.Status(t.Status) == nil {
+		t.Status = wf.Initial()
+	}
+	t.Rank = r.store.nextRank(teamID, t.Status)
+	t.UpdatedAt = time.Now()
+
+	restored, err := r.store.task(taskID)
+	if err != nil {
+		return nil, err
+	}
+	r.store.record(model.NewActivity(restored, by, model.ActivityRestored))
+
+	return restored, nil
+}
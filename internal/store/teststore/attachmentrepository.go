@@ -0,0 +1,52 @@
+package teststore
+
+import (
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type AttachmentRepository struct {
+	store *Store
+}
+
+func (r *AttachmentRepository) Create(a *model.Attachment) error {
+	a.ID = r.store.id()
+	a.CreatedAt = time.Now()
+
+	cp := *a
+	r.store.attachments[a.ID] = &cp
+
+	return nil
+}
+
+func (r *AttachmentRepository) Find(id int) (*model.Attachment, error) {
+	a, ok := r.store.attachments[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	cp := *a
+	return &cp, nil
+}
+
+func (r *AttachmentRepository) ListByTask(taskID int) ([]*model.Attachment, error) {
+	attachments := []*model.Attachment{}
+	for _, id := range sortedIDs(r.store.attachments) {
+		if a := r.store.attachments[id]; a.TaskID == taskID {
+			cp := *a
+			attachments = append(attachments, &cp)
+		}
+	}
+	return attachments, nil
+}
+
+func (r *AttachmentRepository) Delete(id int) error {
+	if _, ok := r.store.attachments[id]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.store.attachments, id)
+	return nil
+}
@@ -0,0 +1,84 @@
+package teststore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type ChecklistRepository struct {
+	store *Store
+}
+
+func (r *ChecklistRepository) Create(i *model.ChecklistItem) error {
+	if err := i.Validate(); err != nil {
+		return err
+	}
+
+	i.Position = 1
+	for _, other := range r.store.checklist {
+		if other.TaskID == i.TaskID && other.Position >= i.Position {
+			i.Position = other.Position + 1
+		}
+	}
+
+	i.ID = r.store.id()
+	i.CreatedAt = time.Now()
+	i.UpdatedAt = i.CreatedAt
+
+	cp := *i
+	r.store.checklist[i.ID] = &cp
+
+	return nil
+}
+
+func (r *ChecklistRepository) Find(id int) (*model.ChecklistItem, error) {
+	i, ok := r.store.checklist[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	cp := *i
+	return &cp, nil
+}
+
+func (r *ChecklistRepository) ListByTask(taskID int) ([]*model.ChecklistItem, error) {
+	items := []*model.ChecklistItem{}
+	for _, id := range sortedIDs(r.store.checklist) {
+		if i := r.store.checklist[id]; i.TaskID == taskID {
+			cp := *i
+			items = append(items, &cp)
+		}
+	}
+	sort.SliceStable(items, func(a, b int) bool { return items[a].Position < items[b].Position })
+
+	return items, nil
+}
+
+func (r *ChecklistRepository) Update(i *model.ChecklistItem) error {
+	if err := i.Validate(); err != nil {
+		return err
+	}
+
+	if _, ok := r.store.checklist[i.ID]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	i.UpdatedAt = time.Now()
+
+	cp := *i
+	r.store.checklist[i.ID] = &cp
+
+	return nil
+}
+
+func (r *ChecklistRepository) Delete(id int) error {
+	if _, ok := r.store.checklist[id]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.store.checklist, id)
+	return nil
+}
@@ -0,0 +1,102 @@
+package teststore
+
+import (
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type CommentRepository struct {
+	store *Store
+}
+
+func (r *CommentRepository) Create(c *model.Comment) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	if c.ParentID != nil {
+		parent, ok := r.store.comments[*c.ParentID]
+		if !ok || parent.TaskID != c.TaskID {
+			return store.ErrParentCommentNotFound
+		}
+	}
+
+	c.ID = r.store.id()
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = c.CreatedAt
+
+	cp := *c
+	r.store.comments[c.ID] = &cp
+
+	return nil
+}
+
+func (r *CommentRepository) Find(id int) (*model.Comment, error) {
+	c, ok := r.store.comments[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	cp := *c
+	return &cp, nil
+}
+
+func (r *CommentRepository) ListByTask(taskID int) ([]*model.Comment, error) {
+	comments := []*model.Comment{}
+	for _, id := range sortedIDs(r.store.comments) {
+		if c := r.store.comments[id]; c.TaskID == taskID {
+			cp := *c
+			comments = append(comments, &cp)
+		}
+	}
+	return comments, nil
+}
+
+func (r *CommentRepository) Update(c *model.Comment, editorID int) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	old, ok := r.store.comments[c.ID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	r.store.revisions[c.ID] = append(r.store.revisions[c.ID], &model.CommentRevision{
+		ID:        r.store.id(),
+		CommentID: c.ID,
+		Body:      old.Body,
+		EditedBy:  &editorID,
+		EditedAt:  time.Now(),
+	})
+
+	c.UpdatedAt = time.Now()
+	c.Edited = true
+	old.Body = c.Body
+	old.Mentions = c.Mentions
+	old.UpdatedAt = c.UpdatedAt
+	old.Edited = true
+
+	return nil
+}
+
+func (r *CommentRepository) Delete(id int) error {
+	if _, ok := r.store.comments[id]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.store.comments, id)
+	delete(r.store.revisions, id)
+	return nil
+}
+
+func (r *CommentRepository) Revisions(commentID int) ([]*model.CommentRevision, error) {
+	revisions := []*model.CommentRevision{}
+	for _, rev := range r.store.revisions[commentID] {
+		cp := *rev
+		revisions = append(revisions, &cp)
+	}
+	return revisions, nil
+}
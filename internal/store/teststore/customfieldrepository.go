@@ -0,0 +1,125 @@
+package teststore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type CustomFieldRepository struct {
+	store *Store
+}
+
+func (r *CustomFieldRepository) taken(f *model.CustomField) bool {
+	for _, other := range r.store.fields {
+		if other.ID != f.ID && other.TeamID == f.TeamID && other.Key == f.Key {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *CustomFieldRepository) Create(f *model.CustomField) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	if r.taken(f) {
+		return model.ErrFieldExists
+	}
+
+	f.ID = r.store.id()
+	f.CreatedAt = time.Now()
+	f.UpdatedAt = f.CreatedAt
+
+	cp := *f
+	r.store.fields[f.ID] = &cp
+
+	return nil
+}
+
+func (r *CustomFieldRepository) Find(id int) (*model.CustomField, error) {
+	f, ok := r.store.fields[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	cp := *f
+	return &cp, nil
+}
+
+func (r *CustomFieldRepository) ListByTeam(teamID int) ([]*model.CustomField, error) {
+	fields := []*model.CustomField{}
+	for _, id := range sortedIDs(r.store.fields) {
+		if f := r.store.fields[id]; f.TeamID == teamID {
+			cp := *f
+			fields = append(fields, &cp)
+		}
+	}
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].Position < fields[j].Position })
+
+	return fields, nil
+}
+
+func (r *CustomFieldRepository) Update(f *model.CustomField) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	old, ok := r.store.fields[f.ID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	if old.Type != f.Type {
+		return model.ErrFieldTypeChange
+	}
+	if f.Type == model.FieldSelect {
+		for _, values := range r.store.values {
+			if v, ok := values[f.ID]; ok && !f.HasOption(v.Value) {
+				return model.ErrFieldOptionInUse
+			}
+		}
+	}
+	if r.taken(f) {
+		return model.ErrFieldExists
+	}
+
+	f.CreatedAt = old.CreatedAt
+	f.UpdatedAt = time.Now()
+
+	cp := *f
+	r.store.fields[f.ID] = &cp
+
+	return nil
+}
+
+func (r *CustomFieldRepository) Delete(id int) error {
+	if _, ok := r.store.fields[id]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.store.fields, id)
+	for _, values := range r.store.values {
+		delete(values, id)
+	}
+	return nil
+}
+
+func (r *CustomFieldRepository) SetValues(taskID int, values []*model.FieldValue, clear []int, by *int) error {
+	return r.store.track(taskID, by, func(*model.Task) error {
+		if r.store.values[taskID] == nil {
+			r.store.values[taskID] = make(map[int]*model.FieldValue)
+		}
+		for _, v := range values {
+			cp := *v
+			r.store.values[taskID][v.FieldID] = &cp
+		}
+		for _, id := range clear {
+			delete(r.store.values[taskID], id)
+		}
+
+		r.store.tasks[taskID].UpdatedAt = time.Now()
+		return nil
+	})
+}
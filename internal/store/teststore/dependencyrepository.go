@@ -0,0 +1,83 @@
+package teststore
+
+import (
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type DependencyRepository struct {
+	store *Store
+}
+
+func (r *DependencyRepository) Add(blockerID int, blockedID int, createdBy *int) error {
+	if blockerID == blockedID {
+		return model.ErrDependencySelf
+	}
+
+	blocker, err := r.store.task(blockerID)
+	if err != nil {
+		return err
+	}
+	blocked, err := r.store.task(blockedID)
+	if err != nil {
+		return err
+	}
+	if blocker.TeamID != blocked.TeamID {
+		return model.ErrDependencyOtherTeam
+	}
+
+	if contains(r.store.blockers[blockedID], blockerID) {
+		return store.ErrAlreadyLinked
+	}
+	if r.blocks(blockedID, blockerID, map[int]bool{}) {
+		return model.ErrDependencyCycle
+	}
+
+	r.store.blockers[blockedID] = append(r.store.blockers[blockedID], blockerID)
+	return nil
+}
+
+// blocks reports whether from blocks to, directly or through other tasks.
+func (r *DependencyRepository) blocks(from int, to int, seen map[int]bool) bool {
+	for _, id := range r.store.blockers[to] {
+		if id == from {
+			return true
+		}
+		if !seen[id] {
+			seen[id] = true
+			if r.blocks(from, id, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *DependencyRepository) Remove(blockerID int, blockedID int) error {
+	if !contains(r.store.blockers[blockedID], blockerID) {
+		return store.ErrRecordNotFound
+	}
+
+	r.store.blockers[blockedID] = without(r.store.blockers[blockedID], blockerID)
+	return nil
+}
+
+func (r *DependencyRepository) Blockers(taskID int) ([]*model.Task, error) {
+	tasks := []*model.Task{}
+	for _, id := range sortedIDs(r.store.tasks) {
+		if t, err := r.store.task(id); err == nil && contains(r.store.blockers[taskID], id) {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
+
+func (r *DependencyRepository) Blocking(taskID int) ([]*model.Task, error) {
+	tasks := []*model.Task{}
+	for _, id := range sortedIDs(r.store.tasks) {
+		if t, err := r.store.task(id); err == nil && contains(r.store.blockers[id], taskID) {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
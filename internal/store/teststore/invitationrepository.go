@@ -0,0 +1,105 @@
+package teststore
+
+import (
+	"strings"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type InvitationRepository struct {
+	store *Store
+}
+
+func (r *InvitationRepository) Create(i *model.Invitation) error {
+	if err := i.Validate(); err != nil {
+		return err
+	}
+	i.BeforeCreate()
+
+	for _, other := range r.store.invitations {
+		if other.TeamID == i.TeamID && strings.EqualFold(other.Email, i.Email) && other.Open() {
+			return store.ErrAlreadyInvited
+		}
+	}
+
+	i.ID = r.store.id()
+
+	cp := *i
+	r.store.invitations[i.ID] = &cp
+
+	return nil
+}
+
+func (r *InvitationRepository) Find(id int) (*model.Invitation, error) {
+	i, ok := r.store.invitations[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	cp := *i
+	cp.TeamName = r.store.teams[i.TeamID].Name
+	return &cp, nil
+}
+
+func (r *InvitationRepository) list(keep func(i *model.Invitation) bool) []*model.Invitation {
+	invitations := []*model.Invitation{}
+	for _, id := range sortedIDs(r.store.invitations) {
+		if i := r.store.invitations[id]; i.Open() && keep(i) {
+			found, _ := r.Find(id)
+			invitations = append(invitations, found)
+		}
+	}
+	return invitations
+}
+
+func (r *InvitationRepository) ListPendingByTeam(teamID int) ([]*model.Invitation, error) {
+	return r.list(func(i *model.Invitation) bool { return i.TeamID == teamID }), nil
+}
+
+func (r *InvitationRepository) ListPendingByEmail(email string) ([]*model.Invitation, error) {
+	return r.list(func(i *model.Invitation) bool { return strings.EqualFold(i.Email, email) }), nil
+}
+
+func (r *InvitationRepository) Respond(id int, status model.InvitationStatus) error {
+	i, ok := r.store.invitations[id]
+	if !ok || !i.Open() {
+		return model.ErrInvitationNotPending
+	}
+
+	now := time.Now()
+	i.Status = status
+	i.RespondedAt = &now
+
+	return nil
+}
+
+func (r *InvitationRepository) Accept(id int, userID int) error {
+	if err := r.Respond(id, model.InvitationAccepted); err != nil {
+		return err
+	}
+
+	i := r.store.invitations[id]
+	if _, ok := r.store.members[i.TeamID][userID]; !ok {
+		r.store.members[i.TeamID][userID] = &model.TeamMember{
+			UserID:    userID,
+			TeamID:    i.TeamID,
+			Role:      i.Role,
+			CreatedAt: time.Now(),
+		}
+	}
+
+	return nil
+}
+
+func (r *InvitationRepository) ExpireStale() (int64, error) {
+	var n int64
+	for _, i := range r.store.invitations {
+		if i.Status == model.InvitationPending && !i.ExpiresAt.After(time.Now()) {
+			i.Status = model.InvitationExpired
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,119 @@
+package teststore
+
+import (
+	"strings"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type LabelRepository struct {
+	store *Store
+}
+
+func (r *LabelRepository) Create(l *model.Label) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+	if r.taken(l) {
+		return store.ErrLabelExists
+	}
+
+	l.ID = r.store.id()
+	l.CreatedAt = time.Now()
+
+	cp := *l
+	r.store.labels[l.ID] = &cp
+
+	return nil
+}
+
+func (r *LabelRepository) taken(l *model.Label) bool {
+	for _, other := range r.store.labels {
+		if other.ID != l.ID && other.TeamID == l.TeamID && strings.EqualFold(other.Name, l.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *LabelRepository) Find(id int) (*model.Label, error) {
+	l, ok := r.store.labels[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	cp := *l
+	return &cp, nil
+}
+
+func (r *LabelRepository) ListByTeam(teamID int) ([]*model.Label, error) {
+	labels := []*model.Label{}
+	for _, id := range sortedIDs(r.store.labels) {
+		if l := r.store.labels[id]; l.TeamID == teamID {
+			cp := *l
+			labels = append(labels, &cp)
+		}
+	}
+	return labels, nil
+}
+
+func (r *LabelRepository) Update(l *model.Label) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+
+	old, ok := r.store.labels[l.ID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	if r.taken(l) {
+		return store.ErrLabelExists
+	}
+
+	old.Name = l.Name
+	old.Color = l.Color
+	return nil
+}
+
+func (r *LabelRepository) Delete(id int) error {
+	if _, ok := r.store.labels[id]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.store.labels, id)
+	for taskID, ids := range r.store.taskLabels {
+		r.store.taskLabels[taskID] = without(ids, id)
+	}
+	return nil
+}
+
+func (r *LabelRepository) Attach(taskID int, labelID int, by *int) error {
+	return r.store.track(taskID, by, func(t *model.Task) error {
+		return r.store.attachLabel(t, labelID)
+	})
+}
+
+func (s *Store) attachLabel(t *model.Task, labelID int) error {
+	l, ok := s.labels[labelID]
+	if !ok || l.TeamID != t.TeamID {
+		return store.ErrRecordNotFound
+	}
+
+	if !contains(s.taskLabels[t.ID], labelID) {
+		s.taskLabels[t.ID] = append(s.taskLabels[t.ID], labelID)
+	}
+	return nil
+}
+
+func (r *LabelRepository) Detach(taskID int, labelID int, by *int) error {
+	return r.store.track(taskID, by, func(*model.Task) error {
+		if !contains(r.store.taskLabels[taskID], labelID) {
+			return store.ErrRecordNotFound
+		}
+
+		r.store.taskLabels[taskID] = without(r.store.taskLabels[taskID], labelID)
+		return nil
+	})
+}
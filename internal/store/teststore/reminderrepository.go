@@ -0,0 +1,50 @@
+package teststore
+
+import (
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type ReminderRepository struct {
+	store *Store
+}
+
+// Pending is not needed by handlers and always comes back empty.
+func (r *ReminderRepository) Pending(kind model.ReminderKind, from time.Time, to time.Time, leads []time.Duration) ([]*model.PendingReminder, error) {
+	return []*model.PendingReminder{}, nil
+}
+
+func (r *ReminderRepository) Record(rem *model.Reminder) (bool, error) {
+	for _, other := range r.store.reminders {
+		if other.TaskID == rem.TaskID && other.UserID == rem.UserID && other.Kind == rem.Kind &&
+			other.Lead == rem.Lead && other.DueDate.Equal(rem.DueDate) {
+			return false, nil
+		}
+	}
+
+	rem.ID = r.store.id()
+	rem.CreatedAt = time.Now()
+
+	cp := *rem
+	r.store.reminders[rem.ID] = &cp
+
+	return true, nil
+}
+
+func (r *ReminderRepository) MarkSent(id int) error {
+	rem, ok := r.store.reminders[id]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	now := time.Now()
+	rem.SentAt = &now
+	return nil
+}
+
+func (r *ReminderRepository) Delete(id int) error {
+	delete(r.store.reminders, id)
+	return nil
+}
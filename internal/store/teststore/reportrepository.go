@@ -0,0 +1,33 @@
+package teststore
+
+import (
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+)
+
+// ReportRepository returns empty reports: handler tests only check who may
+// see them.
+type ReportRepository struct {
+	store *Store
+}
+
+func (r *ReportRepository) Burndown(teamID int, sprintID *int, from time.Time, to time.Time) (*model.Burndown, error) {
+	return &model.Burndown{
+		TeamID:   teamID,
+		SprintID: sprintID,
+		Points:   []*model.BurndownPoint{},
+	}, nil
+}
+
+func (r *ReportRepository) CycleTime(teamID int, from time.Time, to time.Time) (model.CycleTimeReport, error) {
+	return model.CycleTimeReport{}, nil
+}
+
+func (r *ReportRepository) Throughput(teamID int, from time.Time, to time.Time) (model.ThroughputReport, error) {
+	return model.ThroughputReport{}, nil
+}
+
+func (r *ReportRepository) Workload(teamID int) (model.WorkloadReport, error) {
+	return model.WorkloadReport{}, nil
+}
@@ -0,0 +1,15 @@
+package teststore
+
+import (
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+// SearchRepository finds nothing; full-text search needs Postgres.
+type SearchRepository struct {
+	store *Store
+}
+
+func (r *SearchRepository) Search(q *store.SearchQuery) (*model.SearchResult, error) {
+	return &model.SearchResult{Hits: []*model.SearchHit{}}, nil
+}
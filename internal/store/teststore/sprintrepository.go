@@ -0,0 +1,198 @@
+package teststore
+
+import (
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type SprintRepository struct {
+	store *Store
+}
+
+func (r *SprintRepository) Create(s *model.Sprint) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	s.ID = r.store.id()
+	s.State = model.SprintPlanned
+	s.CreatedAt = time.Now()
+
+	cp := *s
+	r.store.sprints[s.ID] = &cp
+
+	return nil
+}
+
+func (r *SprintRepository) Find(id int) (*model.Sprint, error) {
+	s, ok := r.store.sprints[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	cp := *s
+	return &cp, nil
+}
+
+func (r *SprintRepository) ListByTeam(teamID int) ([]*model.Sprint, error) {
+	sprints := []*model.Sprint{}
+	for _, id := range sortedIDs(r.store.sprints) {
+		if s := r.store.sprints[id]; s.TeamID == teamID {
+			cp := *s
+			sprints = append(sprints, &cp)
+		}
+	}
+	return sprints, nil
+}
+
+func (r *SprintRepository) Update(s *model.Sprint) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	old, ok := r.store.sprints[s.ID]
+	if !ok || old.State == model.SprintClosed {
+		return model.ErrSprintClosed
+	}
+
+	old.Name = s.Name
+	old.Goal = s.Goal
+	old.StartDate = s.StartDate
+	old.EndDate = s.EndDate
+	return nil
+}
+
+func (r *SprintRepository) Start(id int) error {
+	s, ok := r.store.sprints[id]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	if s.State != model.SprintPlanned {
+		return model.ErrSprintNotPlanned
+	}
+	for _, other := range r.store.sprints {
+		if other.TeamID == s.TeamID && other.State == model.SprintActive {
+			return model.ErrSprintActiveExists
+		}
+	}
+
+	now := time.Now()
+	s.State = model.SprintActive
+	s.StartedAt = &now
+	return nil
+}
+
+func (r *SprintRepository) Close(id int, carryTo *int, by *int) error {
+	s, ok := r.store.sprints[id]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	if s.State != model.SprintActive {
+		return model.ErrSprintNotActive
+	}
+
+	if carryTo != nil {
+		next, ok := r.store.sprints[*carryTo]
+		if !ok || next.ID == s.ID || next.TeamID != s.TeamID || !next.Open() {
+			return model.ErrSprintCarryOver
+		}
+	}
+
+	carried := r.tasks(func(t *model.Task) bool {
+		return t.Category != model.CategoryDone
+	}, id)
+	if err := r.move(carried, carryTo, by); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	s.State = model.SprintClosed
+	s.ClosedAt = &now
+	s.CarriedOver = len(carried)
+	return nil
+}
+
+func (r *SprintRepository) AddTasks(sprintID int, taskIDs []int, by *int) error {
+	s, ok := r.store.sprints[sprintID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	if !s.Open() {
+		return model.ErrSprintClosed
+	}
+
+	var ids []int
+	for _, id := range taskIDs {
+		t, err := r.store.task(id)
+		if err != nil || t.TeamID != s.TeamID {
+			return store.ErrRecordNotFound
+		}
+		if !contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	return r.move(ids, &sprintID, by)
+}
+
+func (r *SprintRepository) RemoveTask(sprintID int, taskID int, by *int) error {
+	s, ok := r.store.sprints[sprintID]
+	if !ok || s.State == model.SprintClosed {
+		return store.ErrRecordNotFound
+	}
+
+	t, err := r.store.task(taskID)
+	if err != nil || t.SprintID == nil || *t.SprintID != sprintID {
+		return store.ErrRecordNotFound
+	}
+
+	return r.move([]int{taskID}, nil, by)
+}
+
+// tasks returns the ids of live tasks in the sprint that match keep.
+func (r *SprintRepository) tasks(keep func(t *model.Task) bool, sprintID int) []int {
+	var ids []int
+	for _, id := range sortedIDs(r.store.tasks) {
+		t, err := r.store.task(id)
+		if err == nil && t.SprintID != nil && *t.SprintID == sprintID && keep(t) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (r *SprintRepository) move(ids []int, to *int, by *int) error {
+	for _, id := range ids {
+		if err := r.store.track(id, by, func(*model.Task) error {
+			r.store.tasks[id].SprintID = to
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Summary counts the tasks of the sprint as they are now; commitments are
+// not tracked, so everything counts as committed.
+func (r *SprintRepository) Summary(id int) (*model.SprintSummary, error) {
+	s, ok := r.store.sprints[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	sum := &model.SprintSummary{
+		SprintID:    id,
+		State:       s.State,
+		CarriedOver: s.CarriedOver,
+	}
+	sum.Total = len(r.tasks(func(*model.Task) bool { return true }, id))
+	sum.Completed = len(r.tasks(func(t *model.Task) bool { return t.Category == model.CategoryDone }, id))
+	sum.Committed = sum.Total
+	sum.CommittedCompleted = sum.Completed
+	sum.Remaining = sum.Total - sum.Completed
+
+	return sum, nil
+}
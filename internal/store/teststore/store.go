@@ -0,0 +1,264 @@
+package teststore
+
+import (
+	"sort"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+// Store keeps everything in memory for handler and policy tests. It follows
+// the rules of sqlstore where handlers depend on them and simplifies the
+// rest: reports and search come back empty, and nothing is transactional.
+type Store struct {
+	nextID int
+
+	users       map[int]*model.User
+	teams       map[int]*model.Team
+	members     map[int]map[int]*model.TeamMember
+	tasks       map[int]*model.Task
+	archived    map[int]*model.AbandonedTask
+	assignees   map[int][]int
+	watchers    map[int][]int
+	invitations map[int]*model.Invitation
+	reminders   map[int]*model.Reminder
+	comments    map[int]*model.Comment
+	revisions   map[int][]*model.CommentRevision
+	activity    []*model.Activity
+	checklist   map[int]*model.ChecklistItem
+	blockers    map[int][]int
+	labels      map[int]*model.Label
+	taskLabels  map[int][]int
+	workflows   map[int]*model.Workflow
+	sprints     map[int]*model.Sprint
+	worklogs    map[int]*model.Worklog
+	templates   map[int]*model.TaskTemplate
+	fields      map[int]*model.CustomField
+	values      map[int]map[int]*model.FieldValue
+	attachments map[int]*model.Attachment
+
+	userRepository *UserRepository
+	teamRepository *TeamRepository
+	taskRepository *TaskRepository
+
+	invitationRepository  *InvitationRepository
+	reminderRepository    *ReminderRepository
+	archiveRepository     *ArchiveRepository
+	commentRepository     *CommentRepository
+	activityRepository    *ActivityRepository
+	checklistRepository   *ChecklistRepository
+	dependencyRepository  *DependencyRepository
+	labelRepository       *LabelRepository
+	workflowRepository    *WorkflowRepository
+	sprintRepository      *SprintRepository
+	reportRepository      *ReportRepository
+	worklogRepository     *WorklogRepository
+	templateRepository    *TemplateRepository
+	customFieldRepository *CustomFieldRepository
+	searchRepository      *SearchRepository
+	attachmentRepository  *AttachmentRepository
+}
+
+func New() *Store {
+	return &Store{
+		users:       make(map[int]*model.User),
+		teams:       make(map[int]*model.Team),
+		members:     make(map[int]map[int]*model.TeamMember),
+		tasks:       make(map[int]*model.Task),
+		archived:    make(map[int]*model.AbandonedTask),
+		assignees:   make(map[int][]int),
+		watchers:    make(map[int][]int),
+		invitations: make(map[int]*model.Invitation),
+		reminders:   make(map[int]*model.Reminder),
+		comments:    make(map[int]*model.Comment),
+		revisions:   make(map[int][]*model.CommentRevision),
+		checklist:   make(map[int]*model.ChecklistItem),
+		blockers:    make(map[int][]int),
+		labels:      make(map[int]*model.Label),
+		taskLabels:  make(map[int][]int),
+		workflows:   make(map[int]*model.Workflow),
+		sprints:     make(map[int]*model.Sprint),
+		worklogs:    make(map[int]*model.Worklog),
+		templates:   make(map[int]*model.TaskTemplate),
+		fields:      make(map[int]*model.CustomField),
+		values:      make(map[int]map[int]*model.FieldValue),
+		attachments: make(map[int]*model.Attachment),
+	}
+}
+
+// id hands out ids from one sequence shared by all records, so an id of one
+// kind never accidentally matches a record of another.
+func (s *Store) id() int {
+	s.nextID++
+	return s.nextID
+}
+
+func (s *Store) User() store.UserRepository {
+	if s.userRepository == nil {
+		s.userRepository = &UserRepository{store: s}
+	}
+	return s.userRepository
+}
+
+func (s *Store) Team() store.TeamRepository {
+	if s.teamRepository == nil {
+		s.teamRepository = &TeamRepository{store: s}
+	}
+	return s.teamRepository
+}
+
+func (s *Store) Task() store.TaskRepository {
+	if s.taskRepository == nil {
+		s.taskRepository = &TaskRepository{store: s}
+	}
+	return s.taskRepository
+}
+
+func (s *Store) Invitation() store.InvitationRepository {
+	if s.invitationRepository == nil {
+		s.invitationRepository = &InvitationRepository{store: s}
+	}
+	return s.invitationRepository
+}
+
+func (s *Store) Reminder() store.ReminderRepository {
+	if s.reminderRepository == nil {
+		s.reminderRepository = &ReminderRepository{store: s}
+	}
+	return s.reminderRepository
+}
+
+func (s *Store) Archive() store.ArchiveRepository {
+	if s.archiveRepository == nil {
+		s.archiveRepository = &ArchiveRepository{store: s}
+	}
+	return s.archiveRepository
+}
+
+func (s *Store) Comment() store.CommentRepository {
+	if s.commentRepository == nil {
+		s.commentRepository = &CommentRepository{store: s}
+	}
+	return s.commentRepository
+}
+
+func (s *Store) Activity() store.ActivityRepository {
+	if s.activityRepository == nil {
+		s.activityRepository = &ActivityRepository{store: s}
+	}
+	return s.activityRepository
+}
+
+func (s *Store) Checklist() store.ChecklistRepository {
+	if s.checklistRepository == nil {
+		s.checklistRepository = &ChecklistRepository{store: s}
+	}
+	return s.checklistRepository
+}
+
+func (s *Store) Dependency() store.DependencyRepository {
+	if s.dependencyRepository == nil {
+		s.dependencyRepository = &DependencyRepository{store: s}
+	}
+	return s.dependencyRepository
+}
+
+func (s *Store) Label() store.LabelRepository {
+	if s.labelRepository == nil {
+		s.labelRepository = &LabelRepository{store: s}
+	}
+	return s.labelRepository
+}
+
+func (s *Store) Workflow() store.WorkflowRepository {
+	if s.workflowRepository == nil {
+		s.workflowRepository = &WorkflowRepository{store: s}
+	}
+	return s.workflowRepository
+}
+
+func (s *Store) Sprint() store.SprintRepository {
+	if s.sprintRepository == nil {
+		s.sprintRepository = &SprintRepository{store: s}
+	}
+	return s.sprintRepository
+}
+
+func (s *Store) Report() store.ReportRepository {
+	if s.reportRepository == nil {
+		s.reportRepository = &ReportRepository{store: s}
+	}
+	return s.reportRepository
+}
+
+func (s *Store) Worklog() store.WorklogRepository {
+	if s.worklogRepository == nil {
+		s.worklogRepository = &WorklogRepository{store: s}
+	}
+	return s.worklogRepository
+}
+
+func (s *Store) Template() store.TemplateRepository {
+	if s.templateRepository == nil {
+		s.templateRepository = &TemplateRepository{store: s}
+	}
+	return s.templateRepository
+}
+
+func (s *Store) CustomField() store.CustomFieldRepository {
+	if s.customFieldRepository == nil {
+		s.customFieldRepository = &CustomFieldRepository{store: s}
+	}
+	return s.customFieldRepository
+}
+
+func (s *Store) Search() store.SearchRepository {
+	if s.searchRepository == nil {
+		s.searchRepository = &SearchRepository{store: s}
+	}
+	return s.searchRepository
+}
+
+func (s *Store) Attachment() store.AttachmentRepository {
+	if s.attachmentRepository == nil {
+		s.attachmentRepository = &AttachmentRepository{store: s}
+	}
+	return s.attachmentRepository
+}
+
+// record appends history entries, as sqlstore does in the transaction of
+// the change.
+func (s *Store) record(entries ...*model.Activity) {
+	for _, a := range entries {
+		a.ID = s.id()
+		s.activity = append(s.activity, a)
+	}
+}
+
+func sortedIDs[T any](m map[int]T) []int {
+	ids := make([]int, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func without(ids []int, id int) []int {
+	out := ids[:0:0]
+	for _, v := range ids {
+		if v != id {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func contains(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
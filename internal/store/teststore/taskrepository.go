@@ -0,0 +1,654 @@
+package teststore
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+const rankStep = 1 << 16
+
+type TaskRepository struct {
+	store *Store
+}
+
+// task returns a copy of a live task with everything sqlstore joins in.
+func (s *Store) task(id int) (*model.Task, error) {
+	t, ok := s.tasks[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+	if _, ok := s.archived[id]; ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	return s.view(t), nil
+}
+
+func (s *Store) view(t *model.Task) *model.Task {
+	cp := *t
+
+	if st := s.workflows[t.TeamID].Status(t.Status); st != nil {
+		cp.Category = st.Category
+	}
+	cp.AssigneeIDs = append([]int{}, s.assignees[t.ID]...)
+	cp.WatcherIDs = append([]int{}, s.watchers[t.ID]...)
+	sort.Ints(cp.WatcherIDs)
+
+	cp.Labels = []model.LabelRef{}
+	for _, id := range s.taskLabels[t.ID] {
+		l := s.labels[id]
+		cp.Labels = append(cp.Labels, model.LabelRef{ID: l.ID, Name: l.Name, Color: l.Color})
+	}
+	sort.Slice(cp.Labels, func(i, j int) bool { return cp.Labels[i].Name < cp.Labels[j].Name })
+
+	cp.Fields = model.FieldValues{}
+	for fieldID, v := range s.values[t.ID] {
+		if v.Number != nil {
+			cp.Fields[s.fields[fieldID].Key] = *v.Number
+		} else {
+			cp.Fields[s.fields[fieldID].Key] = v.Value
+		}
+	}
+
+	cp.SpentMinutes = 0
+	for _, w := range s.worklogs {
+		if w.TaskID == t.ID {
+			cp.SpentMinutes += w.Minutes
+		}
+	}
+
+	cp.Progress = model.TaskProgress{}
+	for _, id := range sortedIDs(s.tasks) {
+		if st := s.tasks[id]; st.ParentID != nil && *st.ParentID == t.ID && s.archived[id] == nil {
+			cp.Progress.SubtasksTotal++
+			if s.done(st) {
+				cp.Progress.SubtasksDone++
+			}
+		}
+	}
+	for _, item := range s.checklist {
+		if item.TaskID == t.ID {
+			cp.Progress.ChecklistTotal++
+			if item.Done {
+				cp.Progress.ChecklistDone++
+			}
+		}
+	}
+	cp.ComputeProgress()
+
+	cp.Blocked = false
+	for _, id := range s.blockers[t.ID] {
+		if b, err := s.task(id); err == nil && b.Category != model.CategoryDone {
+			cp.Blocked = true
+		}
+	}
+
+	return &cp
+}
+
+func (s *Store) done(t *model.Task) bool {
+	st := s.workflows[t.TeamID].Status(t.Status)
+	return st != nil && st.Category == model.CategoryDone
+}
+
+// track mirrors trackTask of sqlstore: the change is recorded field by
+// field under the actor.
+func (s *Store) track(taskID int, by *int, change func(t *model.Task) error) error {
+	before, err := s.task(taskID)
+	if err != nil {
+		return err
+	}
+
+	t := *before
+	if err := change(&t); err != nil {
+		return err
+	}
+
+	after, err := s.task(taskID)
+	if err != nil {
+		return err
+	}
+	s.record(model.DiffTasks(before, after, by)...)
+
+	return nil
+}
+
+func (s *Store) checkMember(teamID int, userID int) error {
+	if _, ok := s.members[teamID][userID]; !ok {
+		return store.ErrUserNotInTeam
+	}
+	return nil
+}
+
+func (s *Store) nextRank(teamID int, status model.TaskStatus) int64 {
+	var rank int64
+	for _, t := range s.tasks {
+		if t.TeamID == teamID && t.Status == status && t.Rank > rank {
+			rank = t.Rank
+		}
+	}
+	return rank + rankStep
+}
+
+func (s *Store) deleteTask(id int) {
+	delete(s.tasks, id)
+	delete(s.archived, id)
+	delete(s.assignees, id)
+	delete(s.watchers, id)
+	delete(s.blockers, id)
+	delete(s.taskLabels, id)
+	delete(s.values, id)
+	for blocked, ids := range s.blockers {
+		s.blockers[blocked] = without(ids, id)
+	}
+	for cid, c := range s.comments {
+		if c.TaskID == id {
+			delete(s.comments, cid)
+		}
+	}
+	for iid, i := range s.checklist {
+		if i.TaskID == id {
+			delete(s.checklist, iid)
+		}
+	}
+	for wid, w := range s.worklogs {
+		if w.TaskID == id {
+			delete(s.worklogs, wid)
+		}
+	}
+	for aid, a := range s.attachments {
+		if a.TaskID == id {
+			delete(s.attachments, aid)
+		}
+	}
+}
+
+func (r *TaskRepository) Create(t *model.Task, by *int) error {
+	if err := r.store.insertTask(t); err != nil {
+		return err
+	}
+
+	r.store.record(model.NewActivity(t, by, model.ActivityCreated))
+
+	return nil
+}
+
+func (s *Store) insertTask(t *model.Task) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	wf, ok := s.workflows[t.TeamID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	if t.Status == "" {
+		t.Status = wf.Initial()
+	}
+	status := wf.Status(t.Status)
+	if status == nil {
+		return model.ErrUnknownStatus
+	}
+	t.Category = status.Category
+
+	if t.Priority == "" {
+		t.Priority = model.LowPriority
+	}
+
+	if t.ParentID != nil {
+		parent, err := s.task(*t.ParentID)
+		if err != nil {
+			return store.ErrParentTaskNotFound
+		}
+		if parent.TeamID != t.TeamID {
+			return model.ErrParentOtherTeam
+		}
+		if parent.Category == model.CategoryDone && t.Category != model.CategoryDone {
+			return model.ErrParentDone
+		}
+	}
+
+	if t.AssigneeID != nil {
+		if err := s.checkMember(t.TeamID, *t.AssigneeID); err != nil {
+			return err
+		}
+	}
+
+	t.ID = s.id()
+	t.Rank = s.nextRank(t.TeamID, t.Status)
+	t.CreatedAt = time.Now()
+	t.UpdatedAt = time.Now()
+
+	cp := *t
+	s.tasks[t.ID] = &cp
+	if t.AssigneeID != nil {
+		s.assignees[t.ID] = []int{*t.AssigneeID}
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) Update(id int, p *model.TaskPatch, by *int) error {
+	return r.store.track(id, by, func(t *model.Task) error {
+		before := *t
+		p.Apply(t)
+		return r.update(t, &before)
+	})
+}
+
+func (r *TaskRepository) update(t *model.Task, before *model.Task) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	if t.Status != before.Status {
+		if err := r.checkStatusChange(t, before.Status); err != nil {
+			return err
+		}
+	}
+
+	if t.AssigneeID != nil && (before.AssigneeID == nil || *before.AssigneeID != *t.AssigneeID) {
+		if err := r.store.checkMember(t.TeamID, *t.AssigneeID); err != nil {
+			return err
+		}
+	}
+
+	old := r.store.tasks[t.ID]
+	if t.Status != old.Status {
+		old.Rank = r.store.nextRank(old.TeamID, t.Status)
+	}
+	old.Name = t.Name
+	old.Content = t.Content
+	old.Status = t.Status
+	old.Priority = t.Priority
+	old.DueDate = t.DueDate
+	old.StoryPoints = t.StoryPoints
+	old.EstimateMinutes = t.EstimateMinutes
+	old.Recurrence = t.Recurrence
+	old.UpdatedAt = time.Now()
+	t.UpdatedAt = old.UpdatedAt
+
+	r.replaceAssignee(t.ID, before.AssigneeID, t.AssigneeID)
+
+	return nil
+}
+
+// checkStatusChange follows the workflow checks of sqlstore: the status
+// must exist and be reachable, and a task only finishes with its subtasks
+// and blockers done.
+func (r *TaskRepository) checkStatusChange(t *model.Task, current model.TaskStatus) error {
+	wf := r.store.workflows[t.TeamID]
+	status := wf.Status(t.Status)
+	if status == nil {
+		return model.ErrUnknownStatus
+	}
+	if !wf.CanMove(current, t.Status) {
+		return model.ErrTransitionNotAllowed
+	}
+	t.Category = status.Category
+
+	if status.Category != model.CategoryDone {
+		return nil
+	}
+	for _, st := range r.store.tasks {
+		if st.ParentID != nil && *st.ParentID == t.ID && r.store.archived[st.ID] == nil && !r.store.done(st) {
+			return model.ErrOpenSubtasks
+		}
+	}
+	if view, err := r.store.task(t.ID); err == nil && view.Blocked {
+		return model.ErrTaskBlocked
+	}
+	return nil
+}
+
+func (r *TaskRepository) replaceAssignee(taskID int, old *int, new *int) {
+	if old != nil && new != nil && *old == *new || old == nil && new == nil {
+		return
+	}
+
+	if new == nil {
+		delete(r.store.assignees, taskID)
+		r.store.tasks[taskID].AssigneeID = nil
+		return
+	}
+
+	ids := r.store.assignees[taskID]
+	if old != nil {
+		ids = without(ids, *old)
+	}
+	if !contains(ids, *new) {
+		ids = append([]int{*new}, ids...)
+	}
+	r.store.assignees[taskID] = ids
+	r.store.tasks[taskID].AssigneeID = new
+}
+
+func (r *TaskRepository) Delete(id int, by *int) error {
+	t, ok := r.store.tasks[id]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	r.store.deleteTask(id)
+	r.store.record(model.NewActivity(t, by, model.ActivityDeleted))
+
+	return nil
+}
+
+func (r *TaskRepository) GetByID(id int) (*model.Task, error) {
+	return r.store.task(id)
+}
+
+// tasks returns the live tasks matching keep in id order.
+func (r *TaskRepository) tasks(keep func(t *model.Task) bool) []*model.Task {
+	tasks := []*model.Task{}
+	for _, id := range sortedIDs(r.store.tasks) {
+		t, err := r.store.task(id)
+		if err == nil && keep(t) {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks
+}
+
+func (r *TaskRepository) List() ([]*model.Task, error) {
+	return r.tasks(func(*model.Task) bool { return true }), nil
+}
+
+func (r *TaskRepository) ListByTeam(teamID int) ([]*model.Task, error) {
+	return r.tasks(func(t *model.Task) bool { return t.TeamID == teamID }), nil
+}
+
+func (r *TaskRepository) Involved(userID int) ([]*model.Task, error) {
+	return r.tasks(func(t *model.Task) bool {
+		_, member := r.store.members[t.TeamID][userID]
+		involved := contains(t.AssigneeIDs, userID) || contains(t.WatcherIDs, userID)
+		return member && involved && t.Category != model.CategoryDone
+	}), nil
+}
+
+// Find supports the filters handlers pass through most often; sorting is by
+// id and the cursor is the last id of the previous page.
+func (r *TaskRepository) Find(f *store.TaskFilter) (*store.TaskPage, error) {
+	after := 0
+	if f.Cursor != "" {
+		id, err := strconv.Atoi(f.Cursor)
+		if err != nil {
+			return nil, store.ErrInvalidCursor
+		}
+		after = id
+	}
+	if f.Limit <= 0 {
+		f.Limit = store.DefaultPageSize
+	}
+
+	tasks := r.tasks(func(t *model.Task) bool {
+		if _, member := r.store.members[t.TeamID][f.MemberID]; !member || t.ID <= after {
+			return false
+		}
+		if f.TeamID != nil && t.TeamID != *f.TeamID {
+			return false
+		}
+		if f.AssigneeID != nil && !contains(t.AssigneeIDs, *f.AssigneeID) {
+			return false
+		}
+		if f.SprintID != nil && (t.SprintID == nil || *t.SprintID != *f.SprintID) {
+			return false
+		}
+		return !f.Backlog || t.SprintID == nil
+	})
+
+	page := &store.TaskPage{Tasks: tasks}
+	if len(tasks) > f.Limit {
+		page.Tasks = tasks[:f.Limit]
+		page.NextCursor = strconv.Itoa(page.Tasks[f.Limit-1].ID)
+	}
+	return page, nil
+}
+
+func (r *TaskRepository) DueDate(from time.Time, to time.Time) ([]*model.Task, error) {
+	return r.tasks(func(t *model.Task) bool {
+		return t.DueDate != nil && !t.DueDate.Before(from) && t.DueDate.Before(to)
+	}), nil
+}
+
+func (r *TaskRepository) Children(parentID int) ([]*model.Task, error) {
+	return r.tasks(func(t *model.Task) bool {
+		return t.ParentID != nil && *t.ParentID == parentID
+	}), nil
+}
+
+func (r *TaskRepository) SetParent(taskID int, parentID *int, by *int) error {
+	return r.store.track(taskID, by, func(t *model.Task) error {
+		if parentID != nil {
+			parent, err := r.store.task(*parentID)
+			if err != nil {
+				return store.ErrParentTaskNotFound
+			}
+			if parent.TeamID != t.TeamID {
+				return model.ErrParentOtherTeam
+			}
+			for p := parent; ; {
+				if p.ID == taskID {
+					return model.ErrSubtaskCycle
+				}
+				if p.ParentID == nil {
+					break
+				}
+				if p, err = r.store.task(*p.ParentID); err != nil {
+					break
+				}
+			}
+			if parent.Category == model.CategoryDone && t.Category != model.CategoryDone {
+				return model.ErrParentDone
+			}
+		}
+
+		r.store.tasks[taskID].ParentID = parentID
+		return nil
+	})
+}
+
+func (r *TaskRepository) AssigneeUser(userID int, taskID int, by *int) error {
+	return r.store.track(taskID, by, func(t *model.Task) error {
+		if err := r.store.checkMember(t.TeamID, userID); err != nil {
+			return err
+		}
+		r.replaceAssignee(taskID, t.AssigneeID, &userID)
+		return nil
+	})
+}
+
+func (r *TaskRepository) AddAssignee(taskID int, userID int, by *int) error {
+	return r.store.track(taskID, by, func(t *model.Task) error {
+		return r.addAssignee(t, userID)
+	})
+}
+
+func (r *TaskRepository) addAssignee(t *model.Task, userID int) error {
+	if err := r.store.checkMember(t.TeamID, userID); err != nil {
+		return err
+	}
+
+	if !contains(r.store.assignees[t.ID], userID) {
+		r.store.assignees[t.ID] = append(r.store.assignees[t.ID], userID)
+	}
+	if r.store.tasks[t.ID].AssigneeID == nil {
+		r.store.tasks[t.ID].AssigneeID = &userID
+	}
+	return nil
+}
+
+func (r *TaskRepository) RemoveAssignee(taskID int, userID int, by *int) error {
+	return r.store.track(taskID, by, func(t *model.Task) error {
+		if !contains(r.store.assignees[taskID], userID) {
+			return store.ErrRecordNotFound
+		}
+
+		r.store.unassign(taskID, userID)
+		return nil
+	})
+}
+
+// unassign takes the user off the task and promotes the next assignee when
+// they were the main one.
+func (s *Store) unassign(taskID int, userID int) {
+	ids := without(s.assignees[taskID], userID)
+	s.assignees[taskID] = ids
+	if main := s.tasks[taskID].AssigneeID; main != nil && *main == userID {
+		s.tasks[taskID].AssigneeID = nil
+		if len(ids) > 0 {
+			s.tasks[taskID].AssigneeID = &ids[0]
+		}
+	}
+}
+
+func (r *TaskRepository) Watch(taskID int, userID int) error {
+	t, err := r.store.task(taskID)
+	if err != nil {
+		return err
+	}
+	if err := r.store.checkMember(t.TeamID, userID); err != nil {
+		return err
+	}
+
+	if !contains(r.store.watchers[taskID], userID) {
+		r.store.watchers[taskID] = append(r.store.watchers[taskID], userID)
+	}
+	return nil
+}
+
+func (r *TaskRepository) Unwatch(taskID int, userID int) error {
+	if !contains(r.store.watchers[taskID], userID) {
+		return store.ErrRecordNotFound
+	}
+
+	r.store.watchers[taskID] = without(r.store.watchers[taskID], userID)
+	return nil
+}
+
+// Bulk applies the operation task by task. Unlike sqlstore it cannot roll
+// back, so an all-or-nothing batch is checked before anything is changed.
+func (r *TaskRepository) Bulk(op *model.BulkOperation, taskIDs []int, atomic bool, by *int) ([]*model.BulkResult, error) {
+	results := make([]*model.BulkResult, len(taskIDs))
+	for i, id := range taskIDs {
+		results[i] = &model.BulkResult{TaskID: id}
+		if _, err := r.store.task(id); err != nil && atomic {
+			results[i].Error = err.Error()
+			for _, other := range results[:i] {
+				other.Error = model.ErrBulkRolledBack.Error()
+			}
+			for j := i + 1; j < len(taskIDs); j++ {
+				results[j] = &model.BulkResult{TaskID: taskIDs[j], Error: model.ErrBulkRolledBack.Error()}
+			}
+			return results, nil
+		}
+	}
+
+	for _, res := range results {
+		if err := r.applyBulk(op, res.TaskID, by); err != nil {
+			res.Error = err.Error()
+			continue
+		}
+		res.OK = true
+	}
+	return results, nil
+}
+
+func (r *TaskRepository) applyBulk(op *model.BulkOperation, taskID int, by *int) error {
+	switch op.Action {
+	case model.BulkSetStatus, model.BulkSetPriority:
+		return r.store.track(taskID, by, func(t *model.Task) error {
+			before := *t
+			if op.Action == model.BulkSetStatus {
+				t.Status = op.Status
+			} else {
+				t.Priority = op.Priority
+			}
+			return r.update(t, &before)
+		})
+	case model.BulkAssign:
+		return r.store.track(taskID, by, func(t *model.Task) error {
+			return r.addAssignee(t, *op.UserID)
+		})
+	case model.BulkAddLabel:
+		return r.store.track(taskID, by, func(t *model.Task) error {
+			return r.store.attachLabel(t, *op.LabelID)
+		})
+	case model.BulkDelete:
+		return r.Delete(taskID, by)
+	case model.BulkAbandon:
+		return r.store.Archive().Abandon(taskID, op.Reason, by)
+	}
+	return model.ErrBulkAction
+}
+
+func (r *TaskRepository) Board(teamID int) (*model.Board, error) {
+	wf, ok := r.store.workflows[teamID]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	tasks, _ := r.ListByTeam(teamID)
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].Rank < tasks[j].Rank })
+
+	return model.NewBoard(wf, tasks), nil
+}
+
+// Move places the card by renumbering its column in steps of rankStep,
+// which keeps the order sqlstore would produce without its gap logic.
+func (r *TaskRepository) Move(taskID int, status model.TaskStatus, beforeID *int, afterID *int, by *int) error {
+	return r.store.track(taskID, by, func(t *model.Task) error {
+		current := t.Status
+		t.Status = status
+		if current != status {
+			if err := r.checkStatusChange(t, current); err != nil {
+				return err
+			}
+		}
+
+		board, err := r.Board(t.TeamID)
+		if err != nil {
+			return err
+		}
+
+		var column []*model.Task
+		for _, c := range board.Columns {
+			if c.Status != status {
+				continue
+			}
+			for _, card := range c.Tasks {
+				if card.ID != taskID {
+					column = append(column, card)
+				}
+			}
+		}
+
+		at := len(column)
+		if beforeID != nil || afterID != nil {
+			at = -1
+			for i, card := range column {
+				if beforeID != nil && card.ID == *beforeID {
+					at = i
+				}
+				if afterID != nil && card.ID == *afterID {
+					at = i + 1
+				}
+			}
+			if at < 0 {
+				return store.ErrBoardNeighbour
+			}
+		}
+
+		column = append(column[:at], append([]*model.Task{t}, column[at:]...)...)
+		for i, card := range column {
+			r.store.tasks[card.ID].Rank = int64(i+1) * rankStep
+		}
+		r.store.tasks[taskID].Status = status
+		return nil
+	})
+}
@@ -0,0 +1,181 @@
+package teststore
+
+import (
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type TeamRepository struct {
+	store *Store
+}
+
+func (r *TeamRepository) Create(t *model.Team) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	t.ID = r.store.id()
+	t.CreatedAt = time.Now()
+	t.UpdatedAt = time.Now()
+
+	cp := *t
+	r.store.teams[t.ID] = &cp
+	r.store.members[t.ID] = make(map[int]*model.TeamMember)
+	r.store.workflows[t.ID] = model.DefaultWorkflow(t.ID)
+
+	return r.AddMembers(t.ID, t.OwnerID, model.RoleOwner)
+}
+
+func (r *TeamRepository) Find(id int) (*model.Team, error) {
+	t, ok := r.store.teams[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	cp := *t
+	return &cp, nil
+}
+
+func (r *TeamRepository) FindByUser(userID int) ([]*model.Team, error) {
+	var teams []*model.Team
+	for _, id := range sortedIDs(r.store.teams) {
+		if _, ok := r.store.members[id][userID]; ok {
+			cp := *r.store.teams[id]
+			teams = append(teams, &cp)
+		}
+	}
+
+	return teams, nil
+}
+
+func (r *TeamRepository) Update(t *model.Team) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	old, ok := r.store.teams[t.ID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	t.UpdatedAt = time.Now()
+	old.Name = t.Name
+	old.Description = t.Description
+	old.UpdatedAt = t.UpdatedAt
+
+	return nil
+}
+
+func (r *TeamRepository) Delete(id int) error {
+	delete(r.store.teams, id)
+	delete(r.store.members, id)
+	for _, taskID := range sortedIDs(r.store.tasks) {
+		if r.store.tasks[taskID].TeamID == id {
+			r.store.deleteTask(taskID)
+		}
+	}
+
+	return nil
+}
+
+func (r *TeamRepository) AddMembers(teamID int, userID int, role model.TeamRole) error {
+	if !role.Valid() {
+		return model.ErrInvalidRole
+	}
+
+	members, ok := r.store.members[teamID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	u, ok := r.store.users[userID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	if _, ok := members[userID]; ok {
+		return store.ErrAlreadyInTeam
+	}
+
+	members[userID] = &model.TeamMember{
+		TeamID:    teamID,
+		UserID:    userID,
+		Email:     u.Email,
+		Role:      role,
+		CreatedAt: time.Now(),
+	}
+
+	return nil
+}
+
+func (r *TeamRepository) RemoveMembers(teamID int, userID int) error {
+	if err := r.store.checkMember(teamID, userID); err != nil {
+		return err
+	}
+	delete(r.store.members[teamID], userID)
+
+	for id, t := range r.store.tasks {
+		if t.TeamID == teamID {
+			r.store.unassign(id, userID)
+			r.store.watchers[id] = without(r.store.watchers[id], userID)
+		}
+	}
+	return nil
+}
+
+func (r *TeamRepository) Members(teamID int) ([]*model.TeamMember, error) {
+	var members []*model.TeamMember
+	for _, id := range sortedIDs(r.store.members[teamID]) {
+		cp := *r.store.members[teamID][id]
+		members = append(members, &cp)
+	}
+
+	return members, nil
+}
+
+func (r *TeamRepository) MemberRole(teamID int, userID int) (model.TeamRole, error) {
+	m, ok := r.store.members[teamID][userID]
+	if !ok {
+		return "", store.ErrUserNotInTeam
+	}
+
+	return m.Role, nil
+}
+
+func (r *TeamRepository) UpdateMemberRole(teamID int, userID int, role model.TeamRole) error {
+	if !role.Valid() || role == model.RoleOwner {
+		return model.ErrInvalidRole
+	}
+
+	m, ok := r.store.members[teamID][userID]
+	if !ok || m.Role == model.RoleOwner {
+		return store.ErrUserNotInTeam
+	}
+	m.Role = role
+
+	return nil
+}
+
+func (r *TeamRepository) TransferOwnership(teamID int, newOwnerID int) error {
+	t, ok := r.store.teams[teamID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	if t.OwnerID == newOwnerID {
+		return model.ErrTransferToOwner
+	}
+
+	next, ok := r.store.members[teamID][newOwnerID]
+	if !ok {
+		return store.ErrUserNotInTeam
+	}
+
+	next.Role = model.RoleOwner
+	if old, ok := r.store.members[teamID][t.OwnerID]; ok {
+		old.Role = model.RoleAdmin
+	}
+	t.OwnerID = newOwnerID
+	t.UpdatedAt = time.Now()
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package teststore
+
+import (
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type TemplateRepository struct {
+	store *Store
+}
+
+func (r *TemplateRepository) check(t *model.TaskTemplate) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	for _, other := range r.store.templates {
+		if other.ID != t.ID && other.TeamID == t.TeamID && other.Name == t.Name {
+			return model.ErrTemplateNameExists
+		}
+	}
+	for _, id := range t.LabelIDs {
+		if l, ok := r.store.labels[id]; !ok || l.TeamID != t.TeamID {
+			return model.ErrTemplateLabelForeign
+		}
+	}
+	return nil
+}
+
+func (r *TemplateRepository) Create(t *model.TaskTemplate) error {
+	if err := r.check(t); err != nil {
+		return err
+	}
+
+	t.ID = r.store.id()
+	t.CreatedAt = time.Now()
+	t.UpdatedAt = t.CreatedAt
+
+	cp := *t
+	r.store.templates[t.ID] = &cp
+
+	return nil
+}
+
+func (r *TemplateRepository) Find(id int) (*model.TaskTemplate, error) {
+	t, ok := r.store.templates[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	cp := *t
+	return &cp, nil
+}
+
+func (r *TemplateRepository) ListByTeam(teamID int) ([]*model.TaskTemplate, error) {
+	templates := []*model.TaskTemplate{}
+	for _, id := range sortedIDs(r.store.templates) {
+		if t := r.store.templates[id]; t.TeamID == teamID {
+			cp := *t
+			templates = append(templates, &cp)
+		}
+	}
+	return templates, nil
+}
+
+func (r *TemplateRepository) Update(t *model.TaskTemplate) error {
+	old, ok := r.store.templates[t.ID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	if err := r.check(t); err != nil {
+		return err
+	}
+
+	t.CreatedAt = old.CreatedAt
+	t.UpdatedAt = time.Now()
+
+	cp := *t
+	r.store.templates[t.ID] = &cp
+
+	return nil
+}
+
+func (r *TemplateRepository) Delete(id int) error {
+	if _, ok := r.store.templates[id]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.store.templates, id)
+	return nil
+}
+
+// Instantiate is not atomic here: a failing subtask leaves the tasks made
+// before it in place.
+func (r *TemplateRepository) Instantiate(t *model.TaskTemplate, in *model.TemplateInstance, by *int) ([]*model.Task, error) {
+	root := &model.Task{
+		Name:       t.TaskName(time.Now(), in.Vars),
+		Content:    t.Content,
+		Priority:   t.Priority,
+		DueDate:    in.DueDate,
+		AssigneeID: in.AssigneeID,
+		TeamID:     t.TeamID,
+	}
+	if err := r.store.insertTask(root); err != nil {
+		return nil, err
+	}
+	created := []*model.Task{root}
+
+	for _, labelID := range t.LabelIDs {
+		if err := r.store.attachLabel(root, labelID); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, title := range t.Checklist {
+		if err := r.store.Checklist().Create(&model.ChecklistItem{TaskID: root.ID, Title: title}); err != nil {
+			return nil, err
+		}
+	}
+
+	if in.WithSubtasks {
+		for _, st := range t.Subtasks {
+			sub := &model.Task{
+				Name:       st.Name,
+				Content:    st.Content,
+				Priority:   st.Priority,
+				DueDate:    in.DueDate,
+				AssigneeID: in.AssigneeID,
+				TeamID:     t.TeamID,
+				ParentID:   &root.ID,
+			}
+			if err := r.store.insertTask(sub); err != nil {
+				return nil, err
+			}
+			created = append(created, sub)
+		}
+	}
+
+	for _, task := range created {
+		r.store.record(model.NewActivity(task, by, model.ActivityCreated))
+	}
+
+	return created, nil
+}
@@ -0,0 +1,70 @@
+package teststore
+
+import (
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type UserRepository struct {
+	store *Store
+}
+
+func (r *UserRepository) Create(u *model.User) error {
+	if err := u.Validate(); err != nil {
+		return err
+	}
+
+	if err := u.BeforeCreate(); err != nil {
+		return err
+	}
+
+	u.ID = r.store.id()
+	cp := *u
+	r.store.users[u.ID] = &cp
+
+	return nil
+}
+
+func (r *UserRepository) Find(id int) (*model.User, error) {
+	u, ok := r.store.users[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	cp := *u
+	return &cp, nil
+}
+
+func (r *UserRepository) FindByEmail(email string) (*model.User, error) {
+	for _, id := range sortedIDs(r.store.users) {
+		if u := r.store.users[id]; u.Email == email {
+			cp := *u
+			return &cp, nil
+		}
+	}
+
+	return nil, store.ErrRecordNotFound
+}
+
+func (r *UserRepository) Update(u *model.User) error {
+	if err := u.BeforeCreate(); err != nil {
+		return err
+	}
+
+	old, ok := r.store.users[u.ID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+	old.EncryptedPassword = u.EncryptedPassword
+
+	return nil
+}
+
+func (r *UserRepository) Delete(id int) error {
+	delete(r.store.users, id)
+	for _, members := range r.store.members {
+		delete(members, id)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package teststore
+
+import (
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type WorkflowRepository struct {
+	store *Store
+}
+
+func (r *WorkflowRepository) Get(teamID int) (*model.Workflow, error) {
+	w, ok := r.store.workflows[teamID]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	cp := *w
+	return &cp, nil
+}
+
+func (r *WorkflowRepository) Save(w *model.Workflow) error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+
+	for _, t := range r.store.tasks {
+		if _, archived := r.store.archived[t.ID]; t.TeamID == w.TeamID && !archived && w.Status(t.Status) == nil {
+			return model.ErrWorkflowStatusInUse
+		}
+	}
+
+	cp := *w
+	r.store.workflows[w.TeamID] = &cp
+
+	return nil
+}
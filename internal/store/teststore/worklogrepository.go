@@ -0,0 +1,100 @@
+package teststore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type WorklogRepository struct {
+	store *Store
+}
+
+func (r *WorklogRepository) Create(w *model.Worklog) error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+
+	w.ID = r.store.id()
+	w.CreatedAt = time.Now()
+	w.UpdatedAt = w.CreatedAt
+
+	cp := *w
+	r.store.worklogs[w.ID] = &cp
+
+	return nil
+}
+
+func (r *WorklogRepository) Find(id int) (*model.Worklog, error) {
+	w, ok := r.store.worklogs[id]
+	if !ok {
+		return nil, store.ErrRecordNotFound
+	}
+
+	return r.view(w), nil
+}
+
+func (r *WorklogRepository) view(w *model.Worklog) *model.Worklog {
+	cp := *w
+	if t, ok := r.store.tasks[w.TaskID]; ok {
+		cp.TaskName = t.Name
+	}
+	return &cp
+}
+
+func (r *WorklogRepository) list(keep func(w *model.Worklog) bool) []*model.Worklog {
+	worklogs := []*model.Worklog{}
+	for _, id := range sortedIDs(r.store.worklogs) {
+		if w := r.store.worklogs[id]; keep(w) {
+			worklogs = append(worklogs, r.view(w))
+		}
+	}
+	sort.SliceStable(worklogs, func(i, j int) bool { return worklogs[i].WorkDate.Before(worklogs[j].WorkDate) })
+
+	return worklogs
+}
+
+func (r *WorklogRepository) ListByTask(taskID int) ([]*model.Worklog, error) {
+	return r.list(func(w *model.Worklog) bool { return w.TaskID == taskID }), nil
+}
+
+func (r *WorklogRepository) Update(w *model.Worklog) error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+
+	old, ok := r.store.worklogs[w.ID]
+	if !ok {
+		return store.ErrRecordNotFound
+	}
+
+	w.UpdatedAt = time.Now()
+	old.Minutes = w.Minutes
+	old.Note = w.Note
+	old.WorkDate = w.WorkDate
+	old.UpdatedAt = w.UpdatedAt
+
+	return nil
+}
+
+func (r *WorklogRepository) Delete(id int) error {
+	if _, ok := r.store.worklogs[id]; !ok {
+		return store.ErrRecordNotFound
+	}
+
+	delete(r.store.worklogs, id)
+	return nil
+}
+
+func (r *WorklogRepository) Timesheet(userID int, teamID *int, from time.Time, to time.Time) (*model.Timesheet, error) {
+	entries := r.list(func(w *model.Worklog) bool {
+		t, ok := r.store.tasks[w.TaskID]
+		return ok && w.UserID == userID &&
+			(teamID == nil || t.TeamID == *teamID) &&
+			!w.WorkDate.Before(from) && !w.WorkDate.After(to)
+	})
+
+	return model.NewTimesheet(userID, entries), nil
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type ActivityHandlers struct {
+	Store  store.Store
+	Policy *policy.Policy
+}
+
+func (s *ActivityHandlers) HandleTaskHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermViewTeam); !authorized(w, r, err) {
+			return
+		}
+
+		filter, err := parseActivityFilter(r)
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+		filter.TaskID = &taskID
+
+		s.respondActivityPage(w, r, filter)
+	}
+}
+
+func (s *ActivityHandlers) HandleTeamActivity() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermViewTeam); !authorized(w, r, err) {
+			return
+		}
+
+		filter, err := parseActivityFilter(r)
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+		filter.TeamID = &teamID
+
+		s.respondActivityPage(w, r, filter)
+	}
+}
+
+func (s *ActivityHandlers) respondActivityPage(w http.ResponseWriter, r *http.Request, filter *store.ActivityFilter) {
+	page, err := s.Store.Activity().List(filter)
+	if err != nil {
+		if err == store.ErrInvalidCursor {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+		utils.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.Respond(w, r, http.StatusOK, page)
+}
+
+func parseActivityFilter(r *http.Request) (*store.ActivityFilter, error) {
+	q := r.URL.Query()
+	f := &store.ActivityFilter{
+		Cursor: q.Get("cursor"),
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return nil, invalidParam("limit")
+		}
+		f.Limit = limit
+	}
+
+	return f, nil
+}
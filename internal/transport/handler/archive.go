@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type ArchiveHandlers struct {
+	Store  store.Store
+	Policy *policy.Policy
+}
+
+func (s *ArchiveHandlers) HandleTaskAbandon() http.HandlerFunc {
+	type request struct {
+		Reason string `json:"reason"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermEditTasks); !authorized(w, r, err) {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := s.Store.Archive().Abandon(taskID, req.Reason, actorID(r)); err != nil {
+			if err == store.ErrRecordNotFound {
+				utils.Error(w, r, http.StatusNotFound, errors.ErrTaskNotFound)
+				return
+			}
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, nil)
+	}
+}
+
+func (s *ArchiveHandlers) HandleTeamAbandonedList() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermViewTeam); !authorized(w, r, err) {
+			return
+		}
+
+		tasks, err := s.Store.Archive().ListByTeam(teamID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, tasks)
+	}
+}
+
+func (s *ArchiveHandlers) HandleTeamAbandonedRestore() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		teamID, err := strconv.Atoi(vars["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		taskID, err := strconv.Atoi(vars["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermEditTasks); !authorized(w, r, err) {
+			return
+		}
+
+		task, err := s.Store.Archive().Restore(teamID, taskID, actorID(r))
+		if err != nil {
+			if err == store.ErrRecordNotFound {
+				utils.Error(w, r, http.StatusNotFound, errors.ErrTaskNotFound)
+				return
+			}
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, task)
+	}
+}
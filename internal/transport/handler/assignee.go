@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type AssigneeHandlers struct {
+	Store  store.Store
+	Policy *policy.Policy
+}
+
+func (s *AssigneeHandlers) HandleTaskAssigneeAdd() http.HandlerFunc {
+	type request struct {
+		UserID int `json:"user_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermEditTasks); !authorized(w, r, err) {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := s.Store.Task().AddAssignee(taskID, req.UserID, actorID(r)); err != nil {
+			assigneeError(w, r, err)
+			return
+		}
+
+		s.respondTask(w, r, taskID)
+	}
+}
+
+func (s *AssigneeHandlers) HandleTaskAssigneeRemove() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, userID, ok := taskUserVars(w, r)
+		if !ok {
+			return
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermEditTasks); !authorized(w, r, err) {
+			return
+		}
+
+		if err := s.Store.Task().RemoveAssignee(taskID, userID, actorID(r)); err != nil {
+			assigneeError(w, r, err)
+			return
+		}
+
+		s.respondTask(w, r, taskID)
+	}
+}
+
+// HandleTaskWatch subscribes the caller, or with user_id another team
+// member, to the task.
+func (s *AssigneeHandlers) HandleTaskWatch() http.HandlerFunc {
+	type request struct {
+		UserID *int `json:"user_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		req := &request{}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+				utils.Error(w, r, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		userID := currentUser(r).ID
+		if req.UserID != nil {
+			userID = *req.UserID
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, watchPermission(r, userID)); !authorized(w, r, err) {
+			return
+		}
+
+		if err := s.Store.Task().Watch(taskID, userID); err != nil {
+			assigneeError(w, r, err)
+			return
+		}
+
+		s.respondTask(w, r, taskID)
+	}
+}
+
+func (s *AssigneeHandlers) HandleTaskUnwatch() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, userID, ok := taskUserVars(w, r)
+		if !ok {
+			return
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, watchPermission(r, userID)); !authorized(w, r, err) {
+			return
+		}
+
+		if err := s.Store.Task().Unwatch(taskID, userID); err != nil {
+			assigneeError(w, r, err)
+			return
+		}
+
+		s.respondTask(w, r, taskID)
+	}
+}
+
+// watchPermission lets every team member follow a task themselves; adding
+// or removing someone else takes the right to edit tasks.
+func watchPermission(r *http.Request, userID int) model.Permission {
+	if userID == currentUser(r).ID {
+		return model.PermViewTeam
+	}
+	return model.PermEditTasks
+}
+
+func (s *AssigneeHandlers) respondTask(w http.ResponseWriter, r *http.Request, taskID int) {
+	task, err := s.Store.Task().GetByID(taskID)
+	if err != nil {
+		utils.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.Respond(w, r, http.StatusOK, task)
+}
+
+func taskUserVars(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	vars := mux.Vars(r)
+
+	taskID, err := strconv.Atoi(vars["task_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err)
+		return 0, 0, false
+	}
+
+	userID, err := strconv.Atoi(vars["user_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err)
+		return 0, 0, false
+	}
+
+	return taskID, userID, true
+}
+
+func assigneeError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case store.ErrUserNotInTeam:
+		utils.Error(w, r, http.StatusUnprocessableEntity, err)
+	case store.ErrRecordNotFound:
+		utils.Error(w, r, http.StatusNotFound, err)
+	default:
+		utils.Error(w, r, http.StatusInternalServerError, err)
+	}
+}
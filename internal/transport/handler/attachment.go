@@ -0,0 +1,309 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/qeery8/rest/internal/app/blob"
+	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+// multipartOverhead is what boundaries and part headers may add on top of
+// the file sizes.
+const multipartOverhead = 1 << 20
+
+type AttachmentHandlers struct {
+	Store        store.Store
+	Policy       *policy.Policy
+	Storage      blob.Storage
+	MaxSize      int64
+	AllowedTypes []string
+}
+
+func (s *AttachmentHandlers) HandleAttachmentList() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermViewTeam); !authorized(w, r, err) {
+			return
+		}
+
+		attachments, err := s.Store.Attachment().ListByTask(taskID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, attachments)
+	}
+}
+
+// HandleAttachmentUpload stores every "file" part of a multipart/form-data
+// body; together they must fit the size limit. The type is sniffed from
+// the contents rather than trusted from the client. If one file is
+// rejected, the ones before it are removed again.
+func (s *AttachmentHandlers) HandleAttachmentUpload() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermEditTasks); !authorized(w, r, err) {
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, s.MaxSize+multipartOverhead)
+		mr, err := r.MultipartReader()
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		created := []*model.Attachment{}
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				s.discard(r, created)
+				uploadError(w, r, &readError{err})
+				return
+			}
+
+			if part.FormName() != "file" || part.FileName() == "" {
+				part.Close()
+				continue
+			}
+
+			a, err := s.save(r, taskID, part)
+			part.Close()
+			if err != nil {
+				s.discard(r, created)
+				uploadError(w, r, err)
+				return
+			}
+			created = append(created, a)
+		}
+
+		if len(created) == 0 {
+			utils.Error(w, r, http.StatusUnprocessableEntity, model.ErrAttachmentEmpty)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusCreated, created)
+	}
+}
+
+// HandleAttachmentDownload streams the file with its stored type and name.
+func (s *AttachmentHandlers) HandleAttachmentDownload() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		a, ok := s.loadAttachment(w, r, model.PermViewTeam)
+		if !ok {
+			return
+		}
+
+		body, err := s.Storage.Get(r.Context(), a.StorageKey)
+		if err != nil {
+			if err == blob.ErrNotFound {
+				utils.Error(w, r, http.StatusNotFound, errors.ErrAttachmentNotFound)
+				return
+			}
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		defer body.Close()
+
+		h := w.Header()
+		h.Set("Content-Type", a.ContentType)
+		h.Set("Content-Length", strconv.FormatInt(a.Size, 10))
+		h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.FileName}))
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("Cache-Control", "private, max-age=0")
+		w.WriteHeader(http.StatusOK)
+
+		// заголовки уже отправлены, оборванную передачу клиент увидит по длине
+		io.Copy(w, body)
+	}
+}
+
+func (s *AttachmentHandlers) HandleAttachmentDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		a, ok := s.loadAttachment(w, r, model.PermEditTasks)
+		if !ok {
+			return
+		}
+
+		// сначала строка: без нее вложение пропадает из списка целиком,
+		// а не остается ссылкой на удаленный файл
+		if err := s.Store.Attachment().Delete(a.ID); err != nil {
+			if err == store.ErrRecordNotFound {
+				utils.Error(w, r, http.StatusNotFound, errors.ErrAttachmentNotFound)
+				return
+			}
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if err := s.Storage.Delete(r.Context(), a.StorageKey); err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, nil)
+	}
+}
+
+// save spools one part to a temporary file, which gives the size the blob
+// storage needs and the first bytes to detect the type from.
+func (s *AttachmentHandlers) save(r *http.Request, taskID int, part *multipart.Part) (*model.Attachment, error) {
+	tmp, err := os.CreateTemp("", "attachment-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, io.LimitReader(part, s.MaxSize+1))
+	if err != nil {
+		return nil, &readError{err}
+	}
+	if size > s.MaxSize {
+		return nil, model.ErrAttachmentTooLarge
+	}
+	if size == 0 {
+		return nil, model.ErrAttachmentEmpty
+	}
+
+	head := make([]byte, 512)
+	n, err := tmp.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	contentType := http.DetectContentType(head[:n])
+	if !model.TypeAllowed(contentType, s.AllowedTypes) {
+		return nil, model.ErrAttachmentType
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	key, err := attachmentKey(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Storage.Put(r.Context(), key, tmp, size, contentType); err != nil {
+		return nil, err
+	}
+
+	a := &model.Attachment{
+		TaskID:      taskID,
+		StorageKey:  key,
+		FileName:    model.CleanFileName(part.FileName()),
+		ContentType: contentType,
+		Size:        size,
+		UploadedBy:  actorID(r),
+	}
+	if err := s.Store.Attachment().Create(a); err != nil {
+		s.Storage.Delete(r.Context(), key)
+		return nil, err
+	}
+	return a, nil
+}
+
+// discard removes what a failed upload request had already stored.
+func (s *AttachmentHandlers) discard(r *http.Request, created []*model.Attachment) {
+	for _, a := range created {
+		s.Store.Attachment().Delete(a.ID)
+		s.Storage.Delete(r.Context(), a.StorageKey)
+	}
+}
+
+func (s *AttachmentHandlers) loadAttachment(w http.ResponseWriter, r *http.Request, perm model.Permission) (*model.Attachment, bool) {
+	vars := mux.Vars(r)
+
+	taskID, err := strconv.Atoi(vars["task_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err)
+		return nil, false
+	}
+
+	attachmentID, err := strconv.Atoi(vars["attachment_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err)
+		return nil, false
+	}
+
+	if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, perm); !authorized(w, r, err) {
+		return nil, false
+	}
+
+	a, err := s.Store.Attachment().Find(attachmentID)
+	if err != nil || a.TaskID != taskID {
+		utils.Error(w, r, http.StatusNotFound, errors.ErrAttachmentNotFound)
+		return nil, false
+	}
+
+	return a, true
+}
+
+func attachmentKey(taskID int) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tasks/%d/%s", taskID, hex.EncodeToString(b)), nil
+}
+
+// readError is a failure to read the request body: a broken or oversized
+// upload rather than a fault of the server.
+type readError struct {
+	err error
+}
+
+func (e *readError) Error() string {
+	return e.err.Error()
+}
+
+func (e *readError) Unwrap() error {
+	return e.err
+}
+
+func uploadError(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		tooLarge *http.MaxBytesError
+		read     *readError
+	)
+	switch {
+	case err == model.ErrAttachmentTooLarge || goerrors.As(err, &tooLarge):
+		utils.Error(w, r, http.StatusRequestEntityTooLarge, model.ErrAttachmentTooLarge)
+	case err == model.ErrAttachmentType:
+		utils.Error(w, r, http.StatusUnsupportedMediaType, err)
+	case err == model.ErrAttachmentEmpty:
+		utils.Error(w, r, http.StatusUnprocessableEntity, err)
+	case goerrors.As(err, &read):
+		utils.Error(w, r, http.StatusBadRequest, err)
+	default:
+		utils.Error(w, r, http.StatusInternalServerError, err)
+	}
+}
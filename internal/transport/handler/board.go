@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type BoardHandlers struct {
+	Store  store.Store
+	Policy *policy.Policy
+}
+
+func (s *BoardHandlers) HandleBoard() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermViewTeam); !authorized(w, r, err) {
+			return
+		}
+
+		board, err := s.Store.Task().Board(teamID)
+		if err != nil {
+			if err == store.ErrRecordNotFound {
+				utils.Error(w, r, http.StatusNotFound, err)
+				return
+			}
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, board)
+	}
+}
+
+func (s *BoardHandlers) HandleTaskMove() http.HandlerFunc {
+	type request struct {
+		Status   model.TaskStatus `json:"status"`
+		BeforeID *int             `json:"before_id"`
+		AfterID  *int             `json:"after_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		task, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermEditTasks)
+		if !authorized(w, r, err) {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if req.Status == "" {
+			req.Status = task.Status
+		}
+
+		if err := s.Store.Task().Move(taskID, req.Status, req.BeforeID, req.AfterID, actorID(r)); err != nil {
+			if err == store.ErrRecordNotFound {
+				utils.Error(w, r, http.StatusNotFound, errors.ErrTaskNotFound)
+				return
+			}
+			utils.Error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		moved, err := s.Store.Task().GetByID(taskID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, moved)
+	}
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type BulkHandlers struct {
+	Store  store.Store
+	Policy *policy.Policy
+}
+
+const (
+	bulkAllOrNothing = "all_or_nothing"
+	bulkBestEffort   = "best_effort"
+)
+
+// HandleTaskBulk applies one action to many tasks. In all_or_nothing mode,
+// the default, nothing is changed unless every task succeeds and the
+// response is 422 otherwise; best_effort keeps what succeeded. Either way
+// the results list every task in request order.
+func (s *BulkHandlers) HandleTaskBulk() http.HandlerFunc {
+	type request struct {
+		TaskIDs []int  `json:"task_ids"`
+		Mode    string `json:"mode"`
+		model.BulkOperation
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		op := &req.BulkOperation
+		if err := op.Validate(); err != nil {
+			utils.Error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		var atomic bool
+		switch req.Mode {
+		case "", bulkAllOrNothing:
+			atomic = true
+		case bulkBestEffort:
+		default:
+			utils.Error(w, r, http.StatusUnprocessableEntity, invalidParam("mode"))
+			return
+		}
+
+		ids := uniqueIDs(req.TaskIDs)
+		if len(ids) == 0 || len(ids) > model.MaxBulkTasks {
+			utils.Error(w, r, http.StatusUnprocessableEntity, model.ErrBulkTasks)
+			return
+		}
+
+		// права проверяем до транзакции, задачи без прав сразу попадают в ошибки
+		results := make(map[int]*model.BulkResult, len(ids))
+		var allowed []int
+		for _, id := range ids {
+			if _, err := s.Policy.AuthorizeTask(currentUser(r), id, op.Permission()); err != nil {
+				results[id] = &model.BulkResult{TaskID: id, Error: err.Error()}
+				continue
+			}
+			allowed = append(allowed, id)
+		}
+
+		if atomic && len(allowed) < len(ids) {
+			for _, id := range allowed {
+				results[id] = &model.BulkResult{TaskID: id, Error: model.ErrBulkRolledBack.Error()}
+			}
+			utils.Respond(w, r, http.StatusUnprocessableEntity, model.NewBulkReport(atomic, ordered(ids, results)))
+			return
+		}
+
+		if len(allowed) > 0 {
+			applied, err := s.Store.Task().Bulk(op, allowed, atomic, actorID(r))
+			if err != nil {
+				utils.Error(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			for _, res := range applied {
+				results[res.TaskID] = res
+			}
+		}
+
+		report := model.NewBulkReport(atomic, ordered(ids, results))
+
+		status := http.StatusOK
+		if atomic && report.Failed > 0 {
+			status = http.StatusUnprocessableEntity
+		}
+		utils.Respond(w, r, status, report)
+	}
+}
+
+func uniqueIDs(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	out := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func ordered(ids []int, results map[int]*model.BulkResult) []*model.BulkResult {
+	out := make([]*model.BulkResult, len(ids))
+	for i, id := range ids {
+		out[i] = results[id]
+	}
+	return out
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type ChecklistHandlers struct {
+	Store  store.Store
+	Policy *policy.Policy
+}
+
+func (s *ChecklistHandlers) HandleChecklistList() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermViewTeam); !authorized(w, r, err) {
+			return
+		}
+
+		items, err := s.Store.Checklist().ListByTask(taskID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, items)
+	}
+}
+
+func (s *ChecklistHandlers) HandleChecklistCreate() http.HandlerFunc {
+	type request struct {
+		Title string `json:"title"`
+		Done  bool   `json:"done"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermEditTasks); !authorized(w, r, err) {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		item := &model.ChecklistItem{
+			TaskID: taskID,
+			Title:  req.Title,
+			Done:   req.Done,
+		}
+
+		if err := s.Store.Checklist().Create(item); err != nil {
+			utils.Error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusCreated, item)
+	}
+}
+
+func (s *ChecklistHandlers) HandleChecklistUpdate() http.HandlerFunc {
+	type request struct {
+		Title    *string `json:"title"`
+		Done     *bool   `json:"done"`
+		Position *int    `json:"position"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		item, ok := s.loadChecklistItem(w, r)
+		if !ok {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if req.Title != nil {
+			item.Title = *req.Title
+		}
+		if req.Done != nil {
+			item.Done = *req.Done
+		}
+		if req.Position != nil {
+			item.Position = *req.Position
+		}
+
+		if err := s.Store.Checklist().Update(item); err != nil {
+			utils.Error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, item)
+	}
+}
+
+func (s *ChecklistHandlers) HandleChecklistDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		item, ok := s.loadChecklistItem(w, r)
+		if !ok {
+			return
+		}
+
+		if err := s.Store.Checklist().Delete(item.ID); err != nil {
+			utils.Error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, nil)
+	}
+}
+
+func (s *ChecklistHandlers) loadChecklistItem(w http.ResponseWriter, r *http.Request) (*model.ChecklistItem, bool) {
+	vars := mux.Vars(r)
+
+	taskID, err := strconv.Atoi(vars["task_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err)
+		return nil, false
+	}
+
+	itemID, err := strconv.Atoi(vars["item_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err)
+		return nil, false
+	}
+
+	if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermEditTasks); !authorized(w, r, err) {
+		return nil, false
+	}
+
+	item, err := s.Store.Checklist().Find(itemID)
+	if err != nil || item.TaskID != taskID {
+		utils.Error(w, r, http.StatusNotFound, errors.ErrChecklistItemNotFound)
+		return nil, false
+	}
+
+	return item, true
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/app/notify"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type CommentHandlers struct {
+	Store    store.Store
+	Policy   *policy.Policy
+	Notifier notify.Notifier
+}
+
+func (s *CommentHandlers) HandleCommentList() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermViewTeam); !authorized(w, r, err) {
+			return
+		}
+
+		comments, err := s.Store.Comment().ListByTask(taskID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, model.BuildThreads(comments))
+	}
+}
+
+func (s *CommentHandlers) HandleCommentCreate() http.HandlerFunc {
+	type request struct {
+		Body     string `json:"body"`
+		ParentID *int   `json:"parent_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		task, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermComment)
+		if !authorized(w, r, err) {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		authorID := currentUser(r).ID
+		c := &model.Comment{
+			TaskID:   taskID,
+			AuthorID: &authorID,
+			ParentID: req.ParentID,
+			Body:     req.Body,
+		}
+
+		mentioned, err := s.resolveMentions(task.TeamID, c)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if err := s.Store.Comment().Create(c); err != nil {
+			if err == store.ErrParentCommentNotFound {
+				utils.Error(w, r, http.StatusNotFound, err)
+				return
+			}
+			utils.Error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		s.notifyMentions(r, task, c, mentioned)
+
+		utils.Respond(w, r, http.StatusCreated, c)
+	}
+}
+
+func (s *CommentHandlers) HandleCommentUpdate() http.HandlerFunc {
+	type request struct {
+		Body string `json:"body"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		task, c, ok := s.loadComment(w, r, model.PermComment)
+		if !ok {
+			return
+		}
+
+		if c.AuthorID == nil || *c.AuthorID != currentUser(r).ID {
+			utils.Error(w, r, http.StatusForbidden, errors.ErrForbidden)
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		already := make(map[int]bool, len(c.Mentions))
+		for _, id := range c.Mentions {
+			already[id] = true
+		}
+
+		c.Body = req.Body
+		mentioned, err := s.resolveMentions(task.TeamID, c)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if err := s.Store.Comment().Update(c, currentUser(r).ID); err != nil {
+			utils.Error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		// уведомляем только тех, кого упомянули в новой версии впервые
+		var fresh []*model.TeamMember
+		for _, m := range mentioned {
+			if !already[m.UserID] {
+				fresh = append(fresh, m)
+			}
+		}
+		s.notifyMentions(r, task, c, fresh)
+
+		utils.Respond(w, r, http.StatusOK, c)
+	}
+}
+
+func (s *CommentHandlers) HandleCommentDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		task, c, ok := s.loadComment(w, r, model.PermViewTeam)
+		if !ok {
+			return
+		}
+
+		// свой комментарий удалить можно всегда, чужой - только с правом удалять задачи
+		if c.AuthorID == nil || *c.AuthorID != currentUser(r).ID {
+			if _, err := s.Policy.Authorize(currentUser(r), task.TeamID, model.PermDeleteTasks); !authorized(w, r, err) {
+				return
+			}
+		}
+
+		if err := s.Store.Comment().Delete(c.ID); err != nil {
+			utils.Error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, nil)
+	}
+}
+
+func (s *CommentHandlers) HandleCommentHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, c, ok := s.loadComment(w, r, model.PermViewTeam)
+		if !ok {
+			return
+		}
+
+		revisions, err := s.Store.Comment().Revisions(c.ID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, revisions)
+	}
+}
+
+// loadComment resolves task_id and comment_id from the route, checks perm on
+// the task's team and makes sure the comment belongs to the task.
+func (s *CommentHandlers) loadComment(w http.ResponseWriter, r *http.Request, perm model.Permission) (*model.Task, *model.Comment, bool) {
+	vars := mux.Vars(r)
+
+	taskID, err := strconv.Atoi(vars["task_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err)
+		return nil, nil, false
+	}
+
+	commentID, err := strconv.Atoi(vars["comment_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err)
+		return nil, nil, false
+	}
+
+	task, err := s.Policy.AuthorizeTask(currentUser(r), taskID, perm)
+	if !authorized(w, r, err) {
+		return nil, nil, false
+	}
+
+	c, err := s.Store.Comment().Find(commentID)
+	if err != nil || c.TaskID != taskID {
+		utils.Error(w, r, http.StatusNotFound, errors.ErrCommentNotFound)
+		return nil, nil, false
+	}
+
+	return task, c, true
+}
+
+// resolveMentions keeps only mentions of team members and fills c.Mentions.
+func (s *CommentHandlers) resolveMentions(teamID int, c *model.Comment) ([]*model.TeamMember, error) {
+	emails := c.MentionedEmails()
+	c.Mentions = []int{}
+	if len(emails) == 0 {
+		return nil, nil
+	}
+
+	members, err := s.Store.Team().Members(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	byEmail := make(map[string]*model.TeamMember, len(members))
+	for _, m := range members {
+		byEmail[strings.ToLower(m.Email)] = m
+	}
+
+	var mentioned []*model.TeamMember
+	for _, email := range emails {
+		if m, ok := byEmail[email]; ok {
+			mentioned = append(mentioned, m)
+			c.Mentions = append(c.Mentions, m.UserID)
+		}
+	}
+	return mentioned, nil
+}
+
+func (s *CommentHandlers) notifyMentions(r *http.Request, task *model.Task, c *model.Comment, mentioned []*model.TeamMember) {
+	author := currentUser(r)
+	for _, m := range mentioned {
+		if m.UserID == author.ID {
+			continue
+		}
+		s.Notifier.Notify(r.Context(), &notify.Notification{
+			Kind:    "mention",
+			To:      m.Email,
+			Subject: fmt.Sprintf("%s mentioned you on %q", author.Email, task.Name),
+			Body:    c.Body,
+			TaskID:  task.ID,
+			SentAt:  time.Now(),
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type FieldHandlers struct {
+	Store  store.Store
+	Policy *policy.Policy
+}
+
+func (s *FieldHandlers) HandleFieldList() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermViewTeam); !authorized(w, r, err) {
+			return
+		}
+
+		fields, err := s.Store.CustomField().ListByTeam(teamID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, fields)
+	}
+}
+
+func (s *FieldHandlers) HandleFieldCreate() http.HandlerFunc {
+	type request struct {
+		Key      string                `json:"key"`
+		Name     string                `json:"name"`
+		Type     model.CustomFieldType `json:"type"`
+		Options  []string              `json:"options"`
+		Position int                   `json:"position"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermUpdateTeam); !authorized(w, r, err) {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		f := &model.CustomField{
+			TeamID:   teamID,
+			Key:      req.Key,
+			Name:     req.Name,
+			Type:     req.Type,
+			Options:  req.Options,
+			Position: req.Position,
+		}
+
+		if err := s.Store.CustomField().Create(f); err != nil {
+			fieldError(w, r, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusCreated, f)
+	}
+}
+
+func (s *FieldHandlers) HandleFieldUpdate() http.HandlerFunc {
+	type request struct {
+		Key      *string   `json:"key"`
+		Name     *string   `json:"name"`
+		Options  *[]string `json:"options"`
+		Position *int      `json:"position"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, ok := s.loadField(w, r)
+		if !ok {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if req.Key != nil {
+			f.Key = *req.Key
+		}
+		if req.Name != nil {
+			f.Name = *req.Name
+		}
+		if req.Options != nil {
+			f.Options = *req.Options
+		}
+		if req.Position != nil {
+			f.Position = *req.Position
+		}
+
+		if err := s.Store.CustomField().Update(f); err != nil {
+			fieldError(w, r, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, f)
+	}
+}
+
+func (s *FieldHandlers) HandleFieldDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, ok := s.loadField(w, r)
+		if !ok {
+			return
+		}
+
+		if err := s.Store.CustomField().Delete(f.ID); err != nil {
+			fieldError(w, r, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, nil)
+	}
+}
+
+// HandleTaskFieldsSet takes an object of field keys to values. Fields not
+// mentioned keep their values; null clears a field.
+func (s *FieldHandlers) HandleTaskFieldsSet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		task, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermEditTasks)
+		if !authorized(w, r, err) {
+			return
+		}
+
+		req := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		fields, err := s.Store.CustomField().ListByTeam(task.TeamID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		byKey := make(map[string]*model.CustomField, len(fields))
+		for _, f := range fields {
+			byKey[f.Key] = f
+		}
+
+		var (
+			values []*model.FieldValue
+			clear  []int
+		)
+		for key, raw := range req {
+			f, ok := byKey[key]
+			if !ok {
+				utils.Error(w, r, http.StatusUnprocessableEntity, model.ErrFieldUnknown)
+				return
+			}
+
+			v, err := f.Parse(raw)
+			if err != nil {
+				utils.Error(w, r, http.StatusUnprocessableEntity, err)
+				return
+			}
+			if v == nil {
+				clear = append(clear, f.ID)
+			} else {
+				values = append(values, v)
+			}
+		}
+
+		if err := s.Store.CustomField().SetValues(taskID, values, clear, actorID(r)); err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		updated, err := s.Store.Task().GetByID(taskID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, updated)
+	}
+}
+
+func (s *FieldHandlers) loadField(w http.ResponseWriter, r *http.Request) (*model.CustomField, bool) {
+	vars := mux.Vars(r)
+
+	teamID, err := strconv.Atoi(vars["team_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+		return nil, false
+	}
+
+	fieldID, err := strconv.Atoi(vars["field_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err)
+		return nil, false
+	}
+
+	if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermUpdateTeam); !authorized(w, r, err) {
+		return nil, false
+	}
+
+	f, err := s.Store.CustomField().Find(fieldID)
+	if err != nil || f.TeamID != teamID {
+		utils.Error(w, r, http.StatusNotFound, errors.ErrFieldNotFound)
+		return nil, false
+	}
+
+	return f, true
+}
+
+func fieldError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case store.ErrRecordNotFound:
+		utils.Error(w, r, http.StatusNotFound, errors.ErrFieldNotFound)
+	case model.ErrFieldExists, model.ErrFieldOptionInUse:
+		utils.Error(w, r, http.StatusConflict, err)
+	default:
+		utils.Error(w, r, http.StatusUnprocessableEntity, err)
+	}
+}
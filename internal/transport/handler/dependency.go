@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type DependencyHandlers struct {
+	Store  store.Store
+	Policy *policy.Policy
+}
+
+func (s *DependencyHandlers) HandleDependencyList() http.HandlerFunc {
+	type response struct {
+		BlockedBy []*model.Task `json:"blocked_by"`
+		Blocks    []*model.Task `json:"blocks"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermViewTeam); !authorized(w, r, err) {
+			return
+		}
+
+		resp := &response{
+			BlockedBy: []*model.Task{},
+			Blocks:    []*model.Task{},
+		}
+
+		blockers, err := s.Store.Dependency().Blockers(taskID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		resp.BlockedBy = append(resp.BlockedBy, blockers...)
+
+		blocking, err := s.Store.Dependency().Blocking(taskID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		resp.Blocks = append(resp.Blocks, blocking...)
+
+		utils.Respond(w, r, http.StatusOK, resp)
+	}
+}
+
+func (s *DependencyHandlers) HandleDependencyAdd() http.HandlerFunc {
+	type request struct {
+		BlockerID int `json:"blocker_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermEditTasks); !authorized(w, r, err) {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := s.Store.Dependency().Add(req.BlockerID, taskID, actorID(r)); err != nil {
+			switch err {
+			case store.ErrRecordNotFound:
+				utils.Error(w, r, http.StatusNotFound, err)
+			case store.ErrAlreadyLinked:
+				utils.Error(w, r, http.StatusConflict, err)
+			default:
+				utils.Error(w, r, http.StatusUnprocessableEntity, err)
+			}
+			return
+		}
+
+		task, err := s.Store.Task().GetByID(taskID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusCreated, task)
+	}
+}
+
+func (s *DependencyHandlers) HandleDependencyRemove() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		taskID, err := strconv.Atoi(vars["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		blockerID, err := strconv.Atoi(vars["blocker_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermEditTasks); !authorized(w, r, err) {
+			return
+		}
+
+		if err := s.Store.Dependency().Remove(blockerID, taskID); err != nil {
+			utils.Error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, nil)
+	}
+}
@@ -1,7 +1,61 @@
 package handler
 
+import (
+	"net/http"
+
+	"github.com/qeery8/rest/internal/app/ctxkeys"
+	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+)
+
 type Handlers struct {
 	User UserHandlers
 	Team TeamHandlers
 	Task TaskHandlers
+
+	Invitation InvitationHandlers
+	Comment    CommentHandlers
+	Attachment AttachmentHandlers
+	Activity   ActivityHandlers
+	Archive    ArchiveHandlers
+	Assignee   AssigneeHandlers
+	Subtask    SubtaskHandlers
+	Checklist  ChecklistHandlers
+	Dependency DependencyHandlers
+	Workflow   WorkflowHandlers
+	Board      BoardHandlers
+	Sprint     SprintHandlers
+	Report     ReportHandlers
+	Worklog    WorklogHandlers
+	Label      LabelHandlers
+	Template   TemplateHandlers
+	Field      FieldHandlers
+	Search     SearchHandlers
+	Bulk       BulkHandlers
+}
+
+func currentUser(r *http.Request) *model.User {
+	return r.Context().Value(ctxkeys.CtxKeyUser).(*model.User)
+}
+
+func actorID(r *http.Request) *int {
+	id := currentUser(r).ID
+	return &id
+}
+
+// authorized writes the response for a failed policy check and reports
+// whether the handler may continue.
+func authorized(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch err {
+	case nil:
+		return true
+	case errors.ErrForbidden:
+		utils.Error(w, r, http.StatusForbidden, err)
+	case errors.ErrTeamNotFound, errors.ErrTaskNotFound:
+		utils.Error(w, r, http.StatusNotFound, err)
+	default:
+		utils.Error(w, r, http.StatusInternalServerError, err)
+	}
+	return false
 }
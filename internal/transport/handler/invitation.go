@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type InvitationHandlers struct {
+	Store  store.Store
+	Policy *policy.Policy
+}
+
+func (s *InvitationHandlers) HandleInvitationCreate() http.HandlerFunc {
+	type request struct {
+		Email string         `json:"email"`
+		Role  model.TeamRole `json:"role"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		actorRole, err := s.Policy.Authorize(currentUser(r), teamID, model.PermManageMembers)
+		if !authorized(w, r, err) {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if req.Role == "" {
+			req.Role = model.RoleMember
+		}
+		if req.Role.Valid() && !actorRole.Outranks(req.Role) {
+			utils.Error(w, r, http.StatusForbidden, errors.ErrForbidden)
+			return
+		}
+
+		if u, err := s.Store.User().FindByEmail(req.Email); err == nil {
+			if _, err := s.Store.Team().MemberRole(teamID, u.ID); err == nil {
+				utils.Error(w, r, http.StatusConflict, store.ErrAlreadyInTeam)
+				return
+			}
+		}
+
+		inviterID := currentUser(r).ID
+		inv := &model.Invitation{
+			TeamID:    teamID,
+			Email:     req.Email,
+			Role:      req.Role,
+			InvitedBy: &inviterID,
+		}
+
+		if err := s.Store.Invitation().Create(inv); err != nil {
+			if err == store.ErrAlreadyInvited {
+				utils.Error(w, r, http.StatusConflict, err)
+				return
+			}
+			utils.Error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusCreated, inv)
+	}
+}
+
+func (s *InvitationHandlers) HandleTeamInvitations() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermManageMembers); !authorized(w, r, err) {
+			return
+		}
+
+		invitations, err := s.Store.Invitation().ListPendingByTeam(teamID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, invitations)
+	}
+}
+
+func (s *InvitationHandlers) HandleMyInvitations() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		invitations, err := s.Store.Invitation().ListPendingByEmail(currentUser(r).Email)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, invitations)
+	}
+}
+
+func (s *InvitationHandlers) HandleInvitationAccept() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		inv, ok := s.openInvitation(w, r)
+		if !ok {
+			return
+		}
+
+		if err := s.Store.Invitation().Accept(inv.ID, currentUser(r).ID); err != nil {
+			if err == model.ErrInvitationNotPending {
+				utils.Error(w, r, http.StatusConflict, err)
+				return
+			}
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		team, err := s.Store.Team().Find(inv.TeamID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, team)
+	}
+}
+
+func (s *InvitationHandlers) HandleInvitationDecline() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		inv, ok := s.openInvitation(w, r)
+		if !ok {
+			return
+		}
+
+		if err := s.Store.Invitation().Respond(inv.ID, model.InvitationDeclined); err != nil {
+			if err == model.ErrInvitationNotPending {
+				utils.Error(w, r, http.StatusConflict, err)
+				return
+			}
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, nil)
+	}
+}
+
+// openInvitation loads the invitation from the route and makes sure it is
+// addressed to the caller and still pending.
+func (s *InvitationHandlers) openInvitation(w http.ResponseWriter, r *http.Request) (*model.Invitation, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["invitation_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err)
+		return nil, false
+	}
+
+	inv, err := s.Store.Invitation().Find(id)
+	if err != nil || !inv.For(currentUser(r)) {
+		utils.Error(w, r, http.StatusNotFound, errors.ErrInvitationNotFound)
+		return nil, false
+	}
+
+	if !inv.Open() {
+		utils.Error(w, r, http.StatusConflict, model.ErrInvitationNotPending)
+		return nil, false
+	}
+
+	return inv, true
+}
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type LabelHandlers struct {
+	Store  store.Store
+	Policy *policy.Policy
+}
+
+func (s *LabelHandlers) HandleLabelList() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermViewTeam); !authorized(w, r, err) {
+			return
+		}
+
+		labels, err := s.Store.Label().ListByTeam(teamID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, labels)
+	}
+}
+
+func (s *LabelHandlers) HandleLabelCreate() http.HandlerFunc {
+	type request struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermEditTasks); !authorized(w, r, err) {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		l := &model.Label{
+			TeamID: teamID,
+			Name:   req.Name,
+			Color:  req.Color,
+		}
+
+		if err := s.Store.Label().Create(l); err != nil {
+			labelError(w, r, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusCreated, l)
+	}
+}
+
+func (s *LabelHandlers) HandleLabelUpdate() http.HandlerFunc {
+	type request struct {
+		Name  *string `json:"name"`
+		Color *string `json:"color"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		l, ok := s.loadLabel(w, r)
+		if !ok {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if req.Name != nil {
+			l.Name = *req.Name
+		}
+		if req.Color != nil {
+			l.Color = *req.Color
+		}
+
+		if err := s.Store.Label().Update(l); err != nil {
+			labelError(w, r, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, l)
+	}
+}
+
+func (s *LabelHandlers) HandleLabelDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l, ok := s.loadLabel(w, r)
+		if !ok {
+			return
+		}
+
+		if err := s.Store.Label().Delete(l.ID); err != nil {
+			utils.Error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, nil)
+	}
+}
+
+func (s *LabelHandlers) HandleTaskLabelAttach() http.HandlerFunc {
+	type request struct {
+		LabelID int `json:"label_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermEditTasks); !authorized(w, r, err) {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := s.Store.Label().Attach(taskID, req.LabelID, actorID(r)); err != nil {
+			labelError(w, r, err)
+			return
+		}
+
+		task, err := s.Store.Task().GetByID(taskID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, task)
+	}
+}
+
+func (s *LabelHandlers) HandleTaskLabelDetach() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		taskID, err := strconv.Atoi(vars["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		labelID, err := strconv.Atoi(vars["label_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermEditTasks); !authorized(w, r, err) {
+			return
+		}
+
+		if err := s.Store.Label().Detach(taskID, labelID, actorID(r)); err != nil {
+			labelError(w, r, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, nil)
+	}
+}
+
+func (s *LabelHandlers) loadLabel(w http.ResponseWriter, r *http.Request) (*model.Label, bool) {
+	vars := mux.Vars(r)
+
+	teamID, err := strconv.Atoi(vars["team_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+		return nil, false
+	}
+
+	labelID, err := strconv.Atoi(vars["label_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err)
+		return nil, false
+	}
+
+	if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermEditTasks); !authorized(w, r, err) {
+		return nil, false
+	}
+
+	l, err := s.Store.Label().Find(labelID)
+	if err != nil || l.TeamID != teamID {
+		utils.Error(w, r, http.StatusNotFound, errors.ErrLabelNotFound)
+		return nil, false
+	}
+
+	return l, true
+}
+
+func labelError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case store.ErrRecordNotFound:
+		utils.Error(w, r, http.StatusNotFound, errors.ErrLabelNotFound)
+	case store.ErrLabelExists:
+		utils.Error(w, r, http.StatusConflict, err)
+	default:
+		utils.Error(w, r, http.StatusUnprocessableEntity, err)
+	}
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+)
+
+// HandleMyTasks groups the caller's open tasks across all their teams into
+// overdue, today, upcoming and no date. The optional tz parameter is an
+// IANA time zone such as Europe/Moscow that decides where today ends; UTC
+// by default.
+func (s *TaskHandlers) HandleMyTasks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		loc := time.UTC
+		if v := r.URL.Query().Get("tz"); v != "" {
+			var err error
+			if loc, err = time.LoadLocation(v); err != nil {
+				utils.Error(w, r, http.StatusBadRequest, invalidParam("tz"))
+				return
+			}
+		}
+
+		tasks, err := s.Store.Task().Involved(currentUser(r).ID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, model.NewMyWork(tasks, time.Now().In(loc)))
+	}
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type ReportHandlers struct {
+	Store  store.Store
+	Policy *policy.Policy
+}
+
+const (
+	defaultReportRange = 90 * 24 * time.Hour
+	maxReportRange     = 366 * 24 * time.Hour
+)
+
+type csvReport interface {
+	CSV() [][]string
+}
+
+func (s *ReportHandlers) HandleReportBurndown() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, ok := reportTeam(w, r, s.Policy)
+		if !ok {
+			return
+		}
+
+		var sprintID *int
+		from, to, err := reportRange(r)
+		if v := r.URL.Query().Get("sprint_id"); v != "" {
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				utils.Error(w, r, http.StatusBadRequest, invalidParam("sprint_id"))
+				return
+			}
+			sp, err := s.Store.Sprint().Find(id)
+			if err != nil || sp.TeamID != teamID {
+				utils.Error(w, r, http.StatusNotFound, errors.ErrSprintNotFound)
+				return
+			}
+			sprintID, from, to = &sp.ID, sp.StartDate, sp.EndDate
+		} else if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		report, err := s.Store.Report().Burndown(teamID, sprintID, from, to)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		respondReport(w, r, "burndown", report)
+	}
+}
+
+func (s *ReportHandlers) HandleReportCycleTime() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, ok := reportTeam(w, r, s.Policy)
+		if !ok {
+			return
+		}
+
+		from, to, err := reportRange(r)
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		report, err := s.Store.Report().CycleTime(teamID, from, to)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		respondReport(w, r, "cycle-time", report)
+	}
+}
+
+func (s *ReportHandlers) HandleReportThroughput() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, ok := reportTeam(w, r, s.Policy)
+		if !ok {
+			return
+		}
+
+		from, to, err := reportRange(r)
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		report, err := s.Store.Report().Throughput(teamID, from, to)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		respondReport(w, r, "throughput", report)
+	}
+}
+
+func (s *ReportHandlers) HandleReportWorkload() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, ok := reportTeam(w, r, s.Policy)
+		if !ok {
+			return
+		}
+
+		report, err := s.Store.Report().Workload(teamID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		respondReport(w, r, "workload", report)
+	}
+}
+
+// reportTeam reads the team a report is about and checks that the caller
+// may view it.
+func reportTeam(w http.ResponseWriter, r *http.Request, p *policy.Policy) (int, bool) {
+	teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+		return 0, false
+	}
+
+	if _, err := p.Authorize(currentUser(r), teamID, model.PermViewTeam); !authorized(w, r, err) {
+		return 0, false
+	}
+	return teamID, true
+}
+
+// reportRange reads from and to (RFC 3339 or YYYY-MM-DD), defaulting to the
+// last 90 days.
+func reportRange(r *http.Request) (time.Time, time.Time, error) {
+	q := r.URL.Query()
+
+	to := time.Now()
+	if v, err := timeParam(q.Get("to"), true); err != nil {
+		return time.Time{}, time.Time{}, invalidParam("to")
+	} else if v != nil {
+		to = *v
+	}
+
+	from := to.Add(-defaultReportRange)
+	if v, err := timeParam(q.Get("from"), false); err != nil {
+		return time.Time{}, time.Time{}, invalidParam("from")
+	} else if v != nil {
+		from = *v
+	}
+
+	if !from.Before(to) || to.Sub(from) > maxReportRange {
+		return time.Time{}, time.Time{}, errors.ErrReportRange
+	}
+	return from, to, nil
+}
+
+// respondReport writes the report as JSON, or as CSV when asked for with
+// format=csv or an Accept: text/csv header.
+func respondReport(w http.ResponseWriter, r *http.Request, name string, report csvReport) {
+	if r.URL.Query().Get("format") != "csv" && !strings.Contains(r.Header.Get("Accept"), "text/csv") {
+		utils.Respond(w, r, http.StatusOK, report)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	cw.WriteAll(report.CSV())
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type SearchHandlers struct {
+	Store store.Store
+}
+
+// HandleSearch looks for words in tasks, comments and teams of the caller:
+//
+//	q              search text, quotes for phrases, "-" to exclude a word
+//	type           task, comment or team, comma separated; all by default
+//	team_id        limit the search to one team
+//	limit, offset  page size and next_offset from the previous page
+func (s *SearchHandlers) HandleSearch() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		sq := &store.SearchQuery{
+			MemberID: currentUser(r).ID,
+			Text:     strings.TrimSpace(q.Get("q")),
+		}
+		if sq.Text == "" || len(sq.Text) > 200 {
+			utils.Error(w, r, http.StatusBadRequest, model.ErrSearchQuery)
+			return
+		}
+
+		for _, v := range listParam(q["type"]) {
+			k := model.SearchKind(v)
+			if !k.Valid() {
+				utils.Error(w, r, http.StatusBadRequest, invalidParam("type"))
+				return
+			}
+			sq.Kinds = append(sq.Kinds, k)
+		}
+
+		if v := q.Get("team_id"); v != "" {
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				utils.Error(w, r, http.StatusBadRequest, invalidParam("team_id"))
+				return
+			}
+			sq.TeamID = &id
+		}
+
+		var err error
+		if v := q.Get("limit"); v != "" {
+			if sq.Limit, err = strconv.Atoi(v); err != nil || sq.Limit < 1 {
+				utils.Error(w, r, http.StatusBadRequest, invalidParam("limit"))
+				return
+			}
+		}
+		if v := q.Get("offset"); v != "" {
+			if sq.Offset, err = strconv.Atoi(v); err != nil || sq.Offset < 0 {
+				utils.Error(w, r, http.StatusBadRequest, invalidParam("offset"))
+				return
+			}
+		}
+
+		result, err := s.Store.Search().Search(sq)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, result)
+	}
+}
@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type SprintHandlers struct {
+	Store  store.Store
+	Policy *policy.Policy
+}
+
+const dateLayout = "2006-01-02"
+
+type sprintRequest struct {
+	Name      *string `json:"name"`
+	Goal      *string `json:"goal"`
+	StartDate *string `json:"start_date"`
+	EndDate   *string `json:"end_date"`
+}
+
+// apply copies the fields present in the request onto sp. Dates are
+// YYYY-MM-DD.
+func (req *sprintRequest) apply(sp *model.Sprint) error {
+	if req.Name != nil {
+		sp.Name = *req.Name
+	}
+	if req.Goal != nil {
+		sp.Goal = *req.Goal
+	}
+	if req.StartDate != nil {
+		d, err := time.Parse(dateLayout, *req.StartDate)
+		if err != nil {
+			return invalidParam("start_date")
+		}
+		sp.StartDate = d
+	}
+	if req.EndDate != nil {
+		d, err := time.Parse(dateLayout, *req.EndDate)
+		if err != nil {
+			return invalidParam("end_date")
+		}
+		sp.EndDate = d
+	}
+	return nil
+}
+
+func (s *SprintHandlers) HandleSprintList() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermViewTeam); !authorized(w, r, err) {
+			return
+		}
+
+		sprints, err := s.Store.Sprint().ListByTeam(teamID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, sprints)
+	}
+}
+
+func (s *SprintHandlers) HandleSprintCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermUpdateTeam); !authorized(w, r, err) {
+			return
+		}
+
+		req := &sprintRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		sp := &model.Sprint{TeamID: teamID}
+		if err := req.apply(sp); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if req.StartDate == nil || req.EndDate == nil {
+			utils.Error(w, r, http.StatusUnprocessableEntity, model.ErrSprintDates)
+			return
+		}
+
+		if err := s.Store.Sprint().Create(sp); err != nil {
+			utils.Error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusCreated, sp)
+	}
+}
+
+func (s *SprintHandlers) HandleSprintGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sp, ok := s.loadSprint(w, r, model.PermViewTeam)
+		if !ok {
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, sp)
+	}
+}
+
+func (s *SprintHandlers) HandleSprintUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sp, ok := s.loadSprint(w, r, model.PermUpdateTeam)
+		if !ok {
+			return
+		}
+
+		req := &sprintRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if err := req.apply(sp); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := s.Store.Sprint().Update(sp); err != nil {
+			sprintError(w, r, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, sp)
+	}
+}
+
+func (s *SprintHandlers) HandleSprintStart() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sp, ok := s.loadSprint(w, r, model.PermUpdateTeam)
+		if !ok {
+			return
+		}
+
+		if err := s.Store.Sprint().Start(sp.ID); err != nil {
+			sprintError(w, r, err)
+			return
+		}
+
+		s.respondSprint(w, r, sp.ID)
+	}
+}
+
+func (s *SprintHandlers) HandleSprintClose() http.HandlerFunc {
+	type request struct {
+		CarryOverTo *int `json:"carry_over_to"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		sp, ok := s.loadSprint(w, r, model.PermUpdateTeam)
+		if !ok {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := s.Store.Sprint().Close(sp.ID, req.CarryOverTo, actorID(r)); err != nil {
+			sprintError(w, r, err)
+			return
+		}
+
+		s.respondSprint(w, r, sp.ID)
+	}
+}
+
+func (s *SprintHandlers) HandleSprintSummary() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sp, ok := s.loadSprint(w, r, model.PermViewTeam)
+		if !ok {
+			return
+		}
+
+		sum, err := s.Store.Sprint().Summary(sp.ID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, sum)
+	}
+}
+
+func (s *SprintHandlers) HandleSprintAddTasks() http.HandlerFunc {
+	type request struct {
+		TaskIDs []int `json:"task_ids"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		sp, ok := s.loadSprint(w, r, model.PermEditTasks)
+		if !ok {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := s.Store.Sprint().AddTasks(sp.ID, req.TaskIDs, actorID(r)); err != nil {
+			if err == store.ErrRecordNotFound {
+				utils.Error(w, r, http.StatusNotFound, errors.ErrTaskNotFound)
+				return
+			}
+			sprintError(w, r, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, nil)
+	}
+}
+
+func (s *SprintHandlers) HandleSprintRemoveTask() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sp, ok := s.loadSprint(w, r, model.PermEditTasks)
+		if !ok {
+			return
+		}
+
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := s.Store.Sprint().RemoveTask(sp.ID, taskID, actorID(r)); err != nil {
+			if err == store.ErrRecordNotFound {
+				utils.Error(w, r, http.StatusNotFound, errors.ErrTaskNotFound)
+				return
+			}
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, nil)
+	}
+}
+
+func (s *SprintHandlers) loadSprint(w http.ResponseWriter, r *http.Request, perm model.Permission) (*model.Sprint, bool) {
+	vars := mux.Vars(r)
+
+	teamID, err := strconv.Atoi(vars["team_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+		return nil, false
+	}
+
+	sprintID, err := strconv.Atoi(vars["sprint_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err)
+		return nil, false
+	}
+
+	if _, err := s.Policy.Authorize(currentUser(r), teamID, perm); !authorized(w, r, err) {
+		return nil, false
+	}
+
+	sp, err := s.Store.Sprint().Find(sprintID)
+	if err != nil || sp.TeamID != teamID {
+		utils.Error(w, r, http.StatusNotFound, errors.ErrSprintNotFound)
+		return nil, false
+	}
+
+	return sp, true
+}
+
+func (s *SprintHandlers) respondSprint(w http.ResponseWriter, r *http.Request, id int) {
+	sp, err := s.Store.Sprint().Find(id)
+	if err != nil {
+		utils.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.Respond(w, r, http.StatusOK, sp)
+}
+
+func sprintError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case store.ErrRecordNotFound:
+		utils.Error(w, r, http.StatusNotFound, errors.ErrSprintNotFound)
+	case model.ErrSprintActiveExists, model.ErrSprintNotPlanned, model.ErrSprintNotActive, model.ErrSprintClosed:
+		utils.Error(w, r, http.StatusConflict, err)
+	default:
+		utils.Error(w, r, http.StatusUnprocessableEntity, err)
+	}
+}
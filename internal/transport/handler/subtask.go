@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type SubtaskHandlers struct {
+	Store  store.Store
+	Policy *policy.Policy
+}
+
+func (s *SubtaskHandlers) HandleSubtaskList() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermViewTeam); !authorized(w, r, err) {
+			return
+		}
+
+		tasks, err := s.Store.Task().Children(taskID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if tasks == nil {
+			tasks = []*model.Task{}
+		}
+
+		utils.Respond(w, r, http.StatusOK, tasks)
+	}
+}
+
+func (s *SubtaskHandlers) HandleSubtaskCreate() http.HandlerFunc {
+	type request struct {
+		Name       string             `json:"name"`
+		Content    string             `json:"content"`
+		Status     model.TaskStatus   `json:"status"`
+		Priority   model.TaskPriority `json:"priority"`
+		DueDate    *time.Time         `json:"due_date"`
+		AssigneeID *int               `json:"assignee_id"`
+
+		StoryPoints     *int    `json:"story_points"`
+		EstimateMinutes *int    `json:"estimate_minutes"`
+		Recurrence      *string `json:"recurrence"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		parentID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		parent, err := s.Policy.AuthorizeTask(currentUser(r), parentID, model.PermEditTasks)
+		if !authorized(w, r, err) {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		t := &model.Task{
+			Name:       req.Name,
+			Content:    req.Content,
+			Status:     req.Status,
+			Priority:   req.Priority,
+			DueDate:    req.DueDate,
+			AssigneeID: req.AssigneeID,
+			TeamID:     parent.TeamID,
+			ParentID:   &parent.ID,
+
+			StoryPoints:     req.StoryPoints,
+			EstimateMinutes: req.EstimateMinutes,
+			Recurrence:      req.Recurrence,
+		}
+
+		if err := s.Store.Task().Create(t, actorID(r)); err != nil {
+			subtaskError(w, r, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusCreated, t)
+	}
+}
+
+func (s *SubtaskHandlers) HandleTaskSetParent() http.HandlerFunc {
+	type request struct {
+		ParentID *int `json:"parent_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermEditTasks); !authorized(w, r, err) {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := s.Store.Task().SetParent(taskID, req.ParentID, actorID(r)); err != nil {
+			subtaskError(w, r, err)
+			return
+		}
+
+		updated, err := s.Store.Task().GetByID(taskID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, updated)
+	}
+}
+
+func subtaskError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case store.ErrParentTaskNotFound, store.ErrRecordNotFound:
+		utils.Error(w, r, http.StatusNotFound, err)
+	default:
+		utils.Error(w, r, http.StatusUnprocessableEntity, err)
+	}
+}
@@ -7,13 +7,16 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/app/policy"
 	"github.com/qeery8/rest/internal/app/utils"
 	"github.com/qeery8/rest/internal/model"
 	"github.com/qeery8/rest/internal/store"
 )
 
 type TaskHandlers struct {
-	Store store.Store
+	Store  store.Store
+	Policy *policy.Policy
 }
 
 func (s *TaskHandlers) HandleTaskCreate() http.HandlerFunc {
@@ -23,6 +26,11 @@ func (s *TaskHandlers) HandleTaskCreate() http.HandlerFunc {
 		Status   model.TaskStatus   `json:"status"`
 		Priority model.TaskPriority `json:"priority"`
 		DueDate  *time.Time         `json:"due_date"`
+		TeamID   int                `json:"team_id"`
+
+		StoryPoints     *int    `json:"story_points"`
+		EstimateMinutes *int    `json:"estimate_minutes"`
+		Recurrence      *string `json:"recurrence"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -33,54 +41,138 @@ func (s *TaskHandlers) HandleTaskCreate() http.HandlerFunc {
 			return
 		}
 
+		if _, err := s.Policy.Authorize(currentUser(r), req.TeamID, model.PermEditTasks); !authorized(w, r, err) {
+			return
+		}
+
 		t := &model.Task{
 			Name:     req.Name,
 			Content:  req.Content,
 			Status:   req.Status,
 			Priority: req.Priority,
 			DueDate:  req.DueDate,
+			TeamID:   req.TeamID,
+
+			StoryPoints:     req.StoryPoints,
+			EstimateMinutes: req.EstimateMinutes,
+			Recurrence:      req.Recurrence,
 		}
 
-		if err := s.Store.Task().Create(t); err != nil {
+		if err := s.Store.Task().Create(t, actorID(r)); err != nil {
 			utils.Error(w, r, http.StatusUnprocessableEntity, err)
 			return
 		}
-		utils.Respond(w, r, http.StatusOK, nil)
+
+		utils.Respond(w, r, http.StatusCreated, t)
 	}
 }
 
-func (s *TaskHandlers) HandleTaskList() http.HandlerFunc {
+func (s *TaskHandlers) HandleTeamTaskCreate() http.HandlerFunc {
 	type request struct {
-		Name    string `json:"name"`
-		Content string `json:"content"`
+		Name       string             `json:"name"`
+		Content    string             `json:"content"`
+		Status     model.TaskStatus   `json:"status"`
+		Priority   model.TaskPriority `json:"priority"`
+		DueDate    *time.Time         `json:"due_date"`
+		AssigneeID *int               `json:"assignee_id"`
+
+		StoryPoints     *int    `json:"story_points"`
+		EstimateMinutes *int    `json:"estimate_minutes"`
+		Recurrence      *string `json:"recurrence"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
-		req := &request{}
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
 
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermEditTasks); !authorized(w, r, err) {
+			return
+		}
+
+		req := &request{}
 		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
 			utils.Error(w, r, http.StatusBadRequest, err)
 			return
 		}
 
-		tasks, err := s.Store.Task().List()
+		t := &model.Task{
+			Name:       req.Name,
+			Content:    req.Content,
+			Status:     req.Status,
+			Priority:   req.Priority,
+			DueDate:    req.DueDate,
+			AssigneeID: req.AssigneeID,
+			TeamID:     teamID,
+
+			StoryPoints:     req.StoryPoints,
+			EstimateMinutes: req.EstimateMinutes,
+			Recurrence:      req.Recurrence,
+		}
+
+		if err := s.Store.Task().Create(t, actorID(r)); err != nil {
+			utils.Error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusCreated, t)
+	}
+}
+
+func (s *TaskHandlers) HandleTeamTaskList() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
 		if err != nil {
-			utils.Error(w, r, http.StatusNotFound, err)
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermViewTeam); !authorized(w, r, err) {
+			return
+		}
+
+		filter, err := parseTaskFilter(r)
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
 			return
 		}
-		utils.Respond(w, r, http.StatusOK, tasks)
+		filter.TeamID = &teamID
+
+		s.respondTaskPage(w, r, filter)
 	}
 }
 
-func (s *TaskHandlers) HandleTaskUpdate() http.HandlerFunc {
-	type request struct {
-		Name       string             `json:"name"`
-		Content    string             `json:"content"`
-		Status     model.TaskStatus   `json:"status"`
-		Priority   model.TaskPriority `json:"priority"`
-		AssigneeID *int               `json:"assignee_id"`
+func (s *TaskHandlers) HandleTaskList() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := parseTaskFilter(r)
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		s.respondTaskPage(w, r, filter)
 	}
+}
+
+func (s *TaskHandlers) respondTaskPage(w http.ResponseWriter, r *http.Request, filter *store.TaskFilter) {
+	page, err := s.Store.Task().Find(filter)
+	if err != nil {
+		if err == store.ErrInvalidCursor || err == store.ErrInvalidSort {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+		utils.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.Respond(w, r, http.StatusOK, page)
+}
 
+// HandleTaskUpdate changes only the fields present in the body, see
+// model.TaskPatch; clearing assignee_id unassigns everyone.
+func (s *TaskHandlers) HandleTaskUpdate() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		idStr := mux.Vars(r)["task_id"]
 		id, err := strconv.Atoi(idStr)
@@ -89,23 +181,18 @@ func (s *TaskHandlers) HandleTaskUpdate() http.HandlerFunc {
 			return
 		}
 
-		req := &request{}
-		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
-			utils.Error(w, r, http.StatusBadRequest, err)
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), id, model.PermEditTasks); !authorized(w, r, err) {
 			return
 		}
 
-		task := &model.Task{
-			ID:         id,
-			Name:       req.Name,
-			Content:    req.Content,
-			Status:     req.Status,
-			Priority:   req.Priority,
-			AssigneeID: req.AssigneeID,
+		patch := &model.TaskPatch{}
+		if err := json.NewDecoder(r.Body).Decode(patch); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
 		}
 
-		if err := s.Store.Task().Update(task); err != nil {
-			utils.Error(w, r, http.StatusNotFound, err)
+		if err := s.Store.Task().Update(id, patch, actorID(r)); err != nil {
+			taskUpdateError(w, r, err)
 			return
 		}
 
@@ -119,6 +206,22 @@ func (s *TaskHandlers) HandleTaskUpdate() http.HandlerFunc {
 	}
 }
 
+// taskUpdateError answers 422 for a change the task or its workflow does
+// not accept and 500 for a failure of the store itself.
+func taskUpdateError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case store.ErrRecordNotFound:
+		utils.Error(w, r, http.StatusNotFound, errors.ErrTaskNotFound)
+	case model.ErrNameShort, model.ErrNameTooLong, model.ErrNameLineBreak, model.ErrTaskPriority,
+		model.ErrNegativeEstimate, model.ErrInvalidRecurrence,
+		model.ErrUnknownStatus, model.ErrTransitionNotAllowed, model.ErrOpenSubtasks, model.ErrTaskBlocked,
+		store.ErrUserNotInTeam:
+		utils.Error(w, r, http.StatusUnprocessableEntity, err)
+	default:
+		utils.Error(w, r, http.StatusInternalServerError, err)
+	}
+}
+
 func (s *TaskHandlers) HandleTaskGetID() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		idStr := mux.Vars(r)["task_id"]
@@ -128,9 +231,8 @@ func (s *TaskHandlers) HandleTaskGetID() http.HandlerFunc {
 			return
 		}
 
-		task, err := s.Store.Task().GetByID(id)
-		if err != nil {
-			utils.Error(w, r, http.StatusNotFound, err)
+		task, err := s.Policy.AuthorizeTask(currentUser(r), id, model.PermViewTeam)
+		if !authorized(w, r, err) {
 			return
 		}
 		utils.Respond(w, r, http.StatusOK, task)
@@ -146,8 +248,16 @@ func (s *TaskHandlers) HandleTaskDelete() http.HandlerFunc {
 			return
 		}
 
-		if err := s.Store.Task().Delete(taskID); err != nil {
-			utils.Error(w, r, http.StatusNotFound, err)
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermDeleteTasks); !authorized(w, r, err) {
+			return
+		}
+
+		if err := s.Store.Task().Delete(taskID, actorID(r)); err != nil {
+			if err == store.ErrRecordNotFound {
+				utils.Error(w, r, http.StatusNotFound, errors.ErrTaskNotFound)
+				return
+			}
+			utils.Error(w, r, http.StatusInternalServerError, err)
 			return
 		}
 
@@ -157,7 +267,7 @@ func (s *TaskHandlers) HandleTaskDelete() http.HandlerFunc {
 
 func (s *TaskHandlers) HandleTaskAssigneeID() http.HandlerFunc {
 	type request struct {
-		TeamID int `json:"team_id"`
+		TaskID int `json:"task_id"`
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
 		idStr := mux.Vars(r)["user_id"]
@@ -173,10 +283,23 @@ func (s *TaskHandlers) HandleTaskAssigneeID() http.HandlerFunc {
 			return
 		}
 
-		if err := s.Store.Task().AssigneeUser(userID, req.TeamID); err != nil {
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), req.TaskID, model.PermEditTasks); !authorized(w, r, err) {
+			return
+		}
+
+		if err := s.Store.Task().AssigneeUser(userID, req.TaskID, actorID(r)); err != nil {
+			if err == store.ErrRecordNotFound {
+				utils.Error(w, r, http.StatusNotFound, errors.ErrTaskNotFound)
+				return
+			}
+			if err == store.ErrUserNotInTeam {
+				utils.Error(w, r, http.StatusUnprocessableEntity, err)
+				return
+			}
 			utils.Error(w, r, http.StatusInternalServerError, err)
 			return
 		}
+
 		utils.Respond(w, r, http.StatusOK, nil)
 	}
 }
@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+// parseTaskFilter reads task listing parameters from the query string:
+//
+//	status, priority   comma separated or repeated values
+//	assignee_id        user id or "me", any of the task's assignees
+//	watcher_id         user id or "me"
+//	involved_id        user id or "me", assigned to or watching the task
+//	team_id            team id
+//	due_from, due_to   RFC 3339 timestamp or YYYY-MM-DD
+//	q                  substring of name or content
+//	labels             label ids, label_match=all to require every label
+//	sprint_id          sprint id or "none" for the backlog
+//	field.<key>        custom field values, field.<key>.from and .to for ranges
+//	sort               created_at, due_date or priority, "-" prefix for descending
+//	limit, cursor      page size and next_cursor from the previous page
+func parseTaskFilter(r *http.Request) (*store.TaskFilter, error) {
+	q := r.URL.Query()
+	f := &store.TaskFilter{
+		MemberID: currentUser(r).ID,
+		Query:    strings.TrimSpace(q.Get("q")),
+		Cursor:   q.Get("cursor"),
+	}
+
+	for _, v := range listParam(q["status"]) {
+		st := model.TaskStatus(v)
+		if !st.Valid() {
+			return nil, invalidParam("status")
+		}
+		f.Statuses = append(f.Statuses, st)
+	}
+
+	for _, v := range listParam(q["priority"]) {
+		p := model.TaskPriority(v)
+		if !p.Valid() {
+			return nil, invalidParam("priority")
+		}
+		f.Priorities = append(f.Priorities, p)
+	}
+
+	var err error
+	if f.AssigneeID, err = userParam(r, "assignee_id"); err != nil {
+		return nil, err
+	}
+	if f.WatcherID, err = userParam(r, "watcher_id"); err != nil {
+		return nil, err
+	}
+	if f.InvolvedID, err = userParam(r, "involved_id"); err != nil {
+		return nil, err
+	}
+
+	if v := q.Get("team_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, invalidParam("team_id")
+		}
+		f.TeamID = &id
+	}
+
+	for _, v := range listParam(q["labels"]) {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, invalidParam("labels")
+		}
+		f.LabelIDs = append(f.LabelIDs, id)
+	}
+
+	switch q.Get("label_match") {
+	case "", "any":
+	case "all":
+		f.AllLabels = true
+	default:
+		return nil, invalidParam("label_match")
+	}
+
+	if v := q.Get("sprint_id"); v == "none" {
+		f.Backlog = true
+	} else if v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, invalidParam("sprint_id")
+		}
+		f.SprintID = &id
+	}
+
+	if f.Fields, err = parseFieldFilters(q); err != nil {
+		return nil, err
+	}
+
+	if f.DueFrom, err = timeParam(q.Get("due_from"), false); err != nil {
+		return nil, invalidParam("due_from")
+	}
+	if f.DueTo, err = timeParam(q.Get("due_to"), true); err != nil {
+		return nil, invalidParam("due_to")
+	}
+
+	if v := q.Get("sort"); v != "" {
+		f.Desc = strings.HasPrefix(v, "-")
+		f.Sort = strings.TrimPrefix(v, "-")
+	}
+
+	if v := q.Get("limit"); v != "" {
+		if f.Limit, err = strconv.Atoi(v); err != nil || f.Limit < 1 {
+			return nil, invalidParam("limit")
+		}
+	}
+
+	return f, nil
+}
+
+// parseFieldFilters collects the field.<key> parameters in key order. Range
+// bounds must be numbers or YYYY-MM-DD dates.
+func parseFieldFilters(q url.Values) ([]store.FieldFilter, error) {
+	byKey := map[string]*store.FieldFilter{}
+	var keys []string
+
+	for name, values := range q {
+		if !strings.HasPrefix(name, "field.") {
+			continue
+		}
+
+		key, bound := strings.TrimPrefix(name, "field."), ""
+		if i := strings.IndexByte(key, '.'); i >= 0 {
+			key, bound = key[:i], key[i+1:]
+		}
+		if !model.ValidFieldKey(key) {
+			return nil, invalidParam(name)
+		}
+
+		ff, ok := byKey[key]
+		if !ok {
+			ff = &store.FieldFilter{Key: key}
+			byKey[key] = ff
+			keys = append(keys, key)
+		}
+
+		switch bound {
+		case "":
+			ff.Values = append(ff.Values, listParam(values)...)
+		case "from", "to":
+			v := strings.TrimSpace(values[0])
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				if _, err := time.Parse("2006-01-02", v); err != nil {
+					return nil, invalidParam(name)
+				}
+			}
+			if bound == "from" {
+				ff.From = v
+			} else {
+				ff.To = v
+			}
+		default:
+			return nil, invalidParam(name)
+		}
+	}
+
+	sort.Strings(keys)
+	filters := make([]store.FieldFilter, 0, len(keys))
+	for _, key := range keys {
+		filters = append(filters, *byKey[key])
+	}
+	return filters, nil
+}
+
+// userParam reads a user id parameter, where "me" is the caller.
+func userParam(r *http.Request, name string) (*int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return nil, nil
+	}
+
+	id := currentUser(r).ID
+	if v != "me" {
+		var err error
+		if id, err = strconv.Atoi(v); err != nil {
+			return nil, invalidParam(name)
+		}
+	}
+	return &id, nil
+}
+
+func listParam(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+// timeParam accepts RFC 3339 or a bare date. A bare date used as an upper
+// bound covers the whole day.
+func timeParam(v string, endOfDay bool) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
+
+func invalidParam(name string) error {
+	return fmt.Errorf("invalid query parameter %q", name)
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/qeery8/rest/internal/app/ctxkeys"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+func TestParseTaskFilter(t *testing.T) {
+	ref := func(v int) *int { return &v }
+	at := func(s string) *time.Time {
+		v, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &v
+	}
+	const me = 5
+
+	testCases := []struct {
+		name  string
+		query string
+		want  *store.TaskFilter
+		err   error
+	}{
+		{
+			name:  "defaults",
+			query: "",
+			want:  &store.TaskFilter{MemberID: me},
+		},
+		{
+			name:  "lists are split and repeated",
+			query: "status=todo,+done&status=in_progress&priority=high",
+			want: &store.TaskFilter{
+				MemberID:   me,
+				Statuses:   []model.TaskStatus{model.StatusToDo, model.StatusDone, model.StatusInProgress},
+				Priorities: []model.TaskPriority{model.HightPriority},
+			},
+		},
+		{
+			name:  "me and ids",
+			query: "assignee_id=me&watcher_id=7&involved_id=me&team_id=3",
+			want:  &store.TaskFilter{MemberID: me, AssigneeID: ref(me), WatcherID: ref(7), InvolvedID: ref(me), TeamID: ref(3)},
+		},
+		{
+			name:  "bare dates cover whole days",
+			query: "due_from=2026-03-01&due_to=2026-03-31",
+			want:  &store.TaskFilter{MemberID: me, DueFrom: at("2026-03-01T00:00:00Z"), DueTo: at("2026-03-31T23:59:59.999999999Z")},
+		},
+		{
+			name:  "timestamps",
+			query: "due_to=2026-03-31T12:00:00%2B03:00",
+			want:  &store.TaskFilter{MemberID: me, DueTo: at("2026-03-31T12:00:00+03:00")},
+		},
+		{
+			name:  "labels and sprint",
+			query: "labels=1,2&label_match=all&sprint_id=4",
+			want:  &store.TaskFilter{MemberID: me, LabelIDs: []int{1, 2}, AllLabels: true, SprintID: ref(4)},
+		},
+		{
+			name:  "backlog",
+			query: "sprint_id=none",
+			want:  &store.TaskFilter{MemberID: me, Backlog: true},
+		},
+		{
+			name:  "search, sort and page",
+			query: "q=+report+&sort=-due_date&limit=10&cursor=abc",
+			want:  &store.TaskFilter{MemberID: me, Query: "report", Sort: store.SortDueDate, Desc: true, Limit: 10, Cursor: "abc"},
+		},
+		{name: "malformed status", query: "status=In%20Progress", err: invalidParam("status")},
+		{name: "unknown priority", query: "priority=urgent", err: invalidParam("priority")},
+		{name: "bad assignee", query: "assignee_id=ann", err: invalidParam("assignee_id")},
+		{name: "bad team", query: "team_id=x", err: invalidParam("team_id")},
+		{name: "bad label", query: "labels=1,x", err: invalidParam("labels")},
+		{name: "bad label match", query: "label_match=some", err: invalidParam("label_match")},
+		{name: "bad sprint", query: "sprint_id=next", err: invalidParam("sprint_id")},
+		{name: "bad due date", query: "due_from=01.03.2026", err: invalidParam("due_from")},
+		{name: "zero limit", query: "limit=0", err: invalidParam("limit")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/private/tasks?"+tc.query, nil)
+			r = r.WithContext(context.WithValue(r.Context(), ctxkeys.CtxKeyUser, &model.User{ID: me}))
+
+			got, err := parseTaskFilter(r)
+			if tc.err != nil {
+				if err == nil || err.Error() != tc.err.Error() {
+					t.Fatalf("got error %v, want %v", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// пустой список фильтров по полям равен его отсутствию
+			if len(got.Fields) == 0 {
+				got.Fields = nil
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseFieldFilters(t *testing.T) {
+	testCases := []struct {
+		name  string
+		query string
+		want  []store.FieldFilter
+		err   error
+	}{
+		{
+			name:  "none",
+			query: "status=todo",
+			want:  []store.FieldFilter{},
+		},
+		{
+			name:  "values in key order",
+			query: "field.team=web,api&field.env=prod&field.team=ios",
+			want: []store.FieldFilter{
+				{Key: "env", Values: []string{"prod"}},
+				{Key: "team", Values: []string{"web", "api", "ios"}},
+			},
+		},
+		{
+			name:  "ranges",
+			query: "field.points.from=+2.5+&field.points.to=8&field.release.to=2026-06-30",
+			want: []store.FieldFilter{
+				{Key: "points", From: "2.5", To: "8"},
+				{Key: "release", To: "2026-06-30"},
+			},
+		},
+		{
+			name:  "value and range on one key",
+			query: "field.points=3&field.points.from=1",
+			want:  []store.FieldFilter{{Key: "points", Values: []string{"3"}, From: "1"}},
+		},
+		{name: "bad key", query: "field.Team=web", err: invalidParam("field.Team")},
+		{name: "unknown bound", query: "field.points.max=3", err: invalidParam("field.points.max")},
+		{name: "text bound", query: "field.points.from=low", err: invalidParam("field.points.from")},
+		{name: "bad date bound", query: "field.release.to=30.06.2026", err: invalidParam("field.release.to")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := parseFieldFilters(q)
+			if tc.err != nil {
+				if err == nil || err.Error() != tc.err.Error() {
+					t.Fatalf("got error %v, want %v", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -7,20 +7,21 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/app/policy"
 	"github.com/qeery8/rest/internal/app/utils"
 	"github.com/qeery8/rest/internal/model"
 	"github.com/qeery8/rest/internal/store"
 )
 
 type TeamHandlers struct {
-	Store store.Store
+	Store  store.Store
+	Policy *policy.Policy
 }
 
 func (s *TeamHandlers) HandleTeamsCreate() http.HandlerFunc {
 	type request struct {
 		Name        string `json:"name"`
 		Description string `json:"description"`
-		OwnerID     int    `json:"owner_id"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -33,7 +34,7 @@ func (s *TeamHandlers) HandleTeamsCreate() http.HandlerFunc {
 		t := &model.Team{
 			Name:        req.Name,
 			Description: req.Description,
-			OwnerID:     req.OwnerID,
+			OwnerID:     currentUser(r).ID,
 		}
 
 		if err := s.Store.Team().Create(t); err != nil {
@@ -41,7 +42,7 @@ func (s *TeamHandlers) HandleTeamsCreate() http.HandlerFunc {
 			return
 		}
 
-		utils.Respond(w, r, http.StatusOK, nil)
+		utils.Respond(w, r, http.StatusCreated, t)
 	}
 }
 
@@ -61,6 +62,10 @@ func (s *TeamHandlers) HandleTeamID() http.HandlerFunc {
 			return
 		}
 
+		if _, err := s.Policy.Authorize(currentUser(r), id, model.PermViewTeam); !authorized(w, r, err) {
+			return
+		}
+
 		team, err := s.Store.Team().Find(id)
 		if err != nil {
 			utils.Error(w, r, http.StatusNotFound, err)
@@ -87,6 +92,11 @@ func (s *TeamHandlers) HandleTeamByUserID() http.HandlerFunc {
 			return
 		}
 
+		if id != currentUser(r).ID {
+			utils.Error(w, r, http.StatusForbidden, errors.ErrForbidden)
+			return
+		}
+
 		teams, err := s.Store.Team().FindByUser(id)
 		if err != nil {
 			utils.Error(w, r, http.StatusNotFound, err)
@@ -97,9 +107,32 @@ func (s *TeamHandlers) HandleTeamByUserID() http.HandlerFunc {
 	}
 }
 
+func (s *TeamHandlers) HandleTeamMembers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermViewTeam); !authorized(w, r, err) {
+			return
+		}
+
+		members, err := s.Store.Team().Members(teamID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, members)
+	}
+}
+
 func (s *TeamHandlers) HandleTeamAddMembers() http.HandlerFunc {
 	type request struct {
-		UserID int `json:"user_id"`
+		UserID int            `json:"user_id"`
+		Role   model.TeamRole `json:"role"`
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
@@ -112,20 +145,148 @@ func (s *TeamHandlers) HandleTeamAddMembers() http.HandlerFunc {
 			return
 		}
 
+		actorRole, err := s.Policy.Authorize(currentUser(r), teamID, model.PermManageMembers)
+		if !authorized(w, r, err) {
+			return
+		}
+
 		req := &request{}
 		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
 			utils.Error(w, r, http.StatusBadRequest, err)
 			return
 		}
 
-		if err := s.Store.Team().AddMembers(teamID, req.UserID); err != nil {
+		if req.Role == "" {
+			req.Role = model.RoleMember
+		}
+		if !req.Role.Valid() || req.Role == model.RoleOwner {
+			utils.Error(w, r, http.StatusUnprocessableEntity, model.ErrInvalidRole)
+			return
+		}
+		if !actorRole.Outranks(req.Role) {
+			utils.Error(w, r, http.StatusForbidden, errors.ErrForbidden)
+			return
+		}
+
+		if _, err := s.Store.User().Find(req.UserID); err != nil {
 			utils.Error(w, r, http.StatusNotFound, err)
 			return
 		}
+
+		if err := s.Store.Team().AddMembers(teamID, req.UserID, req.Role); err != nil {
+			if err == store.ErrAlreadyInTeam {
+				utils.Error(w, r, http.StatusConflict, err)
+				return
+			}
+			utils.Error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
 		utils.Respond(w, r, http.StatusOK, nil)
 	}
 }
 
+func (s *TeamHandlers) HandleTeamMemberRole() http.HandlerFunc {
+	type request struct {
+		Role model.TeamRole `json:"role"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		teamID, err := strconv.Atoi(vars["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		userID, err := strconv.Atoi(vars["user_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		actorRole, err := s.Policy.Authorize(currentUser(r), teamID, model.PermManageMembers)
+		if !authorized(w, r, err) {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if !req.Role.Valid() || req.Role == model.RoleOwner {
+			utils.Error(w, r, http.StatusUnprocessableEntity, model.ErrInvalidRole)
+			return
+		}
+
+		targetRole, err := s.Store.Team().MemberRole(teamID, userID)
+		if err != nil {
+			utils.Error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		if !actorRole.Outranks(targetRole) || !actorRole.Outranks(req.Role) {
+			utils.Error(w, r, http.StatusForbidden, errors.ErrForbidden)
+			return
+		}
+
+		if err := s.Store.Team().UpdateMemberRole(teamID, userID, req.Role); err != nil {
+			utils.Error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+		utils.Respond(w, r, http.StatusOK, nil)
+	}
+}
+
+func (s *TeamHandlers) HandleTeamTransferOwnership() http.HandlerFunc {
+	type request struct {
+		UserID int `json:"user_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermTransferOwnership); !authorized(w, r, err) {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		// владелец передает команду только другому участнику
+		if req.UserID == currentUser(r).ID {
+			utils.Error(w, r, http.StatusUnprocessableEntity, model.ErrTransferToOwner)
+			return
+		}
+
+		if err := s.Store.Team().TransferOwnership(teamID, req.UserID); err != nil {
+			if err == store.ErrUserNotInTeam || err == model.ErrTransferToOwner {
+				utils.Error(w, r, http.StatusUnprocessableEntity, err)
+				return
+			}
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		team, err := s.Store.Team().Find(teamID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, team)
+	}
+}
+
 func (s *TeamHandlers) HandleTeamMembersDelete() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
@@ -133,11 +294,54 @@ func (s *TeamHandlers) HandleTeamMembersDelete() http.HandlerFunc {
 		teamIdStr := vars["team_id"]
 		userIdStr := vars["user_id"]
 
-		teamID, _ := strconv.Atoi(teamIdStr)
-		userID, _ := strconv.Atoi(userIdStr)
+		teamID, err := strconv.Atoi(teamIdStr)
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		userID, err := strconv.Atoi(userIdStr)
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		team, err := s.Store.Team().Find(teamID)
+		if err != nil {
+			utils.Error(w, r, http.StatusNotFound, errors.ErrTeamNotFound)
+			return
+		}
+
+		if team.OwnerID == userID {
+			utils.Error(w, r, http.StatusUnprocessableEntity, errors.ErrOwnerCannotLeave)
+			return
+		}
+
+		// участник может выйти из команды сам, остальных удаляют те, кто выше по роли
+		if userID != currentUser(r).ID {
+			actorRole, err := s.Policy.Authorize(currentUser(r), teamID, model.PermManageMembers)
+			if !authorized(w, r, err) {
+				return
+			}
+
+			targetRole, err := s.Store.Team().MemberRole(teamID, userID)
+			if err != nil {
+				utils.Error(w, r, http.StatusNotFound, err)
+				return
+			}
+
+			if !actorRole.Outranks(targetRole) {
+				utils.Error(w, r, http.StatusForbidden, errors.ErrForbidden)
+				return
+			}
+		}
 
 		if err := s.Store.Team().RemoveMembers(teamID, userID); err != nil {
-			utils.Error(w, r, http.StatusNotFound, err)
+			if err == store.ErrUserNotInTeam {
+				utils.Error(w, r, http.StatusNotFound, err)
+				return
+			}
+			utils.Error(w, r, http.StatusInternalServerError, err)
 			return
 		}
 		utils.Respond(w, r, http.StatusOK, nil)
@@ -158,6 +362,10 @@ func (s *TeamHandlers) HandleTeamUpdate() http.HandlerFunc {
 			return
 		}
 
+		if _, err := s.Policy.Authorize(currentUser(r), id, model.PermUpdateTeam); !authorized(w, r, err) {
+			return
+		}
+
 		req := &request{}
 		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
 			utils.Error(w, r, http.StatusBadRequest, err)
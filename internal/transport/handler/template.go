@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type TemplateHandlers struct {
+	Store  store.Store
+	Policy *policy.Policy
+}
+
+type templateRequest struct {
+	Name        *string                  `json:"name"`
+	NamePattern *string                  `json:"name_pattern"`
+	Content     *string                  `json:"content"`
+	Priority    *model.TaskPriority      `json:"priority"`
+	LabelIDs    *[]int                   `json:"label_ids"`
+	Checklist   *[]string                `json:"checklist"`
+	Subtasks    *[]model.TemplateSubtask `json:"subtasks"`
+}
+
+func (req *templateRequest) apply(t *model.TaskTemplate) {
+	if req.Name != nil {
+		t.Name = *req.Name
+	}
+	if req.NamePattern != nil {
+		t.NamePattern = *req.NamePattern
+	}
+	if req.Content != nil {
+		t.Content = *req.Content
+	}
+	if req.Priority != nil {
+		t.Priority = *req.Priority
+	}
+	if req.LabelIDs != nil {
+		t.LabelIDs = *req.LabelIDs
+	}
+	if req.Checklist != nil {
+		t.Checklist = *req.Checklist
+	}
+	if req.Subtasks != nil {
+		t.Subtasks = *req.Subtasks
+	}
+}
+
+func (s *TemplateHandlers) HandleTemplateList() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermViewTeam); !authorized(w, r, err) {
+			return
+		}
+
+		templates, err := s.Store.Template().ListByTeam(teamID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, templates)
+	}
+}
+
+func (s *TemplateHandlers) HandleTemplateCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermEditTasks); !authorized(w, r, err) {
+			return
+		}
+
+		req := &templateRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		t := &model.TaskTemplate{
+			TeamID:    teamID,
+			CreatedBy: actorID(r),
+		}
+		req.apply(t)
+
+		if err := s.Store.Template().Create(t); err != nil {
+			templateError(w, r, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusCreated, t)
+	}
+}
+
+func (s *TemplateHandlers) HandleTemplateGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		t, ok := s.loadTemplate(w, r, model.PermViewTeam)
+		if !ok {
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, t)
+	}
+}
+
+func (s *TemplateHandlers) HandleTemplateUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		t, ok := s.loadTemplate(w, r, model.PermEditTasks)
+		if !ok {
+			return
+		}
+
+		req := &templateRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+		req.apply(t)
+
+		if err := s.Store.Template().Update(t); err != nil {
+			templateError(w, r, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, t)
+	}
+}
+
+func (s *TemplateHandlers) HandleTemplateDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		t, ok := s.loadTemplate(w, r, model.PermEditTasks)
+		if !ok {
+			return
+		}
+
+		if err := s.Store.Template().Delete(t.ID); err != nil {
+			templateError(w, r, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, nil)
+	}
+}
+
+func (s *TemplateHandlers) HandleTemplateInstantiate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		t, ok := s.loadTemplate(w, r, model.PermEditTasks)
+		if !ok {
+			return
+		}
+
+		in := &model.TemplateInstance{}
+		if err := json.NewDecoder(r.Body).Decode(in); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		created, err := s.Store.Template().Instantiate(t, in, actorID(r))
+		if err != nil {
+			utils.Error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		task, err := s.Store.Task().GetByID(created[0].ID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusCreated, task)
+	}
+}
+
+func (s *TemplateHandlers) loadTemplate(w http.ResponseWriter, r *http.Request, perm model.Permission) (*model.TaskTemplate, bool) {
+	vars := mux.Vars(r)
+
+	teamID, err := strconv.Atoi(vars["team_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+		return nil, false
+	}
+
+	templateID, err := strconv.Atoi(vars["template_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err)
+		return nil, false
+	}
+
+	if _, err := s.Policy.Authorize(currentUser(r), teamID, perm); !authorized(w, r, err) {
+		return nil, false
+	}
+
+	t, err := s.Store.Template().Find(templateID)
+	if err != nil || t.TeamID != teamID {
+		utils.Error(w, r, http.StatusNotFound, errors.ErrTemplateNotFound)
+		return nil, false
+	}
+
+	return t, true
+}
+
+func templateError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case store.ErrRecordNotFound:
+		utils.Error(w, r, http.StatusNotFound, errors.ErrTemplateNotFound)
+	case model.ErrTemplateNameExists:
+		utils.Error(w, r, http.StatusConflict, err)
+	default:
+		utils.Error(w, r, http.StatusUnprocessableEntity, err)
+	}
+}
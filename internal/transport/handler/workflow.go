@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type WorkflowHandlers struct {
+	Store  store.Store
+	Policy *policy.Policy
+}
+
+func (s *WorkflowHandlers) HandleWorkflowGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermViewTeam); !authorized(w, r, err) {
+			return
+		}
+
+		wf, err := s.Store.Workflow().Get(teamID)
+		if err != nil {
+			if err == store.ErrRecordNotFound {
+				utils.Error(w, r, http.StatusNotFound, err)
+				return
+			}
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, wf)
+	}
+}
+
+func (s *WorkflowHandlers) HandleWorkflowUpdate() http.HandlerFunc {
+	type request struct {
+		Statuses    []*model.WorkflowStatus    `json:"statuses"`
+		Transitions []model.WorkflowTransition `json:"transitions"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, err := strconv.Atoi(mux.Vars(r)["team_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, errors.ErrInvalidTeamId)
+			return
+		}
+
+		if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermUpdateTeam); !authorized(w, r, err) {
+			return
+		}
+
+		req := &request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		wf := &model.Workflow{
+			TeamID:      teamID,
+			Statuses:    req.Statuses,
+			Transitions: req.Transitions,
+		}
+		if wf.Transitions == nil {
+			wf.Transitions = []model.WorkflowTransition{}
+		}
+
+		if err := s.Store.Workflow().Save(wf); err != nil {
+			if err == model.ErrWorkflowStatusInUse {
+				utils.Error(w, r, http.StatusConflict, err)
+				return
+			}
+			utils.Error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, wf)
+	}
+}
@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/qeery8/rest/internal/app/errors"
+	"github.com/qeery8/rest/internal/app/policy"
+	"github.com/qeery8/rest/internal/app/utils"
+	"github.com/qeery8/rest/internal/model"
+	"github.com/qeery8/rest/internal/store"
+)
+
+type WorklogHandlers struct {
+	Store  store.Store
+	Policy *policy.Policy
+}
+
+type worklogRequest struct {
+	Minutes  *int    `json:"minutes"`
+	Note     *string `json:"note"`
+	WorkDate *string `json:"work_date"`
+}
+
+// apply copies the fields present in the request onto wl. work_date is
+// YYYY-MM-DD.
+func (req *worklogRequest) apply(wl *model.Worklog) error {
+	if req.Minutes != nil {
+		wl.Minutes = *req.Minutes
+	}
+	if req.Note != nil {
+		wl.Note = *req.Note
+	}
+	if req.WorkDate != nil {
+		d, err := time.Parse(dateLayout, *req.WorkDate)
+		if err != nil {
+			return invalidParam("work_date")
+		}
+		wl.WorkDate = d
+	}
+	return nil
+}
+
+func (s *WorklogHandlers) HandleWorklogList() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermViewTeam); !authorized(w, r, err) {
+			return
+		}
+
+		worklogs, err := s.Store.Worklog().ListByTask(taskID)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, worklogs)
+	}
+}
+
+func (s *WorklogHandlers) HandleWorklogCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskID, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		task, err := s.Policy.AuthorizeTask(currentUser(r), taskID, model.PermEditTasks)
+		if !authorized(w, r, err) {
+			return
+		}
+
+		req := &worklogRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		now := time.Now().UTC()
+		wl := &model.Worklog{
+			TaskID:   task.ID,
+			TaskName: task.Name,
+			UserID:   currentUser(r).ID,
+			WorkDate: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		}
+		if err := req.apply(wl); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := s.Store.Worklog().Create(wl); err != nil {
+			utils.Error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusCreated, wl)
+	}
+}
+
+func (s *WorklogHandlers) HandleWorklogUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wl, ok := s.loadWorklog(w, r, model.PermEditTasks)
+		if !ok {
+			return
+		}
+
+		if wl.UserID != currentUser(r).ID {
+			utils.Error(w, r, http.StatusForbidden, errors.ErrForbidden)
+			return
+		}
+
+		req := &worklogRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if err := req.apply(wl); err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := s.Store.Worklog().Update(wl); err != nil {
+			utils.Error(w, r, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, wl)
+	}
+}
+
+func (s *WorklogHandlers) HandleWorklogDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wl, ok := s.loadWorklog(w, r, model.PermViewTeam)
+		if !ok {
+			return
+		}
+
+		// свою запись удалить можно всегда, чужую - только с правом удалять задачи
+		if wl.UserID != currentUser(r).ID {
+			if _, err := s.Policy.AuthorizeTask(currentUser(r), wl.TaskID, model.PermDeleteTasks); !authorized(w, r, err) {
+				return
+			}
+		}
+
+		if err := s.Store.Worklog().Delete(wl.ID); err != nil {
+			utils.Error(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		utils.Respond(w, r, http.StatusOK, nil)
+	}
+}
+
+func (s *WorklogHandlers) HandleReportTimesheet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID, ok := reportTeam(w, r, s.Policy)
+		if !ok {
+			return
+		}
+
+		userID := currentUser(r).ID
+		if v := r.URL.Query().Get("user_id"); v != "" && v != "me" {
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				utils.Error(w, r, http.StatusBadRequest, invalidParam("user_id"))
+				return
+			}
+			// чужой табель видят только те, кто управляет участниками
+			if id != userID {
+				if _, err := s.Policy.Authorize(currentUser(r), teamID, model.PermManageMembers); !authorized(w, r, err) {
+					return
+				}
+			}
+			userID = id
+		}
+
+		from, to, err := reportRange(r)
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		ts, err := s.Store.Worklog().Timesheet(userID, &teamID, from, to)
+		if err != nil {
+			utils.Error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		respondReport(w, r, "timesheet", ts)
+	}
+}
+
+func (s *WorklogHandlers) loadWorklog(w http.ResponseWriter, r *http.Request, perm model.Permission) (*model.Worklog, bool) {
+	vars := mux.Vars(r)
+
+	taskID, err := strconv.Atoi(vars["task_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err)
+		return nil, false
+	}
+
+	worklogID, err := strconv.Atoi(vars["worklog_id"])
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err)
+		return nil, false
+	}
+
+	if _, err := s.Policy.AuthorizeTask(currentUser(r), taskID, perm); !authorized(w, r, err) {
+		return nil, false
+	}
+
+	wl, err := s.Store.Worklog().Find(worklogID)
+	if err != nil || wl.TaskID != taskID {
+		utils.Error(w, r, http.StatusNotFound, errors.ErrWorklogNotFound)
+		return nil, false
+	}
+
+	return wl, true
+}
@@ -1,6 +1,6 @@
 CREATE TABLE tasks_abandoned (
     id INT REFERENCES tasks(id) ON DELETE SET NULL,
-    name_task VARCHAR(50) REFERENCES tasks(name) ON DELETE SET NULL,
+    name_task VARCHAR(50),
     content TEXT, 
     abandoned_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 );
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS tasks_team_id_idx;
+
+ALTER TABLE tasks DROP COLUMN team_id;
@@ -0,0 +1,48 @@
+ALTER TABLE tasks ADD COLUMN team_id INT REFERENCES teams(id) ON DELETE CASCADE;
+
+-- tasks created before teams move to their assignee's team when the
+-- assignee belongs to exactly one
+UPDATE tasks t SET team_id = m.team_id
+FROM (
+    SELECT user_id, MIN(team_id) AS team_id
+    FROM team_members
+    WHERE team_id IS NOT NULL
+    GROUP BY user_id
+    HAVING COUNT(*) = 1
+) m
+WHERE t.team_id IS NULL AND m.user_id = t.assignee_id;
+
+-- an assignee in no team or in several gets an Inbox team of their own
+-- holding the rest of their tasks
+WITH inbox AS (
+    INSERT INTO teams (name, description, owner_id)
+    SELECT 'Inbox', 'Tasks created before teams', assignee_id
+    FROM tasks
+    WHERE team_id IS NULL AND assignee_id IS NOT NULL
+    GROUP BY assignee_id
+    RETURNING id, owner_id
+), members AS (
+    INSERT INTO team_members (user_id, team_id)
+    SELECT owner_id, id FROM inbox
+)
+UPDATE tasks t SET team_id = inbox.id
+FROM inbox
+WHERE t.team_id IS NULL AND t.assignee_id = inbox.owner_id;
+
+-- a task nobody is assigned to has no one to own it; stop here instead of
+-- dropping it and let an operator assign or delete those tasks first
+DO $$
+DECLARE
+    orphans INT;
+BEGIN
+    SELECT COUNT(*) INTO orphans FROM tasks WHERE team_id IS NULL;
+    IF orphans > 0 THEN
+        RAISE EXCEPTION '% task(s) have no assignee and cannot be moved to a team', orphans
+            USING HINT = 'Set tasks.assignee_id or delete those rows, then run the migration again.';
+    END IF;
+END
+$$;
+
+ALTER TABLE tasks ALTER COLUMN team_id SET NOT NULL;
+
+CREATE INDEX tasks_team_id_idx ON tasks (team_id);
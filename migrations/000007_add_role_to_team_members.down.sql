@@ -0,0 +1,3 @@
+ALTER TABLE team_members DROP COLUMN role;
+
+DROP TYPE team_role;
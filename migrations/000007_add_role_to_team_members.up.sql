@@ -0,0 +1,16 @@
+CREATE TYPE team_role AS ENUM ('owner', 'admin', 'member', 'viewer');
+
+ALTER TABLE team_members ADD COLUMN role team_role NOT NULL DEFAULT 'member';
+
+UPDATE team_members tm
+SET role = 'owner'
+FROM teams t
+WHERE t.id = tm.team_id AND t.owner_id = tm.user_id;
+
+INSERT INTO team_members (user_id, team_id, role)
+SELECT t.owner_id, t.id, 'owner'
+FROM teams t
+WHERE NOT EXISTS (
+    SELECT 1 FROM team_members tm
+    WHERE tm.team_id = t.id AND tm.user_id = t.owner_id
+);
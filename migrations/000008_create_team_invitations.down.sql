@@ -0,0 +1,3 @@
+DROP TABLE team_invitations;
+
+DROP TYPE invitation_status;
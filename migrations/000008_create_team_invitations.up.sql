@@ -0,0 +1,19 @@
+CREATE TYPE invitation_status AS ENUM ('pending', 'accepted', 'declined', 'expired');
+
+CREATE TABLE team_invitations (
+    id BIGSERIAL PRIMARY KEY,
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    email VARCHAR NOT NULL,
+    role team_role NOT NULL DEFAULT 'member',
+    invited_by INT REFERENCES users(id) ON DELETE SET NULL,
+    status invitation_status NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    responded_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX team_invitations_pending_idx
+    ON team_invitations (team_id, LOWER(email))
+    WHERE status = 'pending';
+
+CREATE INDEX team_invitations_email_idx ON team_invitations (LOWER(email));
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS tasks_due_date_idx;
+
+DROP TABLE task_reminders;
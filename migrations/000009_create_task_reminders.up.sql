@@ -0,0 +1,13 @@
+CREATE TABLE task_reminders (
+    id BIGSERIAL PRIMARY KEY,
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    lead_seconds INT NOT NULL DEFAULT 0,
+    due_date TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ,
+    UNIQUE (task_id, user_id, kind, lead_seconds, due_date)
+);
+
+CREATE INDEX tasks_due_date_idx ON tasks (due_date) WHERE due_date IS NOT NULL;
@@ -0,0 +1,8 @@
+DROP TABLE tasks_abandoned;
+
+CREATE TABLE tasks_abandoned (
+    id INT REFERENCES tasks(id) ON DELETE SET NULL,
+    name_task VARCHAR(50),
+    content TEXT,
+    abandoned_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS tasks_abandoned;
+
+CREATE TABLE tasks_abandoned (
+    task_id INT PRIMARY KEY,
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    snapshot JSONB NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    abandoned_by INT REFERENCES users(id) ON DELETE SET NULL,
+    abandoned_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX tasks_abandoned_team_id_idx ON tasks_abandoned (team_id, abandoned_at DESC);
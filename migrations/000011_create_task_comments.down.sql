@@ -0,0 +1,5 @@
+DROP TABLE task_comment_mentions;
+
+DROP TABLE task_comment_revisions;
+
+DROP TABLE task_comments;
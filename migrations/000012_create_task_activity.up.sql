@@ -0,0 +1,15 @@
+CREATE TABLE task_activity (
+    id BIGSERIAL PRIMARY KEY,
+    task_id INT NOT NULL,
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    actor_id INT REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL,
+    field VARCHAR(50),
+    old_value TEXT,
+    new_value TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX task_activity_task_id_idx ON task_activity (task_id, id DESC);
+
+CREATE INDEX task_activity_team_id_idx ON task_activity (team_id, id DESC);
@@ -0,0 +1,5 @@
+DROP TABLE task_checklist_items;
+
+DROP INDEX IF EXISTS tasks_parent_id_idx;
+
+ALTER TABLE tasks DROP COLUMN parent_id;
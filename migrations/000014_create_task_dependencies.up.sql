@@ -0,0 +1,10 @@
+CREATE TABLE task_dependencies (
+    blocker_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    blocked_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    created_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX task_dependencies_blocked_id_idx ON task_dependencies (blocked_id);
@@ -0,0 +1,3 @@
+DROP TABLE task_labels;
+
+DROP TABLE labels;
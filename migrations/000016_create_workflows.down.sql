@@ -0,0 +1,19 @@
+DROP INDEX tasks_team_id_status_idx;
+
+CREATE TYPE task_status AS ENUM ('todo', 'in_progress', 'done');
+
+-- custom statuses fall back to their category
+UPDATE tasks t SET status = ws.category
+FROM workflow_statuses ws
+WHERE ws.team_id = t.team_id AND ws.key = t.status;
+
+UPDATE tasks SET status = 'todo'
+WHERE status NOT IN ('todo', 'in_progress', 'done');
+
+ALTER TABLE tasks ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE tasks ALTER COLUMN status TYPE task_status USING status::task_status;
+ALTER TABLE tasks ALTER COLUMN status SET DEFAULT 'todo';
+
+DROP TABLE workflow_transitions;
+
+DROP TABLE workflow_statuses;
@@ -0,0 +1,40 @@
+CREATE TABLE workflow_statuses (
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    key VARCHAR(32) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    category VARCHAR(16) NOT NULL CHECK (category IN ('todo', 'in_progress', 'done')),
+    position INT NOT NULL,
+    PRIMARY KEY (team_id, key)
+);
+
+CREATE TABLE workflow_transitions (
+    team_id INT NOT NULL,
+    from_status VARCHAR(32) NOT NULL,
+    to_status VARCHAR(32) NOT NULL,
+    PRIMARY KEY (team_id, from_status, to_status),
+    FOREIGN KEY (team_id, from_status) REFERENCES workflow_statuses(team_id, key) ON DELETE CASCADE,
+    FOREIGN KEY (team_id, to_status) REFERENCES workflow_statuses(team_id, key) ON DELETE CASCADE,
+    CHECK (from_status <> to_status)
+);
+
+INSERT INTO workflow_statuses (team_id, key, name, category, position)
+SELECT teams.id, s.key, s.name, s.key, s.position
+FROM teams
+CROSS JOIN (VALUES
+    ('todo', 'To do', 1),
+    ('in_progress', 'In progress', 2),
+    ('done', 'Done', 3)
+) AS s(key, name, position);
+
+INSERT INTO workflow_transitions (team_id, from_status, to_status)
+SELECT a.team_id, a.key, b.key
+FROM workflow_statuses a
+JOIN workflow_statuses b ON b.team_id = a.team_id AND b.key <> a.key;
+
+ALTER TABLE tasks ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE tasks ALTER COLUMN status TYPE VARCHAR(32) USING status::text;
+ALTER TABLE tasks ALTER COLUMN status SET DEFAULT 'todo';
+
+DROP TYPE task_status;
+
+CREATE INDEX tasks_team_id_status_idx ON tasks (team_id, status);
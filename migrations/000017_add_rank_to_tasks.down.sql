@@ -0,0 +1,3 @@
+DROP INDEX tasks_board_idx;
+
+ALTER TABLE tasks DROP COLUMN rank;
@@ -0,0 +1,10 @@
+ALTER TABLE tasks ADD COLUMN rank BIGINT NOT NULL DEFAULT 0;
+
+UPDATE tasks t SET rank = ranked.position * 65536
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY team_id, status ORDER BY created_at, id) AS position
+    FROM tasks
+) ranked
+WHERE ranked.id = t.id;
+
+CREATE INDEX tasks_board_idx ON tasks (team_id, status, rank);
@@ -0,0 +1,7 @@
+ALTER TABLE tasks DROP COLUMN sprint_id;
+
+DROP TABLE sprint_commitments;
+
+DROP TABLE sprints;
+
+DROP TYPE sprint_state;
@@ -0,0 +1,32 @@
+CREATE TYPE sprint_state AS ENUM ('planned', 'active', 'closed');
+
+CREATE TABLE sprints (
+    id BIGSERIAL PRIMARY KEY,
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    goal TEXT NOT NULL DEFAULT '',
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    state sprint_state NOT NULL DEFAULT 'planned',
+    carried_over INT NOT NULL DEFAULT 0,
+    started_at TIMESTAMPTZ,
+    closed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX sprints_team_id_idx ON sprints (team_id);
+
+CREATE UNIQUE INDEX sprints_active_idx ON sprints (team_id) WHERE state = 'active';
+
+-- what the team committed to when the sprint started; task_id has no
+-- foreign key so the numbers survive deleted tasks
+CREATE TABLE sprint_commitments (
+    sprint_id BIGINT NOT NULL REFERENCES sprints(id) ON DELETE CASCADE,
+    task_id INT NOT NULL,
+    PRIMARY KEY (sprint_id, task_id)
+);
+
+ALTER TABLE tasks ADD COLUMN sprint_id BIGINT REFERENCES sprints(id) ON DELETE SET NULL;
+
+CREATE INDEX tasks_sprint_id_idx ON tasks (sprint_id);
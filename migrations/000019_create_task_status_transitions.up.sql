@@ -0,0 +1,37 @@
+CREATE TABLE task_status_transitions (
+    id BIGSERIAL PRIMARY KEY,
+    task_id INT NOT NULL,
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    from_status VARCHAR(32),
+    to_status VARCHAR(32) NOT NULL,
+    to_category VARCHAR(16) NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX task_status_transitions_task_id_idx ON task_status_transitions (task_id, changed_at);
+
+CREATE INDEX task_status_transitions_team_id_idx ON task_status_transitions (team_id, changed_at);
+
+-- backfill from the activity log: every task starts in the status it had
+-- before its first recorded change, or in its current one
+INSERT INTO task_status_transitions (task_id, team_id, from_status, to_status, to_category, changed_at)
+SELECT t.id, t.team_id, NULL, s.status,
+    COALESCE((SELECT ws.category FROM workflow_statuses ws WHERE ws.team_id = t.team_id AND ws.key = s.status), 'todo'),
+    t.created_at
+FROM tasks t
+CROSS JOIN LATERAL (
+    SELECT COALESCE((
+        SELECT a.old_value FROM task_activity a
+        WHERE a.task_id = t.id AND a.field = 'status'
+        ORDER BY a.id
+        LIMIT 1
+    ), t.status) AS status
+) s
+WHERE t.team_id IS NOT NULL;
+
+INSERT INTO task_status_transitions (task_id, team_id, from_status, to_status, to_category, changed_at)
+SELECT a.task_id, a.team_id, a.old_value, a.new_value,
+    COALESCE((SELECT ws.category FROM workflow_statuses ws WHERE ws.team_id = a.team_id AND ws.key = a.new_value), 'todo'),
+    a.created_at
+FROM task_activity a
+WHERE a.field = 'status' AND a.new_value IS NOT NULL;
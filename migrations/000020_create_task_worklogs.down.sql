@@ -0,0 +1,5 @@
+DROP TABLE task_worklogs;
+
+ALTER TABLE tasks
+    DROP COLUMN story_points,
+    DROP COLUMN estimate_minutes;
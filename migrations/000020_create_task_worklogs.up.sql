@@ -0,0 +1,18 @@
+ALTER TABLE tasks
+    ADD COLUMN story_points INT CHECK (story_points >= 0),
+    ADD COLUMN estimate_minutes INT CHECK (estimate_minutes >= 0);
+
+CREATE TABLE task_worklogs (
+    id BIGSERIAL PRIMARY KEY,
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    minutes INT NOT NULL CHECK (minutes > 0),
+    note TEXT NOT NULL DEFAULT '',
+    work_date DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX task_worklogs_task_id_idx ON task_worklogs (task_id);
+
+CREATE INDEX task_worklogs_user_id_idx ON task_worklogs (user_id, work_date);
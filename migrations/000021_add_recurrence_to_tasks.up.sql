@@ -0,0 +1,3 @@
+ALTER TABLE tasks
+    ADD COLUMN recurrence VARCHAR(100),
+    ADD COLUMN next_occurrence_id INT REFERENCES tasks(id) ON DELETE SET NULL;
@@ -0,0 +1,3 @@
+DROP TABLE task_template_labels;
+
+DROP TABLE task_templates;
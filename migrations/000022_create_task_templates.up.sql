@@ -0,0 +1,21 @@
+CREATE TABLE task_templates (
+    id BIGSERIAL PRIMARY KEY,
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    name_pattern VARCHAR(100) NOT NULL,
+    content TEXT NOT NULL DEFAULT '',
+    priority task_priority,
+    checklist JSONB NOT NULL DEFAULT '[]',
+    subtasks JSONB NOT NULL DEFAULT '[]',
+    created_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX task_templates_team_id_name_idx ON task_templates (team_id, LOWER(name));
+
+CREATE TABLE task_template_labels (
+    template_id BIGINT NOT NULL REFERENCES task_templates(id) ON DELETE CASCADE,
+    label_id BIGINT NOT NULL REFERENCES labels(id) ON DELETE CASCADE,
+    PRIMARY KEY (template_id, label_id)
+);
@@ -0,0 +1,27 @@
+CREATE TYPE custom_field_type AS ENUM ('text', 'number', 'date', 'select');
+
+CREATE TABLE custom_fields (
+    id BIGSERIAL PRIMARY KEY,
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    key VARCHAR(32) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    type custom_field_type NOT NULL,
+    options JSONB NOT NULL DEFAULT '[]',
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (team_id, key)
+);
+
+-- value keeps the canonical text of every type so equality filters need no
+-- casts; value_number and value_date are filled for range filters.
+CREATE TABLE task_field_values (
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    field_id BIGINT NOT NULL REFERENCES custom_fields(id) ON DELETE CASCADE,
+    value TEXT NOT NULL,
+    value_number NUMERIC,
+    value_date DATE,
+    PRIMARY KEY (task_id, field_id)
+);
+
+CREATE INDEX task_field_values_field_id_value_idx ON task_field_values (field_id, value);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS task_comments_search_idx;
+ALTER TABLE task_comments DROP COLUMN IF EXISTS search;
+
+DROP INDEX IF EXISTS teams_search_idx;
+ALTER TABLE teams DROP COLUMN IF EXISTS search;
+
+DROP INDEX IF EXISTS tasks_search_idx;
+ALTER TABLE tasks DROP COLUMN IF EXISTS search;
@@ -0,0 +1,19 @@
+ALTER TABLE tasks ADD COLUMN search tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', COALESCE(name, '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE(content, '')), 'B')
+) STORED;
+
+CREATE INDEX tasks_search_idx ON tasks USING GIN (search);
+
+ALTER TABLE teams ADD COLUMN search tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', COALESCE(name, '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE(description, '')), 'B')
+) STORED;
+
+CREATE INDEX teams_search_idx ON teams USING GIN (search);
+
+ALTER TABLE task_comments ADD COLUMN search tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple', body)
+) STORED;
+
+CREATE INDEX task_comments_search_idx ON task_comments USING GIN (search);
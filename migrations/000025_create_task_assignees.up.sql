@@ -0,0 +1,20 @@
+CREATE TABLE task_assignees (
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    assigned_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX task_assignees_user_id_idx ON task_assignees (user_id);
+
+INSERT INTO task_assignees (task_id, user_id, assigned_at)
+SELECT id, assignee_id, updated_at FROM tasks WHERE assignee_id IS NOT NULL;
+
+CREATE TABLE task_watchers (
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX task_watchers_user_id_idx ON task_watchers (user_id);
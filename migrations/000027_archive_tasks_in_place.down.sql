@@ -0,0 +1,29 @@
+CREATE TABLE tasks_abandoned (
+    task_id INT PRIMARY KEY,
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    snapshot JSONB NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    abandoned_by INT REFERENCES users(id) ON DELETE SET NULL,
+    abandoned_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX tasks_abandoned_team_id_idx ON tasks_abandoned (team_id, abandoned_at DESC);
+
+WITH moved AS (
+    DELETE FROM tasks t
+    WHERE t.archived_at IS NOT NULL
+    RETURNING t.*
+)
+INSERT INTO tasks_abandoned (task_id, team_id, name, snapshot, reason, abandoned_by, abandoned_at)
+SELECT moved.id, moved.team_id, moved.name,
+    to_jsonb(moved) - 'search' - 'archived_at' - 'archive_reason' - 'archived_by',
+    moved.archive_reason, moved.archived_by, moved.archived_at
+FROM moved;
+
+DROP INDEX IF EXISTS tasks_archived_idx;
+
+ALTER TABLE tasks
+    DROP COLUMN archived_by,
+    DROP COLUMN archive_reason,
+    DROP COLUMN archived_at;
@@ -0,0 +1,38 @@
+ALTER TABLE tasks
+    ADD COLUMN archived_at TIMESTAMPTZ,
+    ADD COLUMN archive_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN archived_by INT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX tasks_archived_idx ON tasks (team_id, archived_at DESC) WHERE archived_at IS NOT NULL;
+
+-- tasks archived so far come back from their snapshots, still archived;
+-- references deleted in the meantime are dropped and a status removed
+-- from the workflow falls back to the initial one
+INSERT INTO tasks (id, name, content, status, priority, due_date, assignee_id, created_at, updated_at,
+    team_id, parent_id, rank, sprint_id, story_points, estimate_minutes, recurrence, next_occurrence_id,
+    archived_at, archive_reason, archived_by)
+SELECT r.id, r.name, r.content, r.status, r.priority, r.due_date, r.assignee_id, r.created_at, r.updated_at,
+    r.team_id, r.parent_id, r.rank, r.sprint_id, r.story_points, r.estimate_minutes, r.recurrence, r.next_occurrence_id,
+    a.abandoned_at, a.reason, a.abandoned_by
+FROM tasks_abandoned a, jsonb_populate_record(
+    NULL::tasks,
+    a.snapshot || jsonb_build_object(
+        'status', COALESCE(
+            (SELECT ws.key FROM workflow_statuses ws WHERE ws.team_id = a.team_id AND ws.key = a.snapshot->>'status'),
+            (SELECT ws.key FROM workflow_statuses ws WHERE ws.team_id = a.team_id ORDER BY ws.position LIMIT 1)
+        ),
+        'rank', COALESCE((a.snapshot->>'rank')::bigint, 0),
+        'assignee_id', (SELECT u.id FROM users u WHERE u.id = (a.snapshot->>'assignee_id')::int),
+        'parent_id', (SELECT p.id FROM tasks p WHERE p.id = (a.snapshot->>'parent_id')::int),
+        'sprint_id', (SELECT s.id FROM sprints s WHERE s.id = (a.snapshot->>'sprint_id')::bigint),
+        'next_occurrence_id', (SELECT n.id FROM tasks n WHERE n.id = (a.snapshot->>'next_occurrence_id')::int)
+    )
+) r
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO task_assignees (task_id, user_id)
+SELECT id, assignee_id FROM tasks
+WHERE archived_at IS NOT NULL AND assignee_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+DROP TABLE tasks_abandoned;